@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"github.com/unicorn-sport/backend/internal/config"
 	"github.com/unicorn-sport/backend/internal/middleware"
@@ -16,8 +19,12 @@ import (
 	"github.com/unicorn-sport/backend/internal/modules/matches"
 	"github.com/unicorn-sport/backend/internal/modules/media"
 	"github.com/unicorn-sport/backend/internal/modules/profiles"
+	"github.com/unicorn-sport/backend/internal/modules/schools"
 	"github.com/unicorn-sport/backend/internal/modules/search"
 	"github.com/unicorn-sport/backend/internal/modules/subscriptions"
+	"github.com/unicorn-sport/backend/internal/storage"
+	"github.com/unicorn-sport/backend/internal/uploads"
+	"github.com/unicorn-sport/backend/internal/watermark"
 
 	_ "github.com/unicorn-sport/backend/docs" // swagger docs
 )
@@ -35,6 +42,7 @@ import (
 // @description ## User Types
 // @description - **player**: Football players looking for opportunities
 // @description - **scout**: Scouts looking for talent
+// @description - **school**: Partner school staff confirming their students' enrollment and age
 // @description - **admin**: Platform administrators
 
 // @contact.name Unicorn Sport Support
@@ -64,19 +72,45 @@ func main() {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
+	// Initialize object storage (S3, or local disk when AWS isn't configured)
+	store, err := cfg.InitStorage()
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// Initialize CloudFront (signed URLs for paid video when a key pair is configured)
+	cloudFront, err := cfg.InitCDN()
+	if err != nil {
+		log.Fatalf("Failed to initialize CloudFront: %v", err)
+	}
+
+	// Initialize malware scanner for quarantined uploads (no-op unless SCANNER_DRIVER=clamav)
+	malwareScanner, err := cfg.InitScanner()
+	if err != nil {
+		log.Fatalf("Failed to initialize malware scanner: %v", err)
+	}
+
+	// View tracking: deduplicated, batched view writes and daily rollups
+	viewTracker := cfg.InitViews(db)
+	go viewTracker.Run(context.Background())
+	go viewTracker.RunRollups(context.Background(), time.Duration(cfg.Views.RollupIntervalMinutes)*time.Minute)
+
 	// Initialize modules
 	authModule := auth.NewAuthModule(db, cfg.JWT.Secret, cfg.JWT.AccessTokenTTL, cfg.JWT.RefreshTokenTTL)
-	mediaModule := media.NewMediaModule(db, cfg.AWS.Region, cfg.AWS.AccessKeyID, cfg.AWS.SecretAccessKey, cfg.AWS.S3Bucket, cfg.AWS.CloudFrontURL)
-
-	// Initialize S3 client for matches/highlights/admin/profiles modules
-	s3Client := mediaModule.GetS3Client()
-	profilesModule := profiles.NewProfilesModule(db, s3Client, cfg.AWS.S3Bucket)
-	searchModule := search.NewSearchModule(db, s3Client, cfg.AWS.S3Bucket)
+	uploadService := uploads.NewService(db, store, malwareScanner)
+	watermarkService := watermark.NewService(db)
+	mediaModule := media.NewMediaModule(db, store, uploadService, watermarkService, cloudFront, viewTracker)
+	profilesModule := profiles.NewProfilesModule(db, store, uploadService, viewTracker)
+	searchModule := search.NewSearchModule(db, store)
 	contactModule := contact.NewContactModule(db)
+	schoolsModule := schools.NewSchoolsModule(db)
+
+	adminModule := admin.NewAdminModule(db, store, uploadService, viewTracker)
+	matchesModule := matches.NewModule(db, store, uploadService, watermarkService, cloudFront, viewTracker)
+	highlightsModule := highlights.NewModule(db, store, uploadService, cloudFront, viewTracker)
 
-	adminModule := admin.NewAdminModule(db, s3Client, cfg.AWS.S3Bucket)
-	matchesModule := matches.NewModule(db, s3Client, cfg.AWS.S3Bucket, cfg.AWS.CloudFrontURL)
-	highlightsModule := highlights.NewModule(db, s3Client, cfg.AWS.S3Bucket, cfg.AWS.CloudFrontURL)
+	// Expire abandoned upload sessions and abort their multipart uploads
+	go mediaModule.RunUploadJanitor(context.Background(), time.Duration(cfg.Storage.UploadJanitorIntervalMinutes)*time.Minute)
 
 	// Subscription URLs
 	successURL := os.Getenv("STRIPE_SUCCESS_URL")
@@ -90,7 +124,7 @@ func main() {
 	subscriptionsModule := subscriptions.NewSubscriptionModule(db, cfg.Stripe.SecretKey, cfg.Stripe.WebhookSecret, cfg.Stripe.PriceIDs, successURL, cancelURL)
 
 	// Setup router
-	r := setupRouter(cfg, authModule, adminModule, mediaModule, profilesModule, searchModule, subscriptionsModule, contactModule, matchesModule, highlightsModule)
+	r := setupRouter(cfg, db, store, authModule, adminModule, mediaModule, profilesModule, searchModule, subscriptionsModule, contactModule, matchesModule, highlightsModule, schoolsModule)
 
 	// Start server
 	log.Printf("🚀 Unicorn Sport API starting on port %s", cfg.Port)
@@ -102,6 +136,8 @@ func main() {
 
 func setupRouter(
 	cfg *config.Config,
+	db *gorm.DB,
+	store storage.Store,
 	authModule *auth.AuthModule,
 	adminModule *admin.AdminModule,
 	mediaModule *media.MediaModule,
@@ -111,6 +147,7 @@ func setupRouter(
 	contactModule *contact.ContactModule,
 	matchesModule *matches.Module,
 	highlightsModule *highlights.Module,
+	schoolsModule *schools.SchoolsModule,
 ) *gin.Engine {
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -126,6 +163,14 @@ func setupRouter(
 		c.JSON(http.StatusOK, gin.H{"status": "ok", "version": "1.0.0"})
 	})
 
+	// Local storage serves its own signed upload/download URLs
+	if local, ok := store.(*storage.LocalStore); ok {
+		h := gin.WrapH(local.Handler())
+		r.GET(storage.LocalRoutePrefix+"/*key", h)
+		r.HEAD(storage.LocalRoutePrefix+"/*key", h)
+		r.PUT(storage.LocalRoutePrefix+"/*key", h)
+	}
+
 	// Serve OpenAPI spec (auto-generated by swag)
 	r.GET("/openapi.yaml", func(c *gin.Context) {
 		c.File("docs/swagger.yaml")
@@ -182,9 +227,10 @@ func setupRouter(
 		// ==================
 		// PUBLIC ROUTES
 		// ==================
-		// Players - public listing
-		v1.GET("/players", profilesModule.ListPlayers)
-		v1.GET("/players/featured", profilesModule.GetFeaturedPlayers)
+		// Players - public listing. Optional auth lets the privacy policy show
+		// subscribers more of each player.
+		v1.GET("/players", optionalAuth(cfg.JWT.Secret, profilesModule.ListPlayers))
+		v1.GET("/players/featured", optionalAuth(cfg.JWT.Secret, profilesModule.GetFeaturedPlayers))
 		v1.GET("/players/:id", optionalAuth(cfg.JWT.Secret, profilesModule.GetPlayer))
 
 		// Academies - public listing for filters
@@ -193,25 +239,38 @@ func setupRouter(
 		// Player highlights (FREE - public)
 		v1.GET("/players/:id/highlights", highlightsModule.GetPlayerHighlightsPublic)
 		v1.GET("/players/:id/tournaments", highlightsModule.GetPlayerTournamentAppearances)
-		v1.GET("/highlights/:id", highlightsModule.GetHighlight)
-		v1.GET("/highlights/featured", highlightsModule.ListFeaturedHighlights)
+		v1.GET("/highlights/:id", optionalAuth(cfg.JWT.Secret, highlightsModule.GetHighlight))
+		v1.GET("/highlights/featured", optionalAuth(cfg.JWT.Secret, highlightsModule.ListFeaturedHighlights))
 		v1.GET("/highlight-types", highlightsModule.GetHighlightTypes)
 
 		// Videos - public highlights
-		v1.GET("/videos/highlights", mediaModule.ListHighlights)
+		v1.GET("/videos/highlights", optionalAuth(cfg.JWT.Secret, mediaModule.ListHighlights))
+
+		// Free previews of locked full matches
+		v1.GET("/matches/:id/preview", optionalAuth(cfg.JWT.Secret, matchesModule.GetMatchPreview))
+		v1.GET("/matches/:id/preview/index.m3u8", matchesModule.GetPreviewPlaylist)
+
+		// Watermarked playlists (session ID in the path authorizes the player)
+		v1.GET("/matches/:id/stream/:session_id/index.m3u8", matchesModule.GetWatermarkedPlaylist)
+		v1.GET("/videos/:id/stream/:session_id/index.m3u8", mediaModule.GetWatermarkedPlaylist)
 
 		// Search
-		v1.GET("/search", searchModule.SearchPlayers) // Alias for /search/players
-		v1.GET("/search/players", searchModule.SearchPlayers)
+		v1.GET("/search", optionalAuth(cfg.JWT.Secret, searchModule.SearchPlayers)) // Alias for /search/players
+		v1.GET("/search/players", optionalAuth(cfg.JWT.Secret, searchModule.SearchPlayers))
 		v1.GET("/search/filters", searchModule.GetFilterOptions)
 		v1.GET("/stats", searchModule.GetStats)
 
 		// Public tournament browsing
 		v1.GET("/tournaments", searchModule.GetPublicTournaments)
-		v1.GET("/tournaments/:id", searchModule.GetTournamentDetail)
+		v1.GET("/tournaments/:id", optionalAuth(cfg.JWT.Secret, searchModule.GetTournamentDetail))
+		v1.GET("/tournaments/:id/fixtures", searchModule.GetTournamentFixtures)
+		v1.GET("/tournaments/:id/bracket", searchModule.GetTournamentBracket)
+		v1.GET("/tournaments/:id/standings", searchModule.GetTournamentStandings)
+		v1.GET("/tournaments/:id/teams", searchModule.GetTournamentTeams)
+		v1.GET("/tournaments/:id/leaderboards", optionalAuth(cfg.JWT.Secret, searchModule.GetTournamentLeaderboards))
 
 		// Similar players (public)
-		v1.GET("/players/:id/similar", profilesModule.GetSimilarPlayers)
+		v1.GET("/players/:id/similar", optionalAuth(cfg.JWT.Secret, profilesModule.GetSimilarPlayers))
 
 		// Public contact form (landing page inquiries)
 		v1.POST("/contact", contactModule.SubmitContact)
@@ -226,11 +285,13 @@ func setupRouter(
 		// AUTHENTICATED ROUTES
 		// ==================
 		protected := v1.Group("")
-		protected.Use(middleware.JWTMiddleware(cfg.JWT.Secret))
+		protected.Use(middleware.JWTMiddleware(cfg.JWT.Secret), middleware.PasswordChangeMiddleware(db))
 		{
 			// Video streaming (checks subscription for full matches)
 			protected.GET("/videos/:id/stream", mediaModule.GetVideoStreamURL)
 			protected.GET("/videos/full-matches", mediaModule.ListFullMatches)
+			protected.GET("/matches/:id/stream", matchesModule.StreamMatch)
+			protected.GET("/matches/:id/players/:playerId/moments", matchesModule.GetPlayerMoments)
 
 			// Subscription management
 			protected.GET("/subscriptions/me", subscriptionsModule.GetCurrentSubscription)
@@ -239,8 +300,13 @@ func setupRouter(
 			protected.POST("/subscriptions/portal", subscriptionsModule.CreatePortalSession)
 			protected.POST("/subscriptions/cancel", subscriptionsModule.CancelSubscription)
 
+			// In-app notifications
+			protected.GET("/notifications", authModule.ListNotifications)
+			protected.PUT("/notifications/:id/read", authModule.MarkNotificationRead)
+			protected.PUT("/notifications/read-all", authModule.MarkAllNotificationsRead)
+
 			// ==================
-			// SCOUT FEATURES (Scout+ tier) - Saved players, tags
+			// SCOUT FEATURES (Scout+ tier) - Saved players, tags, match notes
 			// ==================
 			scout := protected.Group("")
 			{
@@ -253,6 +319,17 @@ func setupRouter(
 				scout.GET("/tags", profilesModule.GetMyTags)
 				scout.POST("/tags", profilesModule.CreateTag)
 				scout.DELETE("/tags/:id", profilesModule.DeleteTag)
+
+				// Whether players see who viewed their profile
+				scout.GET("/scout/privacy", profilesModule.GetViewPrivacy)
+				scout.PUT("/scout/privacy", profilesModule.UpdateViewPrivacy)
+
+				// Timestamped notes on match videos
+				scout.GET("/matches/:id/notes", matchesModule.ListMatchNotes)
+				scout.POST("/matches/:id/notes", matchesModule.CreateMatchNote)
+				scout.PUT("/matches/:id/notes/:noteId", matchesModule.UpdateMatchNote)
+				scout.DELETE("/matches/:id/notes/:noteId", matchesModule.DeleteMatchNote)
+				scout.GET("/players/:id/match-notes", matchesModule.GetPlayerMatchNotes)
 			}
 
 			// ==================
@@ -266,6 +343,41 @@ func setupRouter(
 				pro.DELETE("/contact-requests/:id", profilesModule.CancelContactRequest)
 			}
 
+			// ==================
+			// PLAYER ROUTES (player accounts created by admin)
+			// ==================
+			playerRoutes := protected.Group("/player")
+			playerRoutes.Use(middleware.PlayerMiddleware())
+			{
+				playerRoutes.GET("/dashboard", profilesModule.GetPlayerDashboard)
+				playerRoutes.GET("/profile", profilesModule.GetMyProfile)
+
+				// Profile edits go to the admin approval queue
+				playerRoutes.POST("/photo/upload", profilesModule.InitPhotoUpload)
+				playerRoutes.GET("/change-requests", profilesModule.GetMyChangeRequests)
+				playerRoutes.POST("/change-requests", profilesModule.SubmitChangeRequest)
+				playerRoutes.DELETE("/change-requests/:id", profilesModule.CancelChangeRequest)
+
+				// Identity verification goes to the admin review queue
+				playerRoutes.GET("/verification", profilesModule.GetMyVerification)
+				playerRoutes.POST("/verification", profilesModule.SubmitVerification)
+				playerRoutes.POST("/verification/upload", profilesModule.InitDocumentUpload)
+			}
+
+			// ==================
+			// SCHOOL ROUTES (partner school staff accounts created by admin)
+			// ==================
+			schoolRoutes := protected.Group("/school")
+			schoolRoutes.Use(middleware.SchoolMiddleware())
+			{
+				schoolRoutes.GET("", schoolsModule.GetMySchool)
+				schoolRoutes.GET("/students", schoolsModule.ListStudents)
+				schoolRoutes.POST("/roster", schoolsModule.UploadRoster)
+				schoolRoutes.GET("/attestations", schoolsModule.ListAttestations)
+				schoolRoutes.PUT("/attestations/:id/confirm", schoolsModule.ConfirmAttestation)
+				schoolRoutes.PUT("/attestations/:id/dispute", schoolsModule.DisputeAttestation)
+			}
+
 			// ==================
 			// ADMIN ROUTES
 			// ==================
@@ -275,9 +387,12 @@ func setupRouter(
 				// Dashboard stats
 				adminRoutes.GET("/stats", adminModule.GetStats)
 				adminRoutes.GET("/analytics", adminModule.GetAnalytics)
+				adminRoutes.GET("/analytics/views", adminModule.GetViewAnalytics)
 
 				// Audit logs
 				adminRoutes.GET("/audit-logs", adminModule.ListAuditLogs)
+				adminRoutes.GET("/minor-access", adminModule.ListMinorAccessLogs)
+				adminRoutes.GET("/minor-access/anomalies", adminModule.GetMinorAccessAnomalies)
 
 				// Settings
 				adminRoutes.GET("/settings", adminModule.GetSettings)
@@ -294,23 +409,74 @@ func setupRouter(
 				adminRoutes.PUT("/academies/:id", adminModule.UpdateAcademy)
 				adminRoutes.DELETE("/academies/:id", adminModule.DeleteAcademy)
 
+				// Partner schools
+				adminRoutes.GET("/schools", adminModule.ListSchools)
+				adminRoutes.POST("/schools", adminModule.CreateSchool)
+				adminRoutes.GET("/schools/:id", adminModule.GetSchool)
+				adminRoutes.PUT("/schools/:id", adminModule.UpdateSchool)
+				adminRoutes.POST("/schools/:id/admins", adminModule.AddSchoolAdmin)
+				adminRoutes.DELETE("/schools/:id/admins/:userId", adminModule.RemoveSchoolAdmin)
+				adminRoutes.GET("/school-attestations", adminModule.ListSchoolAttestations)
+				adminRoutes.POST("/players/:id/school-attestation", adminModule.RequestSchoolAttestation)
+
 				// Contact request management
 				adminRoutes.GET("/contact-requests", adminModule.ListContactRequests)
 				adminRoutes.PUT("/contact-requests/:id/approve", adminModule.ApproveContactRequest)
 				adminRoutes.PUT("/contact-requests/:id/reject", adminModule.RejectContactRequest)
 
+				// Profile edits submitted by players
+				adminRoutes.GET("/player-change-requests", adminModule.ListPlayerChangeRequests)
+				adminRoutes.PUT("/player-change-requests/:id/approve", adminModule.ApprovePlayerChangeRequest)
+				adminRoutes.PUT("/player-change-requests/:id/reject", adminModule.RejectPlayerChangeRequest)
+
+				// Player verification queue
+				adminRoutes.GET("/verifications", adminModule.ListVerifications)
+				adminRoutes.GET("/verifications/:id", adminModule.GetVerification)
+				adminRoutes.POST("/verifications/:id/claim", adminModule.ClaimVerification)
+				adminRoutes.POST("/verifications/:id/release", adminModule.ReleaseVerification)
+				adminRoutes.PUT("/verifications/:id/approve", adminModule.ApproveVerification)
+				adminRoutes.PUT("/verifications/:id/reject", adminModule.RejectVerification)
+				adminRoutes.PUT("/verifications/:id/request-info", adminModule.RequestVerificationInfo)
+				adminRoutes.POST("/players/:id/verifications", adminModule.SubmitPlayerVerification)
+				adminRoutes.GET("/players/:id/verification-history", adminModule.GetPlayerVerificationHistory)
+
+				// Automated age-data checks
+				adminRoutes.GET("/player-flags", adminModule.ListPlayerFlags)
+				adminRoutes.PUT("/player-flags/:id/dismiss", adminModule.DismissPlayerFlag)
+				adminRoutes.POST("/player-flags/scan", adminModule.ScanPlayerFlags)
+
+				// Duplicate players and merges
+				adminRoutes.GET("/player-duplicates", adminModule.ListDuplicateCandidates)
+				adminRoutes.POST("/player-duplicates/dismiss", adminModule.DismissDuplicateCandidate)
+				adminRoutes.GET("/player-merges", adminModule.ListPlayerMerges)
+				adminRoutes.POST("/player-merges", adminModule.MergePlayers)
+				adminRoutes.POST("/player-merges/:id/undo", adminModule.UndoPlayerMerge)
+
 				// Player management
 				adminRoutes.GET("/players", adminModule.ListPlayers)
 				adminRoutes.POST("/players", adminModule.CreatePlayer)
 				adminRoutes.POST("/players/bulk", adminModule.BulkUpdatePlayers)
+				adminRoutes.POST("/players/import", adminModule.ImportPlayers)
 				adminRoutes.GET("/players/:id", adminModule.GetPlayer)
 				adminRoutes.PUT("/players/:id", adminModule.UpdatePlayer)
 				adminRoutes.DELETE("/players/:id", adminModule.DeletePlayer)
 
+				// Guardians and consent (minors are only published with consent)
+				adminRoutes.GET("/players/:id/consent", adminModule.GetPlayerConsent)
+				adminRoutes.POST("/players/:id/guardians", adminModule.AddPlayerGuardian)
+				adminRoutes.DELETE("/players/:id/guardians/:guardianId", adminModule.RemovePlayerGuardian)
+				adminRoutes.PUT("/guardians/:id", adminModule.UpdateGuardian)
+				adminRoutes.POST("/players/:id/consents", adminModule.RecordConsent)
+				adminRoutes.POST("/consents/:id/withdraw", adminModule.WithdrawConsent)
+
 				// Tournament management
 				adminRoutes.GET("/events", adminModule.ListTournaments)
 				adminRoutes.POST("/events", adminModule.CreateTournament)
 				adminRoutes.PUT("/events/:id", adminModule.UpdateTournament)
+				adminRoutes.GET("/events/:id/roster", adminModule.GetTournamentRoster)
+				adminRoutes.POST("/events/:id/registrations", adminModule.RegisterTournamentPlayer)
+				adminRoutes.PUT("/tournament-registrations/:id", adminModule.UpdateTournamentRegistration)
+				adminRoutes.DELETE("/tournament-registrations/:id", adminModule.DeleteTournamentRegistration)
 
 				// Video management
 				adminRoutes.GET("/videos", mediaModule.ListVideos)
@@ -322,12 +488,15 @@ func setupRouter(
 				adminRoutes.POST("/videos/:id/players", mediaModule.LinkPlayerToVideo)
 				adminRoutes.POST("/videos/:id/approve", mediaModule.ApproveVideo)
 				adminRoutes.POST("/videos/:id/reject", mediaModule.RejectVideo)
+				adminRoutes.PUT("/videos/:id/watermark", mediaModule.SetWatermarkRenditions)
 
 				// Upload workflow
 				adminRoutes.POST("/upload/init", mediaModule.InitUpload)
+				adminRoutes.POST("/upload/complete", mediaModule.CompleteUpload)
 				adminRoutes.POST("/upload/multipart/init", mediaModule.InitMultipart)
 				adminRoutes.POST("/upload/multipart/part-url", mediaModule.GetUploadPartURL)
 				adminRoutes.POST("/upload/multipart/complete", mediaModule.CompleteMultipart)
+				adminRoutes.GET("/upload/:session_id/parts", mediaModule.ListUploadParts)
 
 				// Legacy upload endpoints (for backward compatibility)
 				adminRoutes.POST("/videos/upload", mediaModule.GetUploadURL)
@@ -340,6 +509,18 @@ func setupRouter(
 				adminRoutes.GET("/tournaments/:tournamentId/matches", matchesModule.ListMatches)
 				adminRoutes.POST("/tournaments/:tournamentId/matches", matchesModule.CreateMatch)
 
+				// Tournament structure: teams, groups and generated fixtures
+				adminRoutes.GET("/tournaments/:tournamentId/teams", matchesModule.ListTeams)
+				adminRoutes.POST("/tournaments/:tournamentId/teams", matchesModule.CreateTeam)
+				adminRoutes.PUT("/teams/:id", matchesModule.UpdateTeam)
+				adminRoutes.DELETE("/teams/:id", matchesModule.DeleteTeam)
+				adminRoutes.GET("/tournaments/:tournamentId/groups", matchesModule.ListGroups)
+				adminRoutes.POST("/tournaments/:tournamentId/groups", matchesModule.CreateGroup)
+				adminRoutes.PUT("/tournament-groups/:id", matchesModule.UpdateGroup)
+				adminRoutes.DELETE("/tournament-groups/:id", matchesModule.DeleteGroup)
+				adminRoutes.POST("/tournaments/:tournamentId/fixtures/generate", matchesModule.GenerateGroupFixtures)
+				adminRoutes.POST("/tournaments/:tournamentId/bracket/generate", matchesModule.GenerateBracket)
+
 				// Match CRUD
 				adminRoutes.GET("/matches/:id", matchesModule.GetMatch)
 				adminRoutes.PUT("/matches/:id", matchesModule.UpdateMatch)
@@ -350,10 +531,20 @@ func setupRouter(
 				adminRoutes.PUT("/matches/:id/players/:playerId", matchesModule.UpdateMatchPlayer)
 				adminRoutes.DELETE("/matches/:id/players/:playerId", matchesModule.RemovePlayerFromMatch)
 
+				// Player moments in the match video ("watch only this player")
+				adminRoutes.GET("/matches/:id/moments", matchesModule.ListMatchMoments)
+				adminRoutes.POST("/matches/:id/moments", matchesModule.CreateMatchMoments)
+				adminRoutes.POST("/matches/:id/moments/import", matchesModule.ImportMatchMoments)
+				adminRoutes.PUT("/matches/:id/moments/:momentId", matchesModule.UpdateMatchMoment)
+				adminRoutes.DELETE("/matches/:id/moments/:momentId", matchesModule.DeleteMatchMoment)
+
 				// Match video (full match - PAID content)
 				adminRoutes.POST("/matches/:id/video/upload", matchesModule.InitMatchVideoUpload)
 				adminRoutes.POST("/matches/:id/video", matchesModule.SaveMatchVideo)
 				adminRoutes.DELETE("/matches/:id/video", matchesModule.DeleteMatchVideo)
+				adminRoutes.PUT("/matches/:id/video/watermark", matchesModule.SetWatermarkRenditions)
+				adminRoutes.PUT("/matches/:id/video/preview", matchesModule.UpdatePreviewSettings)
+				adminRoutes.POST("/watermarks/lookup", matchesModule.LookupWatermark)
 
 				// Match video thumbnail
 				adminRoutes.POST("/matches/:id/video/thumbnail/upload", matchesModule.InitThumbnailUpload)
@@ -373,6 +564,12 @@ func setupRouter(
 				adminRoutes.POST("/highlights/:id/thumbnail/upload", highlightsModule.InitThumbnailUpload)
 				adminRoutes.PUT("/highlights/:id/thumbnail", highlightsModule.UpdateThumbnail)
 
+				// Bulk highlight ingest from a manifest
+				adminRoutes.POST("/highlights/ingests", highlightsModule.StartHighlightIngest)
+				adminRoutes.GET("/highlights/ingests", highlightsModule.ListHighlightIngests)
+				adminRoutes.GET("/highlights/ingests/:id", highlightsModule.GetHighlightIngest)
+				adminRoutes.POST("/highlights/ingests/:id/complete", highlightsModule.CompleteHighlightIngest)
+
 				// Highlight CRUD
 				adminRoutes.GET("/players/:id/highlights", highlightsModule.ListPlayerHighlights)
 				adminRoutes.GET("/matches/:id/highlights", highlightsModule.ListMatchHighlights)
@@ -0,0 +1,165 @@
+package access
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/consent"
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Actions recorded in the minor access log
+const (
+	ActionViewProfile         = "view_profile"
+	ActionStreamMatch         = "stream_match" // full match playback started
+	ActionViewMoments         = "view_moments" // "watch only this player" playlist
+	ActionContactRequest      = "contact_request"
+	ActionViewContactRequests = "view_contact_requests"
+	ActionExport              = "export"
+)
+
+// Actions lists the recorded actions
+var Actions = []string{ActionViewProfile, ActionStreamMatch, ActionViewMoments, ActionContactRequest, ActionViewContactRequests, ActionExport}
+
+// Record logs the requester's access to whichever of players are minors. Reads by
+// admins, by players of their own data and by anonymous visitors aren't logged;
+// exports are logged for everyone. Failures are logged and never block the request.
+func Record(db *gorm.DB, c *gin.Context, action, resourceType string, resourceID *uuid.UUID, players ...domain.Player) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		return
+	}
+	uid := userID.(uuid.UUID)
+	role, _ := c.Get("user_role")
+	roleName, _ := role.(string)
+	if roleName == "admin" && action != ActionExport {
+		return
+	}
+
+	ip := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+	now := time.Now()
+
+	var entries []domain.MinorAccessLog
+	for i := range players {
+		p := &players[i]
+		if !consent.IsMinor(p) || (p.UserID != nil && *p.UserID == uid) {
+			continue
+		}
+		entry := domain.MinorAccessLog{
+			UserID:       uid,
+			UserRole:     roleName,
+			PlayerID:     p.ID,
+			Action:       action,
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			IPAddress:    &ip,
+			CreatedAt:    now,
+		}
+		if userAgent != "" {
+			entry.UserAgent = &userAgent
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return
+	}
+	if err := db.CreateInBatches(&entries, 500).Error; err != nil {
+		log.Printf("access: failed to record %d %s entries: %v", len(entries), action, err)
+	}
+}
+
+// RecordQuery logs access to the players whose IDs the subquery selects, e.g. a
+// match lineup or the players tagged in a video
+func RecordQuery(db *gorm.DB, c *gin.Context, action, resourceType string, resourceID *uuid.UUID, playerIDs *gorm.DB) {
+	if _, ok := c.Get("user_id"); !ok {
+		return
+	}
+	var players []domain.Player
+	if err := db.Select("id, user_id, date_of_birth").Where("id IN (?)", playerIDs).Find(&players).Error; err != nil {
+		log.Printf("access: failed to load players for %s: %v", action, err)
+		return
+	}
+	Record(db, c, action, resourceType, resourceID, players...)
+}
+
+// Anomaly flags
+const (
+	FlagHighVolume = "high_volume" // more distinct minors in the window than the threshold
+	FlagSpike      = "spike"       // far above the account's own usual rate
+)
+
+// An account is spiking at several times its baseline (its rate over the
+// baselineDays before the window), and only past a handful of minors
+const (
+	spikeFactor  = 3.0
+	spikeMinimum = 10
+	baselineDays = 30
+)
+
+// Anomaly is an account whose access to minors' data stands out in a window
+type Anomaly struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	UserRole       string    `json:"user_role"`
+	DistinctMinors int       `json:"distinct_minors"`
+	Accesses       int       `json:"accesses"`
+	Expected       float64   `json:"expected"` // distinct minors the baseline predicts for the window
+	LastAccessAt   time.Time `json:"last_access_at"`
+	Flags          []string  `json:"flags" gorm:"-"`
+}
+
+const anomaliesSQL = `
+WITH recent AS (
+    SELECT user_id, MAX(user_role) AS user_role, COUNT(DISTINCT player_id) AS distinct_minors,
+           COUNT(*) AS accesses, MAX(created_at) AS last_access_at
+    FROM minor_access_logs
+    WHERE created_at >= ? AND user_role <> 'admin'
+    GROUP BY user_id
+), baseline AS (
+    SELECT user_id, COUNT(DISTINCT (player_id, DATE(created_at)))::float / ? AS daily
+    FROM minor_access_logs
+    WHERE created_at >= ? AND created_at < ? AND user_role <> 'admin'
+    GROUP BY user_id
+)
+SELECT r.user_id, u.email, r.user_role, r.distinct_minors, r.accesses, r.last_access_at,
+       COALESCE(b.daily, 0) * ? AS expected
+FROM recent r
+JOIN users u ON u.id = r.user_id
+LEFT JOIN baseline b ON b.user_id = r.user_id
+ORDER BY r.distinct_minors DESC`
+
+// Anomalies returns the non-admin accounts that viewed unusually many minors since
+// since: more distinct minors than threshold, or a spike against their own rate
+// over the previous 30 days
+func Anomalies(ctx context.Context, db *gorm.DB, since time.Time, threshold int) ([]Anomaly, error) {
+	windowDays := time.Since(since).Hours() / 24
+	if windowDays < 1 {
+		windowDays = 1
+	}
+	baselineFrom := since.AddDate(0, 0, -baselineDays)
+
+	var rows []Anomaly
+	if err := db.WithContext(ctx).Raw(anomaliesSQL, since, float64(baselineDays), baselineFrom, since, windowDays).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	flagged := make([]Anomaly, 0)
+	for _, a := range rows {
+		if a.DistinctMinors > threshold {
+			a.Flags = append(a.Flags, FlagHighVolume)
+		}
+		if a.DistinctMinors >= spikeMinimum && float64(a.DistinctMinors) > spikeFactor*a.Expected {
+			a.Flags = append(a.Flags, FlagSpike)
+		}
+		if len(a.Flags) > 0 {
+			flagged = append(flagged, a)
+		}
+	}
+	return flagged, nil
+}
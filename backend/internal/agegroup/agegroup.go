@@ -0,0 +1,79 @@
+package agegroup
+
+import (
+	"strings"
+	"time"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Group is a youth age group: players must be under Limit on the competition's
+// cut-off date, so a U17 player is 16 or younger on that day
+type Group struct {
+	Name  string `json:"name"`
+	Limit int    `json:"limit"`
+}
+
+// Groups are the supported age groups, youngest first
+var Groups = []Group{
+	{Name: "U13", Limit: 13},
+	{Name: "U15", Limit: 15},
+	{Name: "U17", Limit: 17},
+	{Name: "U20", Limit: 20},
+}
+
+// Names lists the group names, youngest first
+func Names() []string {
+	names := make([]string, len(Groups))
+	for i, g := range Groups {
+		names[i] = g.Name
+	}
+	return names
+}
+
+// Parse finds a group by name, case-insensitively
+func Parse(name string) (Group, bool) {
+	for _, g := range Groups {
+		if strings.EqualFold(g.Name, strings.TrimSpace(name)) {
+			return g, true
+		}
+	}
+	return Group{}, false
+}
+
+// Cutoff returns the date ages are taken on for a competition: its own cut-off if
+// set, otherwise 1 January of its year as in FIFA and CAF youth competitions
+func Cutoff(year int, override *time.Time) time.Time {
+	if override != nil && !override.IsZero() {
+		y, m, d := override.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// TournamentCutoff returns the tournament's cut-off date
+func TournamentCutoff(t *domain.Tournament) time.Time {
+	return Cutoff(t.Year, t.AgeCutoffDate)
+}
+
+// Eligible reports whether a player born on dob can play in the group
+func (g Group) Eligible(dob, cutoff time.Time) bool {
+	return domain.AgeAt(dob, cutoff) < g.Limit
+}
+
+// BornAfter returns the date eligible players must be born after. Anyone born on
+// or before it has reached the limit by the cut-off.
+func (g Group) BornAfter(cutoff time.Time) time.Time {
+	return cutoff.AddDate(-g.Limit, 0, 0)
+}
+
+// Of returns the youngest group a player born on dob is eligible for, or "" if
+// they are too old for all of them
+func Of(dob, cutoff time.Time) string {
+	for _, g := range Groups {
+		if g.Eligible(dob, cutoff) {
+			return g.Name
+		}
+	}
+	return ""
+}
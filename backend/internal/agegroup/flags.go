@@ -0,0 +1,302 @@
+package agegroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/duplicates"
+	"github.com/unicorn-sport/backend/internal/verification"
+)
+
+// Flag types
+const (
+	FlagDOBMismatch       = "dob_mismatch"       // the school's records have another date of birth
+	FlagImplausibleHeight = "implausible_height" // height far outside the range for the age
+	FlagPossibleDuplicate = "possible_duplicate" // near-identical name with a different date of birth
+)
+
+// FlagTypes lists the flag types
+var FlagTypes = []string{FlagDOBMismatch, FlagImplausibleHeight, FlagPossibleDuplicate}
+
+// Flag statuses
+const (
+	FlagOpen      = "open"
+	FlagDismissed = "dismissed" // reviewed by an admin and left as is; never reopened
+	FlagCleared   = "cleared"   // the data was corrected
+)
+
+// Heights in cm outside these ranges are flagged. They are deliberately wide: the
+// aim is to catch typos and players who are clearly older than their stated age,
+// not to second-guess late or early developers.
+var heightRanges = []struct{ maxAge, min, max int }{
+	{9, 105, 160},
+	{10, 115, 168},
+	{11, 118, 175},
+	{12, 122, 182},
+	{13, 127, 190},
+	{14, 132, 197},
+	{15, 138, 203},
+	{16, 142, 207},
+	{17, 145, 210},
+}
+
+const adultMinHeight, adultMaxHeight = 145, 215
+
+func plausibleHeight(age int) (min, max int) {
+	for _, r := range heightRanges {
+		if age <= r.maxAge {
+			return r.min, r.max
+		}
+	}
+	return adultMinHeight, adultMaxHeight
+}
+
+type finding struct {
+	Type    string
+	Related *uuid.UUID
+	Details string
+}
+
+// Recheck runs Check for each player. Failures are logged and never fail the
+// action that changed the players.
+func Recheck(db *gorm.DB, playerIDs ...uuid.UUID) {
+	for _, id := range playerIDs {
+		if err := Check(db, id); err != nil {
+			log.Printf("agegroup: failed to check player %s: %v", id, err)
+		}
+	}
+}
+
+// Check re-runs the age checks for one player, opening flags for new problems and
+// clearing open flags whose problem has been fixed. A deleted player's flags are
+// cleared.
+func Check(db *gorm.DB, playerID uuid.UUID) error {
+	var p domain.Player
+	err := db.Where("deleted_at IS NULL").First(&p, "id = ?", playerID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return sync(db, playerID, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	var findings []finding
+	mismatch, err := dobMismatch(db, &p)
+	if err != nil {
+		return err
+	}
+	if mismatch != nil {
+		findings = append(findings, *mismatch)
+	}
+	if height := implausibleHeight(&p); height != nil {
+		findings = append(findings, *height)
+	}
+	namesakes, err := possibleDuplicates(db, &p)
+	if err != nil {
+		return err
+	}
+	findings = append(findings, namesakes...)
+
+	return sync(db, p.ID, findings)
+}
+
+// Scan re-checks every player, e.g. after a bulk import. It runs one check per
+// player, so it takes a while on a large database.
+func Scan(ctx context.Context, db *gorm.DB) (int, error) {
+	db = db.WithContext(ctx)
+	now := time.Now()
+	deleted := db.Model(&domain.Player{}).Select("id").Where("deleted_at IS NOT NULL")
+	if err := db.Model(&domain.PlayerFlag{}).
+		Where("status = ? AND (player_id IN (?) OR related_player_id IN (?))", FlagOpen, deleted, deleted).
+		Updates(map[string]interface{}{"status": FlagCleared, "resolved_at": now, "updated_at": now}).Error; err != nil {
+		return 0, err
+	}
+
+	var ids []uuid.UUID
+	if err := db.Model(&domain.Player{}).Where("deleted_at IS NULL").Order("created_at").Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	checked := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return checked, err
+		}
+		if err := Check(db, id); err != nil {
+			return checked, err
+		}
+		checked++
+	}
+	return checked, nil
+}
+
+// dobMismatch compares the date of birth against the one on the school's records
+// in its latest answer to an attestation
+func dobMismatch(db *gorm.DB, p *domain.Player) (*finding, error) {
+	var a domain.SchoolAttestation
+	err := db.Preload("School").
+		Where("player_id = ? AND status IN ? AND reported_date_of_birth IS NOT NULL", p.ID,
+			[]string{verification.AttestationConfirmed, verification.AttestationDisputed}).
+		Order("attested_at DESC").First(&a).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if sameDay(*a.ReportedDateOfBirth, p.DateOfBirth) {
+		return nil, nil
+	}
+	school := "The school"
+	if a.School != nil {
+		school = a.School.Name
+	}
+	return &finding{
+		Type: FlagDOBMismatch,
+		Details: fmt.Sprintf("%s has %s on record; the profile says %s",
+			school, a.ReportedDateOfBirth.Format("2006-01-02"), p.DateOfBirth.Format("2006-01-02")),
+	}, nil
+}
+
+func implausibleHeight(p *domain.Player) *finding {
+	if p.HeightCm == nil || *p.HeightCm <= 0 {
+		return nil
+	}
+	age := p.GetAge()
+	min, max := plausibleHeight(age)
+	if *p.HeightCm >= min && *p.HeightCm <= max {
+		return nil
+	}
+	return &finding{
+		Type:    FlagImplausibleHeight,
+		Details: fmt.Sprintf("%d cm is outside %d–%d cm for age %d", *p.HeightCm, min, max, age),
+	}
+}
+
+// possibleDuplicates finds players in the same country whose name is within a
+// typo or two of this one, in either order, but who have a different date of
+// birth: the same person registered twice under different ages
+func possibleDuplicates(db *gorm.DB, p *domain.Player) ([]finding, error) {
+	first, last := duplicates.NormalizeName(p.FirstName), duplicates.NormalizeName(p.LastName)
+	if first == "" || last == "" {
+		return nil, nil
+	}
+	// Candidates share initials, in either order, which keeps the query on an index-friendly prefix
+	initials := []string{string([]rune(first)[:1]), string([]rune(last)[:1])}
+
+	var candidates []domain.Player
+	if err := db.Select("id, first_name, last_name, date_of_birth").
+		Where("id <> ? AND deleted_at IS NULL AND country = ? AND date_of_birth <> ?", p.ID, p.Country, p.DateOfBirth).
+		Where("LOWER(LEFT(first_name, 1)) IN ? AND LOWER(LEFT(last_name, 1)) IN ?", initials, initials).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	var findings []finding
+	for i := range candidates {
+		other := &candidates[i]
+		if _, similar := duplicates.NameMatch(p.FirstName, p.LastName, other.FirstName, other.LastName); !similar {
+			continue
+		}
+		findings = append(findings, finding{
+			Type:    FlagPossibleDuplicate,
+			Related: &other.ID,
+			Details: fmt.Sprintf("%s %s (born %s) and %s %s (born %s) have near-identical names",
+				p.FirstName, p.LastName, p.DateOfBirth.Format("2006-01-02"),
+				other.FirstName, other.LastName, other.DateOfBirth.Format("2006-01-02")),
+		})
+	}
+	return findings, nil
+}
+
+// sync opens, reopens and clears the player's flags to match the findings. A
+// duplicate flag covers the pair, so it may be stored on the other player.
+func sync(db *gorm.DB, playerID uuid.UUID, findings []finding) error {
+	var existing []domain.PlayerFlag
+	if err := db.Where("player_id = ? OR (type = ? AND related_player_id = ?)", playerID, FlagPossibleDuplicate, playerID).
+		Find(&existing).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return db.Transaction(func(tx *gorm.DB) error {
+		matched := map[uuid.UUID]bool{}
+		for _, f := range findings {
+			flag := matchFlag(existing, playerID, f)
+			switch {
+			case flag == nil:
+				if err := tx.Create(&domain.PlayerFlag{
+					PlayerID:        playerID,
+					Type:            f.Type,
+					RelatedPlayerID: f.Related,
+					Details:         f.Details,
+					Status:          FlagOpen,
+					CreatedAt:       now,
+					UpdatedAt:       now,
+				}).Error; err != nil {
+					return err
+				}
+				continue
+			case flag.Status == FlagCleared:
+				if err := tx.Model(flag).Updates(map[string]interface{}{
+					"status": FlagOpen, "details": f.Details, "resolved_by": nil, "resolved_at": nil, "note": nil, "updated_at": now,
+				}).Error; err != nil {
+					return err
+				}
+			case flag.Status == FlagOpen && flag.Details != f.Details:
+				if err := tx.Model(flag).Updates(map[string]interface{}{"details": f.Details, "updated_at": now}).Error; err != nil {
+					return err
+				}
+			}
+			matched[flag.ID] = true
+		}
+
+		for i := range existing {
+			flag := &existing[i]
+			if flag.Status != FlagOpen || matched[flag.ID] {
+				continue
+			}
+			if err := tx.Model(flag).Updates(map[string]interface{}{
+				"status": FlagCleared, "resolved_at": now, "updated_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func matchFlag(existing []domain.PlayerFlag, playerID uuid.UUID, f finding) *domain.PlayerFlag {
+	for i := range existing {
+		e := &existing[i]
+		if e.Type != f.Type {
+			continue
+		}
+		if f.Related == nil {
+			if e.PlayerID == playerID && e.RelatedPlayerID == nil {
+				return e
+			}
+			continue
+		}
+		if e.RelatedPlayerID == nil {
+			continue
+		}
+		if (e.PlayerID == playerID && *e.RelatedPlayerID == *f.Related) ||
+			(e.PlayerID == *f.Related && *e.RelatedPlayerID == playerID) {
+			return e
+		}
+	}
+	return nil
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
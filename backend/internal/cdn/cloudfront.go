@@ -0,0 +1,317 @@
+package cdn
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Signer signs CloudFront URLs and cookies with a CloudFront key pair (trusted key
+// group public key + matching RSA private key)
+type Signer struct {
+	keyPairID string
+	key       *rsa.PrivateKey
+	now       func() time.Time
+}
+
+// NewSigner creates a signer for the given key pair ID and RSA private key
+func NewSigner(keyPairID string, key *rsa.PrivateKey) *Signer {
+	return &Signer{keyPairID: keyPairID, key: key, now: time.Now}
+}
+
+// ParsePrivateKey decodes a PEM encoded RSA private key (PKCS#1 or PKCS#8)
+func ParsePrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("cloudfront: no PEM block found in private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cloudfront: failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("cloudfront: private key is not RSA")
+	}
+	return key, nil
+}
+
+// Policy describes who may fetch a resource and until when. A policy with only
+// Resource and Expires is signed as a canned policy; setting Starts or IPAddress
+// (or using a wildcard resource) makes it a custom policy.
+type Policy struct {
+	Resource  string    // URL, may contain * wildcards (custom policies only)
+	Expires   time.Time // required
+	Starts    time.Time // optional "not before"
+	IPAddress string    // optional client IP or CIDR
+}
+
+// IsCanned reports whether the policy can be expressed as a canned policy
+func (p Policy) IsCanned() bool {
+	return p.Starts.IsZero() && p.IPAddress == "" && !strings.Contains(p.Resource, "*")
+}
+
+type policyDocument struct {
+	Statement []policyStatement `json:"Statement"`
+}
+
+type policyStatement struct {
+	Resource  string          `json:"Resource"`
+	Condition policyCondition `json:"Condition"`
+}
+
+type policyCondition struct {
+	DateLessThan    epochTime  `json:"DateLessThan"`
+	IPAddress       *sourceIP  `json:"IpAddress,omitempty"`
+	DateGreaterThan *epochTime `json:"DateGreaterThan,omitempty"`
+}
+
+type epochTime struct {
+	EpochTime int64 `json:"AWS:EpochTime"`
+}
+
+type sourceIP struct {
+	SourceIP string `json:"AWS:SourceIp"`
+}
+
+// JSON renders the policy document exactly as CloudFront expects it to be signed
+func (p Policy) JSON() ([]byte, error) {
+	if p.Resource == "" {
+		return nil, errors.New("cloudfront: policy resource is required")
+	}
+	if p.Expires.IsZero() {
+		return nil, errors.New("cloudfront: policy expiry is required")
+	}
+
+	cond := policyCondition{DateLessThan: epochTime{EpochTime: p.Expires.Unix()}}
+	if p.IPAddress != "" {
+		cidr, err := toCIDR(p.IPAddress)
+		if err != nil {
+			return nil, err
+		}
+		cond.IPAddress = &sourceIP{SourceIP: cidr}
+	}
+	if !p.Starts.IsZero() {
+		cond.DateGreaterThan = &epochTime{EpochTime: p.Starts.Unix()}
+	}
+
+	var buf strings.Builder
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(policyDocument{Statement: []policyStatement{{Resource: p.Resource, Condition: cond}}}); err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimSuffix(buf.String(), "\n")), nil
+}
+
+// toCIDR turns a bare IP into a single-host CIDR block
+func toCIDR(ip string) (string, error) {
+	if strings.Contains(ip, "/") {
+		if _, _, err := net.ParseCIDR(ip); err != nil {
+			return "", fmt.Errorf("cloudfront: invalid IP range %q", ip)
+		}
+		return ip, nil
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", fmt.Errorf("cloudfront: invalid IP address %q", ip)
+	}
+	if parsed.To4() != nil {
+		return parsed.String() + "/32", nil
+	}
+	return parsed.String() + "/128", nil
+}
+
+// SignURL signs rawURL with the policy. Resource defaults to the (escaped) URL. Canned
+// policies add Expires/Signature/Key-Pair-Id, custom ones Policy/Signature/Key-Pair-Id.
+func (s *Signer) SignURL(rawURL string, p Policy) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("cloudfront: invalid URL: %w", err)
+	}
+	if p.Resource == "" {
+		p.Resource = u.String()
+	}
+
+	doc, err := p.JSON()
+	if err != nil {
+		return "", err
+	}
+	sig, err := s.sign(doc)
+	if err != nil {
+		return "", err
+	}
+
+	// CloudFront requires these parameters last and in this order, so they're
+	// appended rather than re-encoded with url.Values
+	var params string
+	if p.IsCanned() {
+		params = fmt.Sprintf("Expires=%d&Signature=%s&Key-Pair-Id=%s", p.Expires.Unix(), sig, s.keyPairID)
+	} else {
+		params = fmt.Sprintf("Policy=%s&Signature=%s&Key-Pair-Id=%s", encode(doc), sig, s.keyPairID)
+	}
+	if u.RawQuery != "" {
+		u.RawQuery += "&" + params
+	} else {
+		u.RawQuery = params
+	}
+	return u.String(), nil
+}
+
+// SignedCookies returns the CloudFront-* cookies granting access to the policy's
+// resource. Cookies always use a custom policy so wildcard resources (e.g. every
+// segment of an HLS stream) can be covered.
+func (s *Signer) SignedCookies(p Policy, domain, cookiePath string) ([]*http.Cookie, error) {
+	doc, err := p.JSON()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := s.sign(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if cookiePath == "" {
+		cookiePath = "/"
+	}
+	values := []struct{ name, value string }{
+		{"CloudFront-Policy", encode(doc)},
+		{"CloudFront-Signature", sig},
+		{"CloudFront-Key-Pair-Id", s.keyPairID},
+	}
+	cookies := make([]*http.Cookie, 0, len(values))
+	for _, v := range values {
+		cookies = append(cookies, &http.Cookie{
+			Name:     v.name,
+			Value:    v.value,
+			Domain:   domain,
+			Path:     cookiePath,
+			Expires:  p.Expires,
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteNoneMode,
+		})
+	}
+	return cookies, nil
+}
+
+// sign returns the URL-safe RSA-SHA1 signature CloudFront verifies
+func (s *Signer) sign(doc []byte) (string, error) {
+	h := sha1.Sum(doc)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA1, h[:])
+	if err != nil {
+		return "", fmt.Errorf("cloudfront: failed to sign policy: %w", err)
+	}
+	return encode(sig), nil
+}
+
+// encode is base64 with CloudFront's URL-safe substitutions (+ -> -, = -> _, / -> ~)
+func encode(b []byte) string {
+	return strings.NewReplacer("+", "-", "=", "_", "/", "~").Replace(base64.StdEncoding.EncodeToString(b))
+}
+
+// ==================== DISTRIBUTION ====================
+
+// CloudFront builds URLs for objects served through a CloudFront distribution.
+// Free assets get plain URLs; paid assets get short-lived signed URLs (and signed
+// cookies for HLS, whose segments are fetched by the player without our query string).
+// A nil *CloudFront means no CDN is configured.
+type CloudFront struct {
+	baseURL      string
+	signer       *Signer
+	ttl          time.Duration
+	bindIP       bool
+	cookieDomain string
+}
+
+// NewCloudFront creates a distribution helper. signer may be nil, in which case
+// only unsigned URLs are available. When bindIP is set, signed URLs and cookies
+// only work from the requesting client's IP.
+func NewCloudFront(baseURL string, signer *Signer, ttl time.Duration, bindIP bool, cookieDomain string) *CloudFront {
+	if baseURL == "" {
+		return nil
+	}
+	return &CloudFront{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		signer:       signer,
+		ttl:          ttl,
+		bindIP:       bindIP,
+		cookieDomain: cookieDomain,
+	}
+}
+
+// Enabled reports whether a CDN is configured
+func (cf *CloudFront) Enabled() bool {
+	return cf != nil
+}
+
+// CanSign reports whether paid assets can be served through the CDN
+func (cf *CloudFront) CanSign() bool {
+	return cf != nil && cf.signer != nil
+}
+
+// TTL is how long signed URLs and cookies stay valid
+func (cf *CloudFront) TTL() time.Duration {
+	return cf.ttl
+}
+
+// URL returns the unsigned CDN URL for an object key (free assets only)
+func (cf *CloudFront) URL(key string) string {
+	return cf.baseURL + "/" + strings.TrimPrefix(key, "/")
+}
+
+// SignedURL returns a time-limited CDN URL for a paid object key. clientIP is only
+// used when IP binding is enabled.
+func (cf *CloudFront) SignedURL(key, clientIP string) (string, time.Time, error) {
+	if !cf.CanSign() {
+		return "", time.Time{}, errors.New("cloudfront: signing is not configured")
+	}
+	p := Policy{Expires: cf.signer.now().Add(cf.ttl)}
+	if cf.bindIP {
+		p.IPAddress = clientIP
+	}
+	signed, err := cf.signer.SignURL(cf.URL(key), p)
+	return signed, p.Expires, err
+}
+
+// StreamCookies returns signed cookies covering every file next to key, so an HLS
+// player can fetch the playlist's variant playlists and segments
+func (cf *CloudFront) StreamCookies(key, clientIP string) ([]*http.Cookie, error) {
+	if !cf.CanSign() {
+		return nil, errors.New("cloudfront: signing is not configured")
+	}
+	prefix := "/"
+	if dir := path.Dir(strings.TrimPrefix(key, "/")); dir != "." {
+		prefix += dir + "/"
+	}
+	p := Policy{
+		Resource: cf.baseURL + prefix + "*",
+		Expires:  cf.signer.now().Add(cf.ttl),
+	}
+	if cf.bindIP {
+		p.IPAddress = clientIP
+	}
+	return cf.signer.SignedCookies(p, cf.cookieDomain, prefix)
+}
+
+// IsHLS reports whether key is an HLS playlist
+func IsHLS(key string) bool {
+	return strings.HasSuffix(strings.ToLower(key), ".m3u8")
+}
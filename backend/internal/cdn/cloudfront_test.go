@@ -0,0 +1,160 @@
+package cdn
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testKeyPairID = "K2JCJMDEHXQW5F"
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestCloudFront(t *testing.T, bindIP bool) (*CloudFront, *rsa.PublicKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	signer := NewSigner(testKeyPairID, key)
+	signer.now = func() time.Time { return testNow }
+	return NewCloudFront("https://cdn.example.com/", signer, 5*time.Minute, bindIP, ".example.com"), &key.PublicKey
+}
+
+// decode reverses CloudFront's base64 substitutions, failing if the standard
+// characters they replace appear
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+	if strings.ContainsAny(s, "+=/") {
+		t.Fatalf("%q contains characters CloudFront substitutes", s)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.NewReplacer("-", "+", "_", "=", "~", "/").Replace(s))
+	if err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+	return b
+}
+
+func verify(t *testing.T, pub *rsa.PublicKey, doc []byte, signature string) {
+	t.Helper()
+	h := sha1.Sum(doc)
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA1, h[:], decode(t, signature)); err != nil {
+		t.Fatalf("signature does not verify for %s: %v", doc, err)
+	}
+}
+
+func TestEncodeSubstitutions(t *testing.T) {
+	// Standard base64 of these bytes is "+/8="
+	if got := encode([]byte{0xfb, 0xff}); got != "-~8_" {
+		t.Fatalf("encode = %q, want %q", got, "-~8_")
+	}
+}
+
+func TestSignedURLCannedPolicy(t *testing.T) {
+	cf, pub := newTestCloudFront(t, false)
+
+	signed, expires, err := cf.SignedURL("videos/match.mp4", "203.0.113.7")
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	if want := testNow.Add(5 * time.Minute); !expires.Equal(want) {
+		t.Fatalf("expires = %v, want %v", expires, want)
+	}
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse %q: %v", signed, err)
+	}
+	if base := u.Scheme + "://" + u.Host + u.Path; base != "https://cdn.example.com/videos/match.mp4" {
+		t.Fatalf("base URL = %q", base)
+	}
+	params := u.Query()
+	if params.Get("Policy") != "" {
+		t.Fatalf("canned URL has a Policy parameter: %s", signed)
+	}
+	wantQuery := fmt.Sprintf("Expires=%d&Signature=%s&Key-Pair-Id=%s", expires.Unix(), params.Get("Signature"), testKeyPairID)
+	if u.RawQuery != wantQuery {
+		t.Fatalf("query = %q, want %q", u.RawQuery, wantQuery)
+	}
+
+	doc := fmt.Sprintf(`{"Statement":[{"Resource":"https://cdn.example.com/videos/match.mp4","Condition":{"DateLessThan":{"AWS:EpochTime":%d}}}]}`, expires.Unix())
+	verify(t, pub, []byte(doc), params.Get("Signature"))
+}
+
+func TestSignedURLCustomPolicy(t *testing.T) {
+	cf, pub := newTestCloudFront(t, true)
+
+	signed, expires, err := cf.SignedURL("videos/match.mp4?v=2", "203.0.113.7")
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse %q: %v", signed, err)
+	}
+	params := u.Query()
+	if params.Get("Expires") != "" {
+		t.Fatalf("custom URL has an Expires parameter: %s", signed)
+	}
+	if !strings.HasPrefix(u.RawQuery, "v=2&Policy=") || !strings.HasSuffix(u.RawQuery, "&Key-Pair-Id="+testKeyPairID) {
+		t.Fatalf("query = %q, want the signing parameters last", u.RawQuery)
+	}
+
+	doc := decode(t, params.Get("Policy"))
+	var policy policyDocument
+	if err := json.Unmarshal(doc, &policy); err != nil {
+		t.Fatalf("policy %s: %v", doc, err)
+	}
+	if len(policy.Statement) != 1 {
+		t.Fatalf("policy has %d statements", len(policy.Statement))
+	}
+	statement := policy.Statement[0]
+	if statement.Resource != "https://cdn.example.com/videos/match.mp4?v=2" {
+		t.Fatalf("resource = %q", statement.Resource)
+	}
+	if statement.Condition.DateLessThan.EpochTime != expires.Unix() {
+		t.Fatalf("expiry = %d, want %d", statement.Condition.DateLessThan.EpochTime, expires.Unix())
+	}
+	if statement.Condition.IPAddress == nil || statement.Condition.IPAddress.SourceIP != "203.0.113.7/32" {
+		t.Fatalf("IP binding = %+v, want 203.0.113.7/32", statement.Condition.IPAddress)
+	}
+	verify(t, pub, doc, params.Get("Signature"))
+}
+
+func TestStreamCookies(t *testing.T) {
+	cf, pub := newTestCloudFront(t, true)
+
+	cookies, err := cf.StreamCookies("hls/abc/index.m3u8", "2001:db8::1")
+	if err != nil {
+		t.Fatalf("StreamCookies: %v", err)
+	}
+	values := map[string]string{}
+	for _, c := range cookies {
+		values[c.Name] = c.Value
+		if c.Path != "/hls/abc/" || c.Domain != ".example.com" || !c.Secure || !c.HttpOnly {
+			t.Fatalf("cookie %s has path %q, domain %q, secure %t, httponly %t", c.Name, c.Path, c.Domain, c.Secure, c.HttpOnly)
+		}
+		if !c.Expires.Equal(testNow.Add(5 * time.Minute)) {
+			t.Fatalf("cookie %s expires %v", c.Name, c.Expires)
+		}
+	}
+	if len(cookies) != 3 || values["CloudFront-Key-Pair-Id"] != testKeyPairID {
+		t.Fatalf("cookies = %v", values)
+	}
+
+	doc := decode(t, values["CloudFront-Policy"])
+	want := fmt.Sprintf(`{"Statement":[{"Resource":"https://cdn.example.com/hls/abc/*","Condition":{"DateLessThan":{"AWS:EpochTime":%d},"IpAddress":{"AWS:SourceIp":"2001:db8::1/128"}}}]}`,
+		testNow.Add(5*time.Minute).Unix())
+	if string(doc) != want {
+		t.Fatalf("policy = %s, want %s", doc, want)
+	}
+	verify(t, pub, doc, values["CloudFront-Signature"])
+}
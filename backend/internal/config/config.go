@@ -1,17 +1,24 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"github.com/unicorn-sport/backend/internal/cdn"
 	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/scanner"
+	"github.com/unicorn-sport/backend/internal/storage"
+	"github.com/unicorn-sport/backend/internal/views"
 )
 
 // Config holds all configuration for the application
@@ -21,6 +28,9 @@ type Config struct {
 	Database    DatabaseConfig
 	JWT         JWTConfig
 	AWS         AWSConfig
+	Storage     StorageConfig
+	Scanner     ScannerConfig
+	Views       ViewsConfig
 	Stripe      StripeConfig
 }
 
@@ -48,6 +58,39 @@ type AWSConfig struct {
 	SecretAccessKey string
 	S3Bucket        string
 	CloudFrontURL   string
+
+	// CloudFront signing for paid assets (full matches, HLS streams)
+	CloudFrontKeyPairID      string
+	CloudFrontPrivateKey     string // PEM contents; takes precedence over the path
+	CloudFrontPrivateKeyPath string
+	CloudFrontSignedTTL      int    // signed URL/cookie lifetime in minutes
+	CloudFrontBindIP         bool   // restrict signed URLs to the requesting IP
+	CloudFrontCookieDomain   string // domain for signed cookies (e.g. .unicornsport.com)
+}
+
+// StorageConfig holds object storage configuration
+type StorageConfig struct {
+	Driver        string // s3 or local; empty picks s3 when AWS credentials are set
+	LocalPath     string
+	PublicBaseURL string // public URL of this API, used for local signed links
+	SigningSecret string
+
+	UploadJanitorIntervalMinutes int // how often stale upload sessions are expired
+}
+
+// ScannerConfig holds malware scanner configuration
+type ScannerConfig struct {
+	Driver         string // none or clamav
+	ClamAVAddress  string // host:port or unix socket path
+	TimeoutSeconds int
+}
+
+// ViewsConfig holds view tracking configuration
+type ViewsConfig struct {
+	IPSalt                string // secret for hashing viewer IPs; defaults to one derived from JWT_SECRET
+	DedupWindowMinutes    int    // repeat views by the same viewer inside the window count once
+	FlushIntervalSeconds  int    // how long views are buffered before being written
+	RollupIntervalMinutes int    // how often daily rollups and view counts are refreshed
 }
 
 // StripeConfig holds Stripe configuration
@@ -86,6 +129,32 @@ func Load() (*Config, error) {
 			SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
 			S3Bucket:        getEnv("AWS_S3_BUCKET", "unicorn-sport-media"),
 			CloudFrontURL:   getEnv("AWS_CLOUDFRONT_URL", ""),
+
+			CloudFrontKeyPairID:      getEnv("CLOUDFRONT_KEY_PAIR_ID", ""),
+			CloudFrontPrivateKey:     getEnv("CLOUDFRONT_PRIVATE_KEY", ""),
+			CloudFrontPrivateKeyPath: getEnv("CLOUDFRONT_PRIVATE_KEY_PATH", ""),
+			CloudFrontSignedTTL:      getEnvAsInt("CLOUDFRONT_SIGNED_URL_TTL_MINUTES", 240),
+			CloudFrontBindIP:         getEnv("CLOUDFRONT_BIND_IP", "false") == "true",
+			CloudFrontCookieDomain:   getEnv("CLOUDFRONT_COOKIE_DOMAIN", ""),
+		},
+		Storage: StorageConfig{
+			Driver:        getEnv("STORAGE_DRIVER", ""),
+			LocalPath:     getEnv("STORAGE_LOCAL_PATH", "./data/storage"),
+			PublicBaseURL: getEnv("PUBLIC_API_URL", "http://localhost:"+getEnv("PORT", "8080")),
+			SigningSecret: getEnv("STORAGE_SIGNING_SECRET", ""),
+
+			UploadJanitorIntervalMinutes: getEnvAsInt("UPLOAD_JANITOR_INTERVAL_MINUTES", 15),
+		},
+		Scanner: ScannerConfig{
+			Driver:         getEnv("SCANNER_DRIVER", "none"),
+			ClamAVAddress:  getEnv("CLAMAV_ADDRESS", "localhost:3310"),
+			TimeoutSeconds: getEnvAsInt("SCANNER_TIMEOUT_SECONDS", 60),
+		},
+		Views: ViewsConfig{
+			IPSalt:                getEnv("VIEWS_IP_SALT", ""),
+			DedupWindowMinutes:    getEnvAsInt("VIEWS_DEDUP_WINDOW_MINUTES", 30),
+			FlushIntervalSeconds:  getEnvAsInt("VIEWS_FLUSH_INTERVAL_SECONDS", 5),
+			RollupIntervalMinutes: getEnvAsInt("VIEWS_ROLLUP_INTERVAL_MINUTES", 10),
 		},
 		Stripe: StripeConfig{
 			SecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
@@ -145,9 +214,36 @@ func (c *Config) InitDB() (*gorm.DB, error) {
 		// New video architecture models
 		&domain.MatchVideo{},
 		&domain.PlayerHighlight{},
+		&domain.HighlightIngest{},
+		&domain.HighlightIngestItem{},
 		&domain.MatchPurchase{},
 		&domain.HighlightView{},
 		&domain.MatchVideoView{},
+		&domain.PlaybackSession{},
+		&domain.MatchPreviewView{},
+		&domain.MatchVideoNote{},
+		&domain.MatchPlayerMoment{},
+		&domain.ViewRollup{},
+		&domain.ProfileView{},
+		&domain.PlayerChangeRequest{},
+		&domain.Guardian{},
+		&domain.PlayerGuardian{},
+		&domain.ConsentRecord{},
+		&domain.MinorAccessLog{},
+		&domain.PlayerVerification{},
+		&domain.VerificationEvent{},
+		&domain.Notification{},
+		&domain.School{},
+		&domain.SchoolAdmin{},
+		&domain.SchoolAttestation{},
+		&domain.TournamentRegistration{},
+		&domain.TournamentGroup{},
+		&domain.Team{},
+		&domain.TournamentStats{},
+		&domain.PlayerFlag{},
+		&domain.PlayerMerge{},
+		&domain.PlayerMergeRow{},
+		&domain.PlayerDuplicateDismissal{},
 	); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -155,6 +251,82 @@ func (c *Config) InitDB() (*gorm.DB, error) {
 	return db, nil
 }
 
+// InitStorage creates the object store selected by STORAGE_DRIVER
+func (c *Config) InitStorage() (storage.Store, error) {
+	driver := c.Storage.Driver
+	if driver == "" {
+		driver = "local"
+		if c.AWS.AccessKeyID != "" && c.AWS.SecretAccessKey != "" {
+			driver = "s3"
+		}
+	}
+
+	switch driver {
+	case "s3":
+		return storage.NewS3Store(context.Background(), c.AWS.Region, c.AWS.AccessKeyID, c.AWS.SecretAccessKey, c.AWS.S3Bucket)
+	case "local":
+		secret := c.Storage.SigningSecret
+		if secret == "" {
+			secret = c.JWT.Secret
+		}
+		return storage.NewLocalStore(c.Storage.LocalPath, c.Storage.PublicBaseURL, c.AWS.S3Bucket, secret)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
+
+// InitCDN sets up the CloudFront distribution, with URL signing when a key pair is
+// configured. Returns nil when AWS_CLOUDFRONT_URL isn't set.
+func (c *Config) InitCDN() (*cdn.CloudFront, error) {
+	if c.AWS.CloudFrontURL == "" {
+		return nil, nil
+	}
+
+	var signer *cdn.Signer
+	if c.AWS.CloudFrontKeyPairID != "" {
+		pemBytes := []byte(strings.ReplaceAll(c.AWS.CloudFrontPrivateKey, `\n`, "\n"))
+		if c.AWS.CloudFrontPrivateKey == "" {
+			var err error
+			if pemBytes, err = os.ReadFile(c.AWS.CloudFrontPrivateKeyPath); err != nil {
+				return nil, fmt.Errorf("failed to read CloudFront private key: %w", err)
+			}
+		}
+		key, err := cdn.ParsePrivateKey(pemBytes)
+		if err != nil {
+			return nil, err
+		}
+		signer = cdn.NewSigner(c.AWS.CloudFrontKeyPairID, key)
+	}
+
+	ttl := time.Duration(c.AWS.CloudFrontSignedTTL) * time.Minute
+	return cdn.NewCloudFront(c.AWS.CloudFrontURL, signer, ttl, c.AWS.CloudFrontBindIP, c.AWS.CloudFrontCookieDomain), nil
+}
+
+// InitScanner creates the malware scanner selected by SCANNER_DRIVER
+func (c *Config) InitScanner() (scanner.Scanner, error) {
+	switch c.Scanner.Driver {
+	case "", "none":
+		return scanner.NewNoop(), nil
+	case "clamav":
+		return scanner.NewClamAV(c.Scanner.ClamAVAddress, time.Duration(c.Scanner.TimeoutSeconds)*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unknown scanner driver %q", c.Scanner.Driver)
+	}
+}
+
+// InitViews creates the view tracker
+func (c *Config) InitViews(db *gorm.DB) *views.Tracker {
+	salt := c.Views.IPSalt
+	if salt == "" {
+		salt = "views:" + c.JWT.Secret
+	}
+	return views.NewTracker(db, views.Config{
+		Salt:          salt,
+		DedupWindow:   time.Duration(c.Views.DedupWindowMinutes) * time.Minute,
+		FlushInterval: time.Duration(c.Views.FlushIntervalSeconds) * time.Second,
+	})
+}
+
 // Helper functions
 // GetEnv returns environment variable value or default
 func GetEnv(key, defaultValue string) string {
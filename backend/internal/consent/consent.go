@@ -0,0 +1,117 @@
+package consent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Consent types a guardian gives for a minor player
+const (
+	ProfilePublication = "profile_publication" // profile and photo listed publicly
+	VideoPublication   = "video_publication"   // highlights and match footage shown
+	ScoutContact       = "scout_contact"       // scouts may request contact through us
+)
+
+// AdultAge is the age from which players don't need guardian consent
+const AdultAge = 18
+
+// CurrentVersions is the consent form version each type must have been given
+// against. Bump a version when the wording changes: older consents stop counting
+// and guardians have to consent again.
+var CurrentVersions = map[string]string{
+	ProfilePublication: "v1",
+	VideoPublication:   "v1",
+	ScoutContact:       "v1",
+}
+
+// Types lists the consent types in display order
+var Types = []string{ProfilePublication, VideoPublication, ScoutContact}
+
+// IsValidType reports whether t is a known consent type
+func IsValidType(t string) bool {
+	_, ok := CurrentVersions[t]
+	return ok
+}
+
+// adultCutoff is the latest date of birth of a player who is an adult today
+func adultCutoff() time.Time {
+	return time.Now().AddDate(-AdultAge, 0, 0)
+}
+
+// IsMinor reports whether a player needs guardian consent
+func IsMinor(p *domain.Player) bool {
+	return p.DateOfBirth.After(adultCutoff())
+}
+
+// Published is a query scope on players that keeps adults and minors with a
+// current, unwithdrawn consent of the given type
+func Published(consentType string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`(players.date_of_birth <= ? OR EXISTS (
+			SELECT 1 FROM consent_records cr
+			WHERE cr.player_id = players.id AND cr.consent_type = ? AND cr.version = ? AND cr.withdrawn_at IS NULL))`,
+			adultCutoff(), consentType, CurrentVersions[consentType])
+	}
+}
+
+// PublishedPlayerIDs is a subquery of the IDs of players whose data may be shown
+// for the consent type, for filtering tables keyed by player_id
+func PublishedPlayerIDs(db *gorm.DB, consentType string) *gorm.DB {
+	return db.Model(&domain.Player{}).Select("players.id").Scopes(Published(consentType))
+}
+
+// Allowed reports whether a player's data may be used for the consent type:
+// always for adults, for minors only with a current consent
+func Allowed(db *gorm.DB, p *domain.Player, consentType string) bool {
+	if !IsMinor(p) {
+		return true
+	}
+	var count int64
+	db.Model(&domain.ConsentRecord{}).
+		Where("player_id = ? AND consent_type = ? AND version = ? AND withdrawn_at IS NULL", p.ID, consentType, CurrentVersions[consentType]).
+		Count(&count)
+	return count > 0
+}
+
+// Current returns the player's current consent of each type, nil where missing
+func Current(db *gorm.DB, playerID uuid.UUID) (map[string]*domain.ConsentRecord, error) {
+	var records []domain.ConsentRecord
+	if err := db.Where("player_id = ? AND withdrawn_at IS NULL", playerID).Order("signed_at DESC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]*domain.ConsentRecord, len(Types))
+	for _, t := range Types {
+		current[t] = nil
+	}
+	for i := range records {
+		r := &records[i]
+		if r.Version == CurrentVersions[r.ConsentType] && current[r.ConsentType] == nil {
+			current[r.ConsentType] = r
+		}
+	}
+	return current, nil
+}
+
+// AllowedIDs returns which of the given minor players have a current consent of the
+// type, for checking a page of players in one query
+func AllowedIDs(db *gorm.DB, playerIDs []uuid.UUID, consentType string) (map[uuid.UUID]bool, error) {
+	allowed := make(map[uuid.UUID]bool, len(playerIDs))
+	if len(playerIDs) == 0 {
+		return allowed, nil
+	}
+	var ids []uuid.UUID
+	if err := db.Model(&domain.ConsentRecord{}).
+		Where("player_id IN ? AND consent_type = ? AND version = ? AND withdrawn_at IS NULL", playerIDs, consentType, CurrentVersions[consentType]).
+		Distinct().Pluck("player_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		allowed[id] = true
+	}
+	return allowed, nil
+}
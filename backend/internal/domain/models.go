@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,12 +14,16 @@ type User struct {
 	PasswordHash  string     `json:"-" gorm:"not null"`
 	FirstName     string     `json:"first_name" gorm:"not null"`
 	LastName      string     `json:"last_name" gorm:"not null"`
-	Role          string     `json:"role" gorm:"not null;check:role IN ('admin', 'scout', 'player')"`
+	Role          string     `json:"role" gorm:"not null;check:role IN ('admin', 'scout', 'player', 'school')"`
 	EmailVerified bool       `json:"email_verified" gorm:"default:false"`
 	IsActive      bool       `json:"is_active" gorm:"default:true"`
 	LastLoginAt   *time.Time `json:"last_login_at,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Set for accounts issued with a temporary password (admin-created players)
+	MustChangePassword bool `json:"must_change_password" gorm:"default:false"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // RefreshToken stores JWT refresh tokens
@@ -74,8 +79,10 @@ type Scout struct {
 	OrganizationType *string   `json:"organization_type,omitempty"`
 	Country          *string   `json:"country,omitempty"`
 	IsVerified       bool      `json:"is_verified" gorm:"default:false"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	// Opt-in: players see who viewed them instead of just organization type and country
+	ShareIdentityWithPlayers bool      `json:"share_identity_with_players" gorm:"default:false"`
+	CreatedAt                time.Time `json:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"`
 
 	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
@@ -94,6 +101,9 @@ type Tournament struct {
 	IsPublic      bool       `json:"is_public" gorm:"default:false"`
 	Featured      bool       `json:"featured" gorm:"default:false"`
 	CoverImageURL *string    `json:"cover_image_url,omitempty"`
+	AgeGroup      *string    `json:"age_group,omitempty"`       // U13, U15, U17 or U20; empty for open competitions
+	AgeCutoffDate *time.Time `json:"age_cutoff_date,omitempty"` // ages are taken on this date, 1 January of Year if unset
+	TieBreakers   *string    `json:"tie_breakers,omitempty"`    // comma-separated, in order; defaults apply if unset
 	CreatedBy     *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
 	CreatedAt     time.Time  `json:"created_at"`
 }
@@ -114,15 +124,17 @@ type Player struct {
 	Country             string     `json:"country" gorm:"not null;index"`
 	State               *string    `json:"state,omitempty"`
 	City                *string    `json:"city,omitempty"`
-	SchoolName          *string    `json:"school_name,omitempty"`
+	SchoolName          *string    `json:"school_name,omitempty"`    // free text, or the linked school's name
+	SchoolID            *uuid.UUID `json:"-" gorm:"type:uuid;index"` // partner school, masked like school_name
 	VerificationStatus  string     `json:"verification_status" gorm:"default:'pending';index"`
+	VerificationSource  *string    `json:"-"` // document, school or admin
 	VerificationDocType *string    `json:"-"`
 	VerificationDocURL  *string    `json:"-"`
 	VerifiedAt          *time.Time `json:"-"`
 	VerifiedBy          *uuid.UUID `json:"-" gorm:"type:uuid"`
 	ProfilePhotoURL     *string    `json:"profile_photo_url,omitempty"`
 	ThumbnailURL        *string    `json:"thumbnail_url,omitempty"`
-	TournamentID        *uuid.UUID `json:"tournament_id,omitempty" gorm:"type:uuid;index"`
+	TournamentID        *uuid.UUID `json:"tournament_id,omitempty" gorm:"type:uuid;index"` // first tournament; see TournamentRegistration for all of them
 	TournamentYear      *int       `json:"tournament_year,omitempty"`
 	CreatedBy           uuid.UUID  `json:"-" gorm:"type:uuid;not null"`
 	CreatedAt           time.Time  `json:"created_at"`
@@ -131,6 +143,7 @@ type Player struct {
 
 	Tournament     *Tournament       `json:"tournament,omitempty" gorm:"foreignKey:TournamentID"`
 	Academy        *Academy          `json:"academy,omitempty" gorm:"foreignKey:AcademyID"`
+	School         *School           `json:"-" gorm:"foreignKey:SchoolID"`
 	Videos         []Video           `json:"videos,omitempty" gorm:"many2many:player_videos;"`
 	Highlights     []PlayerHighlight `json:"highlights,omitempty" gorm:"foreignKey:PlayerID"`
 	AcademyPlayers []AcademyPlayer   `json:"academy_memberships,omitempty" gorm:"foreignKey:PlayerID"`
@@ -156,6 +169,53 @@ type AcademyPlayer struct {
 	Player  *Player  `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
 }
 
+// Guardian is a parent or legal guardian who consents on behalf of minor players.
+// One guardian can be linked to several players (siblings).
+type Guardian struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	FirstName string    `json:"first_name" gorm:"not null"`
+	LastName  string    `json:"last_name" gorm:"not null"`
+	Email     *string   `json:"email,omitempty"`
+	Phone     *string   `json:"phone,omitempty"`
+	Address   *string   `json:"address,omitempty"`
+	CreatedBy uuid.UUID `json:"-" gorm:"type:uuid;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PlayerGuardian links a guardian to a player
+type PlayerGuardian struct {
+	PlayerID     uuid.UUID `json:"player_id" gorm:"type:uuid;primaryKey"`
+	GuardianID   uuid.UUID `json:"guardian_id" gorm:"type:uuid;primaryKey;index"`
+	Relationship string    `json:"relationship" gorm:"not null;default:'parent'"` // parent, legal_guardian, relative, other
+	IsPrimary    bool      `json:"is_primary" gorm:"default:false"`               // first contact for consent and contact requests
+	CreatedAt    time.Time `json:"created_at"`
+
+	Guardian *Guardian `json:"guardian,omitempty" gorm:"foreignKey:GuardianID"`
+}
+
+// ConsentRecord is one consent given for a player, against a specific version of
+// the consent form. Records are never edited: a new version means a new record,
+// and withdrawing sets WithdrawnAt.
+type ConsentRecord struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PlayerID         uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;index"`
+	GuardianID       *uuid.UUID `json:"guardian_id,omitempty" gorm:"type:uuid;index"` // nil when an adult player consented themselves
+	ConsentType      string     `json:"consent_type" gorm:"not null;index"`           // profile_publication, video_publication, scout_contact
+	Version          string     `json:"version" gorm:"not null"`                      // consent form version agreed to
+	Method           string     `json:"method" gorm:"not null"`                       // signed_form, in_person, electronic
+	SignedAt         time.Time  `json:"signed_at" gorm:"not null"`
+	EvidenceURL      *string    `json:"-"` // s3:// URL of the scanned form or recording (private storage)
+	Notes            *string    `json:"notes,omitempty"`
+	RecordedBy       uuid.UUID  `json:"-" gorm:"type:uuid;not null"`
+	WithdrawnAt      *time.Time `json:"withdrawn_at,omitempty"`
+	WithdrawnBy      *uuid.UUID `json:"-" gorm:"type:uuid"`
+	WithdrawalReason *string    `json:"withdrawal_reason,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+
+	Guardian *Guardian `json:"guardian,omitempty" gorm:"foreignKey:GuardianID"`
+}
+
 // Subscription represents scout subscription management
 type Subscription struct {
 	ID                   uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -182,6 +242,7 @@ type Video struct {
 	ThumbnailURL    *string   `json:"thumbnail_url,omitempty"`
 	DurationSeconds *int      `json:"duration_seconds,omitempty"`
 	FileSizeBytes   *int64    `json:"file_size_bytes,omitempty"`
+	WatermarkPrefix *string   `json:"-"` // A/B renditions for forensic watermarking, full matches only
 
 	// Review workflow
 	Status      string     `json:"status" gorm:"default:'approved';index"` // pending, approved, rejected
@@ -224,6 +285,19 @@ type Match struct {
 	TournamentID *uuid.UUID `json:"tournament_id,omitempty" gorm:"type:uuid;index"`
 	AcademyID    *uuid.UUID `json:"academy_id,omitempty" gorm:"type:uuid;index"`
 
+	// Tournament structure, set on generated fixtures. Knockout matches are linked
+	// to the match their winner goes on to.
+	HomeTeamID    *uuid.UUID `json:"home_team_id,omitempty" gorm:"type:uuid;index"`
+	AwayTeamID    *uuid.UUID `json:"away_team_id,omitempty" gorm:"type:uuid;index"`
+	GroupID       *uuid.UUID `json:"group_id,omitempty" gorm:"type:uuid;index"`
+	Round         *int       `json:"round,omitempty"`                          // group matchday, or knockout round counting from 1
+	BracketSlot   *int       `json:"bracket_slot,omitempty"`                   // position in the knockout round, from 1
+	NextMatchID   *uuid.UUID `json:"next_match_id,omitempty" gorm:"type:uuid"` // knockout match the winner plays next
+	NextMatchSide *string    `json:"next_match_side,omitempty"`                // home or away
+	HomePenalties *int       `json:"home_penalties,omitempty"`                 // shoot-out deciding a drawn knockout match
+	AwayPenalties *int       `json:"away_penalties,omitempty"`
+	WinnerTeamID  *uuid.UUID `json:"winner_team_id,omitempty" gorm:"type:uuid"`
+
 	// Metadata
 	CreatedBy uuid.UUID `json:"-" gorm:"type:uuid;not null"`
 	CreatedAt time.Time `json:"created_at"`
@@ -236,6 +310,40 @@ type Match struct {
 	Video      *MatchVideo `json:"video,omitempty" gorm:"foreignKey:MatchID"`
 }
 
+// TournamentGroup is a group of teams that play each other in a round robin
+type TournamentGroup struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TournamentID uuid.UUID `json:"tournament_id" gorm:"type:uuid;not null;index"`
+	Name         string    `json:"name" gorm:"not null"` // e.g. Group A
+	Position     int       `json:"position" gorm:"default:0"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	Teams []Team `json:"teams,omitempty" gorm:"foreignKey:GroupID"`
+}
+
+// Team is a side entered in a tournament, usually an academy's squad
+type Team struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TournamentID uuid.UUID  `json:"tournament_id" gorm:"type:uuid;not null;index"`
+	AcademyID    *uuid.UUID `json:"academy_id,omitempty" gorm:"type:uuid;index"`
+	GroupID      *uuid.UUID `json:"group_id,omitempty" gorm:"type:uuid;index"`
+	Name         string     `json:"name" gorm:"not null"`
+	ShortName    *string    `json:"short_name,omitempty"`
+	Seed         *int       `json:"seed,omitempty"` // knockout seeding, 1 is the top seed
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	Academy *Academy `json:"academy,omitempty" gorm:"foreignKey:AcademyID"`
+}
+
+// TournamentStats caches a tournament's standings and leaderboards as JSON. It is
+// recomputed whenever a result changes.
+type TournamentStats struct {
+	TournamentID uuid.UUID `json:"tournament_id" gorm:"type:uuid;primary_key"`
+	Data         string    `json:"-" gorm:"type:jsonb;not null"`
+	ComputedAt   time.Time `json:"computed_at"`
+}
+
 // MatchVideo represents the full match video (PAID content)
 type MatchVideo struct {
 	ID      uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -251,6 +359,16 @@ type MatchVideo struct {
 	Status          string  `json:"status" gorm:"default:'processing';index"` // processing, ready, failed, archived
 	ProcessingError *string `json:"-"`
 
+	// Forensic watermarking: prefix holding A/B HLS renditions (<prefix>/a/index.m3u8,
+	// <prefix>/b/index.m3u8). When set, every stream gets a per-session segment mix.
+	WatermarkPrefix *string `json:"-"`
+
+	// Free preview for users without access: a pre-cut clip (PreviewKey) or, for HLS
+	// videos, a window of the full match. Duration 0 = DefaultPreviewSeconds.
+	PreviewKey             *string `json:"-"`
+	PreviewStartSeconds    int     `json:"preview_start_seconds" gorm:"default:0"`
+	PreviewDurationSeconds int     `json:"preview_duration_seconds" gorm:"default:0"`
+
 	// Pricing for pay-per-view
 	PriceCents int    `json:"price_cents" gorm:"default:999"` // Default $9.99
 	Currency   string `json:"currency" gorm:"default:'USD'"`
@@ -304,6 +422,43 @@ type PlayerHighlight struct {
 	Match  *Match  `json:"match,omitempty" gorm:"foreignKey:MatchID"`
 }
 
+// HighlightIngest is a batch of highlight clips uploaded together from a manifest,
+// e.g. a media team's footage from a tournament
+type HighlightIngest struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ManifestName string     `json:"manifest_name"`
+	Status       string     `json:"status" gorm:"default:'uploading';index"` // uploading, completed
+	ItemCount    int        `json:"item_count"`
+	CreatedBy    uuid.UUID  `json:"created_by" gorm:"type:uuid;not null;index"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+
+	Items []HighlightIngestItem `json:"items,omitempty" gorm:"foreignKey:IngestID"`
+}
+
+// HighlightIngestItem is one clip of an ingest: its upload and the highlight
+// created from it once the upload checks out
+type HighlightIngestItem struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	IngestID         uuid.UUID  `json:"ingest_id" gorm:"type:uuid;not null;index"`
+	Row              int        `json:"row" gorm:"column:manifest_row"` // line or entry number in the manifest
+	FileName         string     `json:"file_name" gorm:"not null"`
+	PlayerID         uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;index"`
+	MatchID          *uuid.UUID `json:"match_id,omitempty" gorm:"type:uuid"`
+	HighlightType    string     `json:"highlight_type" gorm:"not null"`
+	TimestampInMatch *int       `json:"timestamp_in_match,omitempty"`
+	Title            *string    `json:"title,omitempty"`
+	Description      *string    `json:"description,omitempty"`
+	UploadSessionID  uuid.UUID  `json:"upload_session_id" gorm:"type:uuid;not null"`
+	S3Key            string     `json:"-" gorm:"not null"`
+	Status           string     `json:"status" gorm:"default:'pending';index"` // pending, created, failed
+	Error            *string    `json:"error,omitempty"`
+	HighlightID      *uuid.UUID `json:"highlight_id,omitempty" gorm:"type:uuid"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
 // MatchPurchase tracks pay-per-view purchases
 type MatchPurchase struct {
 	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -332,13 +487,59 @@ type MatchPurchase struct {
 	MatchVideo *MatchVideo `json:"match_video,omitempty" gorm:"foreignKey:MatchVideoID"`
 }
 
+// DefaultPreviewSeconds is the free preview length when an admin hasn't picked one
+const DefaultPreviewSeconds = 180
+
+// PreviewWindow returns the preview's start offset and length in seconds
+func (v *MatchVideo) PreviewWindow() (int, int) {
+	duration := v.PreviewDurationSeconds
+	if duration <= 0 {
+		duration = DefaultPreviewSeconds
+	}
+	return v.PreviewStartSeconds, duration
+}
+
+// MatchPreviewView tracks free preview plays of locked matches, for
+// preview-to-purchase conversion analytics
+type MatchPreviewView struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MatchVideoID uuid.UUID  `json:"match_video_id" gorm:"type:uuid;not null;index"`
+	ViewerID     *uuid.UUID `json:"viewer_id,omitempty" gorm:"type:uuid;index"` // NULL for anonymous
+	IPHash       *string    `json:"-"`
+	Source       *string    `json:"source,omitempty"` // full_matches_list, match_page, share
+	CreatedAt    time.Time  `json:"created_at" gorm:"index"`
+}
+
+// PlaybackSession records one watermarked stream of a paid video, so a leaked copy
+// can be traced back to the account that streamed it
+type PlaybackSession struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	AssetType string    `json:"asset_type" gorm:"not null;index:idx_playback_asset"` // match_video, video
+	AssetID   uuid.UUID `json:"asset_id" gorm:"type:uuid;not null;index:idx_playback_asset"`
+
+	// Watermark payload: WatermarkID drives the A/B segment sequence, WatermarkCode is
+	// the same ID rendered as the visible overlay code
+	WatermarkID   int64  `json:"watermark_id" gorm:"not null;uniqueIndex"`
+	WatermarkCode string `json:"watermark_code" gorm:"not null;uniqueIndex"`
+	Forensic      bool   `json:"forensic" gorm:"default:false"` // A/B segment selection applied
+
+	IPAddress *string   `json:"ip_address,omitempty"`
+	UserAgent *string   `json:"user_agent,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
 // HighlightView tracks views on highlights for analytics
 type HighlightView struct {
 	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	HighlightID uuid.UUID  `json:"highlight_id" gorm:"type:uuid;not null;index"`
 	ViewerID    *uuid.UUID `json:"viewer_id,omitempty" gorm:"type:uuid;index"` // NULL for anonymous
 	Source      *string    `json:"source,omitempty"`                           // player_profile, search, direct
-	IPHash      *string    `json:"-"`                                          // For unique view counting
+	IPHash      *string    `json:"-"`                                          // Salted, for unique view counting
 	CreatedAt   time.Time  `json:"created_at"`
 }
 
@@ -396,6 +597,7 @@ type MatchPlayer struct {
 	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	MatchID        uuid.UUID  `json:"match_id" gorm:"type:uuid;not null;uniqueIndex:idx_match_player_unique"`
 	PlayerID       uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;uniqueIndex:idx_match_player_unique"`
+	TeamID         *uuid.UUID `json:"team_id,omitempty" gorm:"type:uuid;index"` // the side they played for
 	PositionPlayed *string    `json:"position_played,omitempty"`
 	MinutesPlayed  *int       `json:"minutes_played,omitempty"`
 	Goals          int        `json:"goals" gorm:"default:0"`
@@ -414,6 +616,24 @@ type MatchPlayer struct {
 	Player *Player `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
 }
 
+// MatchPlayerMoment is a time range of a match video in which a player is involved,
+// tagged by media staff so viewers can watch only that player's moments
+type MatchPlayerMoment struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MatchID      uuid.UUID `json:"match_id" gorm:"type:uuid;not null;index:idx_moment_match_player"`
+	MatchVideoID uuid.UUID `json:"match_video_id" gorm:"type:uuid;not null;index"`
+	PlayerID     uuid.UUID `json:"player_id" gorm:"type:uuid;not null;index:idx_moment_match_player"`
+	StartSeconds int       `json:"start_seconds" gorm:"not null"`
+	EndSeconds   int       `json:"end_seconds" gorm:"not null"`
+	Label        *string   `json:"label,omitempty"`                  // e.g. goal, pressing, 1v1
+	Source       string    `json:"source" gorm:"default:'clipping'"` // clipping, csv_import
+	CreatedBy    uuid.UUID `json:"-" gorm:"type:uuid;not null"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Player *Player `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
+}
+
 // UploadSession tracks multipart uploads to S3
 type UploadSession struct {
 	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -431,6 +651,17 @@ type UploadSession struct {
 	CreatedAt   time.Time  `json:"created_at"`
 	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// Integrity validation (set once the object is stored)
+	ChecksumSHA256      *string    `json:"checksum_sha256,omitempty"`       // client-declared whole-file digest
+	DetectedContentType *string    `json:"detected_content_type,omitempty"` // sniffed from file header
+	FailureReason       *string    `json:"failure_reason,omitempty"`
+	VerifiedAt          *time.Time `json:"verified_at,omitempty"`
+
+	// Malware scanning (documents and images are quarantined until clean)
+	ScanStatus    *string    `json:"scan_status,omitempty" gorm:"index"` // clean, infected
+	ScanSignature *string    `json:"scan_signature,omitempty"`
+	ScannedAt     *time.Time `json:"scanned_at,omitempty"`
 }
 
 // PlayerVideo is many-to-many relationship between players and videos
@@ -461,6 +692,170 @@ type ContactRequest struct {
 	User   *User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// PlayerChangeRequest is an edit a player submitted to their own profile. Nothing
+// changes on the Player until an admin approves it. Nil fields are left as they are.
+type PlayerChangeRequest struct {
+	ID                 uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PlayerID           uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;index"`
+	SubmittedBy        uuid.UUID  `json:"-" gorm:"type:uuid;not null"`
+	SchoolName         *string    `json:"school_name,omitempty"`
+	City               *string    `json:"city,omitempty"`
+	HeightCm           *int       `json:"height_cm,omitempty"`
+	SecondaryPositions *string    `json:"secondary_positions,omitempty"`         // JSON array, as on Player
+	PhotoURL           *string    `json:"-"`                                     // s3:// URL of the verified upload, becomes profile_photo_url
+	Note               *string    `json:"note,omitempty"`                        // from the player to the reviewer
+	Status             string     `json:"status" gorm:"default:'pending';index"` // pending, approved, rejected, cancelled
+	ReviewNote         *string    `json:"review_note,omitempty"`                 // shown to the player, e.g. why it was rejected
+	ReviewedBy         *uuid.UUID `json:"-" gorm:"type:uuid"`
+	ReviewedAt         *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	Player *Player `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
+}
+
+// PlayerVerification is an identity document submitted for review. A player has at
+// most one open submission; approving it marks the player verified.
+type PlayerVerification struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PlayerID    uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;index"`
+	DocType     string     `json:"doc_type" gorm:"not null"`                // nin, passport, birth_certificate, school_id
+	DocURL      string     `json:"-" gorm:"not null"`                       // s3:// URL under private/verification/
+	Status      string     `json:"status" gorm:"default:'submitted';index"` // submitted, in_review, more_info_requested, approved, rejected
+	SubmittedBy uuid.UUID  `json:"submitted_by" gorm:"type:uuid;not null"`
+	ClaimedBy   *uuid.UUID `json:"claimed_by,omitempty" gorm:"type:uuid;index"`
+	ClaimedAt   *time.Time `json:"claimed_at,omitempty"`
+	Reason      *string    `json:"reason,omitempty"` // why it was rejected or what else is needed, shown to the player
+	DecidedBy   *uuid.UUID `json:"decided_by,omitempty" gorm:"type:uuid"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	Player *Player             `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
+	Events []VerificationEvent `json:"events,omitempty" gorm:"foreignKey:VerificationID"`
+}
+
+// VerificationEvent is one step in a player's verification history. Status changes
+// made outside the review queue (bulk verify, player edits, school attestations)
+// have no VerificationID.
+type VerificationEvent struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	VerificationID *uuid.UUID `json:"verification_id,omitempty" gorm:"type:uuid;index"`
+	PlayerID       uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;index"`
+	Action         string     `json:"action" gorm:"not null"` // submitted, resubmitted, claimed, released, approved, rejected, more_info_requested, manually_verified, manually_unverified, school_attested, school_disputed
+	ActorID        uuid.UUID  `json:"actor_id" gorm:"type:uuid;not null"`
+	Note           *string    `json:"note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// SchoolAttestation is a partner school confirming a student's enrollment and date
+// of birth. Linking a player to a school opens a pending attestation in the
+// school's queue; schools registering a roster attest as they upload it.
+type SchoolAttestation struct {
+	ID                  uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SchoolID            uuid.UUID  `json:"school_id" gorm:"type:uuid;not null;index"`
+	PlayerID            uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;index"`
+	Status              string     `json:"status" gorm:"default:'pending';index"` // pending, confirmed, disputed, cancelled
+	DisputeReason       *string    `json:"dispute_reason,omitempty"`              // not_enrolled, dob_incorrect, other
+	ReportedDateOfBirth *time.Time `json:"reported_date_of_birth,omitempty"`      // on the school's records: as confirmed, or as reported in a dispute
+	Note                *string    `json:"note,omitempty"`
+	RequestedBy         uuid.UUID  `json:"requested_by" gorm:"type:uuid;not null"`
+	AttestedBy          *uuid.UUID `json:"attested_by,omitempty" gorm:"type:uuid"`
+	AttestedAt          *time.Time `json:"attested_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+
+	School *School `json:"school,omitempty" gorm:"foreignKey:SchoolID"`
+	Player *Player `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
+}
+
+// TournamentRegistration is a player's entry in a tournament. Players can attend
+// any number of tournaments; appearances in a tournament's matches register them.
+type TournamentRegistration struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TournamentID uuid.UUID  `json:"tournament_id" gorm:"type:uuid;not null;uniqueIndex:idx_tournament_registration_unique"`
+	PlayerID     uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;uniqueIndex:idx_tournament_registration_unique;index"`
+	Squad        *string    `json:"squad,omitempty"` // team the player was entered with
+	ShirtNumber  *int       `json:"shirt_number,omitempty"`
+	Status       string     `json:"status" gorm:"default:'registered';index"` // registered, participated, withdrawn
+	CreatedBy    *uuid.UUID `json:"-" gorm:"type:uuid"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	Tournament *Tournament `json:"tournament,omitempty" gorm:"foreignKey:TournamentID"`
+	Player     *Player     `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
+}
+
+// PlayerFlag is an automated warning about a player's age data for admins to
+// review. Flags clear themselves when the data is corrected; dismissed flags stay
+// dismissed.
+type PlayerFlag struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PlayerID        uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;index"`
+	Type            string     `json:"type" gorm:"not null;index"`                   // dob_mismatch, implausible_height, possible_duplicate
+	RelatedPlayerID *uuid.UUID `json:"related_player_id,omitempty" gorm:"type:uuid"` // the other player, for possible duplicates
+	Details         string     `json:"details"`
+	Status          string     `json:"status" gorm:"default:'open';index"` // open, dismissed, cleared
+	ResolvedBy      *uuid.UUID `json:"resolved_by,omitempty" gorm:"type:uuid"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	Note            *string    `json:"note,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	Player        *Player `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
+	RelatedPlayer *Player `json:"related_player,omitempty" gorm:"foreignKey:RelatedPlayerID"`
+}
+
+// PlayerMerge records a duplicate player folded into another. The moved rows are
+// kept so the merge can be undone.
+type PlayerMerge struct {
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SurvivorID        uuid.UUID  `json:"survivor_id" gorm:"type:uuid;not null;index"`
+	MergedID          uuid.UUID  `json:"merged_id" gorm:"type:uuid;not null;index"`      // soft-deleted by the merge
+	MovedUserID       *uuid.UUID `json:"moved_user_id,omitempty" gorm:"type:uuid"`       // login moved to the survivor
+	DeactivatedUserID *uuid.UUID `json:"deactivated_user_id,omitempty" gorm:"type:uuid"` // merged player's login, when both had one
+	Status            string     `json:"status" gorm:"default:'merged';index"`           // merged, undone
+	MergedBy          uuid.UUID  `json:"merged_by" gorm:"type:uuid;not null"`
+	UndoneBy          *uuid.UUID `json:"undone_by,omitempty" gorm:"type:uuid"`
+	UndoneAt          *time.Time `json:"undone_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+
+	Rows     []PlayerMergeRow `json:"rows,omitempty" gorm:"foreignKey:MergeID"`
+	Survivor *Player          `json:"survivor,omitempty" gorm:"foreignKey:SurvivorID"`
+	Merged   *Player          `json:"merged,omitempty" gorm:"foreignKey:MergedID"`
+}
+
+// PlayerMergeRow is a row a merge re-pointed from the merged player to the survivor
+type PlayerMergeRow struct {
+	ID      uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MergeID uuid.UUID `json:"merge_id" gorm:"type:uuid;not null;index"`
+	Source  string    `json:"source" gorm:"not null"` // table and column, e.g. match_players.player_id
+	RowID   uuid.UUID `json:"row_id" gorm:"type:uuid;not null"`
+}
+
+// PlayerDuplicateDismissal marks a candidate pair an admin has checked and found
+// to be two different players. PlayerID is the lower of the two IDs.
+type PlayerDuplicateDismissal struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PlayerID      uuid.UUID `json:"player_id" gorm:"type:uuid;not null;uniqueIndex:idx_duplicate_dismissal_pair"`
+	OtherPlayerID uuid.UUID `json:"other_player_id" gorm:"type:uuid;not null;uniqueIndex:idx_duplicate_dismissal_pair"`
+	DismissedBy   uuid.UUID `json:"dismissed_by" gorm:"type:uuid;not null"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Notification is an in-app message to a user. Email delivery isn't wired up yet,
+// so notifications are also logged.
+type Notification struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `json:"-" gorm:"type:uuid;not null;index"`
+	Type      string     `json:"type" gorm:"not null"` // e.g. verification_approved
+	Title     string     `json:"title" gorm:"not null"`
+	Body      string     `json:"body" gorm:"type:text"`
+	Link      *string    `json:"link,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index"`
+}
+
 // SavedPlayer represents scout's saved/favorited players (Scout+ tier)
 type SavedPlayer struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -475,6 +870,37 @@ type SavedPlayer struct {
 	Player *Player `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
 }
 
+// MatchVideoNote is a scout's private timestamped note on a full match video,
+// optionally about one player from the match lineup
+type MatchVideoNote struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID           uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index:idx_match_note_user_player"`
+	MatchID          uuid.UUID  `json:"match_id" gorm:"type:uuid;not null;index"`
+	MatchVideoID     uuid.UUID  `json:"match_video_id" gorm:"type:uuid;not null;index"`
+	PlayerID         *uuid.UUID `json:"player_id,omitempty" gorm:"type:uuid;index:idx_match_note_user_player"` // NULL = general note
+	TimestampSeconds int        `json:"timestamp_seconds" gorm:"not null"`
+	Body             string     `json:"body" gorm:"not null"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	Match  *Match  `json:"match,omitempty" gorm:"foreignKey:MatchID"`
+	Player *Player `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
+}
+
+// Timestamp formats the note's position in the video as m:ss (or h:mm:ss)
+func (n *MatchVideoNote) Timestamp() string {
+	return FormatTimestamp(n.TimestampSeconds)
+}
+
+// FormatTimestamp formats a position in a video as m:ss (or h:mm:ss)
+func FormatTimestamp(seconds int) string {
+	h, m, s := seconds/3600, seconds/60%60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
 // ScoutTag represents a custom tag created by a scout
 type ScoutTag struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -512,9 +938,56 @@ type VideoView struct {
 	VideoID   uuid.UUID  `json:"video_id" gorm:"type:uuid;not null;index"`
 	ViewerID  *uuid.UUID `json:"viewer_id,omitempty" gorm:"type:uuid;index"`
 	PlayerID  *uuid.UUID `json:"player_id,omitempty" gorm:"type:uuid"`
+	Source    *string    `json:"source,omitempty"`
+	IPHash    *string    `json:"-"` // Salted, for unique view counting
 	CreatedAt time.Time  `json:"created_at" gorm:"index"`
 }
 
+// ProfileView records a view of a player profile. Players only see the viewer's
+// organization type and country, and the viewer's identity when IdentityShared
+// (the scout had opted in at the time of the view).
+type ProfileView struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PlayerID         uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;index"`
+	ViewerID         *uuid.UUID `json:"-" gorm:"type:uuid;index"` // NULL for anonymous; never shown unless IdentityShared
+	ViewerRole       *string    `json:"viewer_role,omitempty"`    // scout, player
+	OrganizationType *string    `json:"organization_type,omitempty"`
+	Country          *string    `json:"country,omitempty"`
+	IdentityShared   bool       `json:"identity_shared" gorm:"default:false"`
+	Source           *string    `json:"source,omitempty"` // search, saved_players, similar, direct
+	IPHash           *string    `json:"-"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"index"`
+}
+
+// MinorAccessLog records an account reading a minor's personal data (profile,
+// full match footage, contact requests) or exporting it, for safeguarding review.
+// One row per minor, so a match stream logs every minor in the lineup.
+type MinorAccessLog struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index:idx_minor_access_user,priority:1"`
+	UserRole     string     `json:"user_role" gorm:"not null"`
+	PlayerID     uuid.UUID  `json:"player_id" gorm:"type:uuid;not null;index:idx_minor_access_player,priority:1"`
+	Action       string     `json:"action" gorm:"not null;index"` // view_profile, stream_match, view_moments, contact_request, view_contact_requests, export
+	ResourceType string     `json:"resource_type" gorm:"not null"`
+	ResourceID   *uuid.UUID `json:"resource_id,omitempty" gorm:"type:uuid"`
+	IPAddress    *string    `json:"ip_address,omitempty"`
+	UserAgent    *string    `json:"user_agent,omitempty"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"index;index:idx_minor_access_user,priority:2;index:idx_minor_access_player,priority:2"`
+}
+
+// ViewRollup is the daily view total of one asset, rebuilt from the raw view tables.
+// Per-player totals sum the rollups of the player's assets.
+type ViewRollup struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Day           time.Time  `json:"day" gorm:"type:date;not null;uniqueIndex:idx_view_rollup_unique,priority:1;index:idx_view_rollup_player,priority:2"`
+	AssetType     string     `json:"asset_type" gorm:"not null;uniqueIndex:idx_view_rollup_unique,priority:2"` // highlight, video, profile
+	AssetID       uuid.UUID  `json:"asset_id" gorm:"type:uuid;not null;uniqueIndex:idx_view_rollup_unique,priority:3"`
+	PlayerID      *uuid.UUID `json:"player_id,omitempty" gorm:"type:uuid;index:idx_view_rollup_player,priority:1"`
+	Views         int        `json:"views" gorm:"default:0"`
+	UniqueViewers int        `json:"unique_viewers" gorm:"default:0"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
 // AuditLog provides audit trail for compliance
 type AuditLog struct {
 	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -548,6 +1021,26 @@ func (PlayerHighlight) TableName() string {
 	return "player_highlights"
 }
 
+func (TournamentGroup) TableName() string {
+	return "tournament_groups"
+}
+
+func (Team) TableName() string {
+	return "teams"
+}
+
+func (TournamentStats) TableName() string {
+	return "tournament_stats"
+}
+
+func (HighlightIngest) TableName() string {
+	return "highlight_ingests"
+}
+
+func (HighlightIngestItem) TableName() string {
+	return "highlight_ingest_items"
+}
+
 func (MatchPurchase) TableName() string {
 	return "match_purchases"
 }
@@ -560,6 +1053,70 @@ func (MatchVideoView) TableName() string {
 	return "match_video_views"
 }
 
+func (MatchPlayerMoment) TableName() string {
+	return "match_player_moments"
+}
+
+func (MatchVideoNote) TableName() string {
+	return "match_video_notes"
+}
+
+func (PlayerGuardian) TableName() string {
+	return "player_guardians"
+}
+
+func (ConsentRecord) TableName() string {
+	return "consent_records"
+}
+
+func (PlayerChangeRequest) TableName() string {
+	return "player_change_requests"
+}
+
+func (PlayerVerification) TableName() string {
+	return "player_verifications"
+}
+
+func (VerificationEvent) TableName() string {
+	return "verification_events"
+}
+
+func (SchoolAttestation) TableName() string {
+	return "school_attestations"
+}
+
+func (SchoolAdmin) TableName() string {
+	return "school_admins"
+}
+
+func (TournamentRegistration) TableName() string {
+	return "tournament_registrations"
+}
+
+func (PlayerFlag) TableName() string {
+	return "player_flags"
+}
+
+func (PlayerMerge) TableName() string {
+	return "player_merges"
+}
+
+func (PlayerMergeRow) TableName() string {
+	return "player_merge_rows"
+}
+
+func (PlayerDuplicateDismissal) TableName() string {
+	return "player_duplicate_dismissals"
+}
+
+func (ProfileView) TableName() string {
+	return "profile_views"
+}
+
+func (ViewRollup) TableName() string {
+	return "view_rollups"
+}
+
 func (ScoutTag) TableName() string {
 	return "scout_tags"
 }
@@ -572,9 +1129,16 @@ func (PlayerStats) TableName() string {
 
 // GetAge calculates age from date of birth
 func (p *Player) GetAge() int {
-	now := time.Now()
-	age := now.Year() - p.DateOfBirth.Year()
-	if now.YearDay() < p.DateOfBirth.YearDay() {
+	return AgeAt(p.DateOfBirth, time.Now())
+}
+
+// AgeAt returns the age in whole years on the given day. Someone born on 29 February
+// turns a year older on 1 March in non-leap years.
+func AgeAt(dob, on time.Time) int {
+	by, bm, bd := dob.Date()
+	y, m, d := on.Date()
+	age := y - by
+	if m < bm || (m == bm && d < bd) {
 		age--
 	}
 	return age
@@ -634,3 +1198,29 @@ type Academy struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// School is a partner school that vouches for its students' identity and age
+type School struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name      string    `json:"name" gorm:"not null;index"`
+	Country   string    `json:"country" gorm:"not null;index"`
+	State     *string   `json:"state,omitempty"`
+	City      *string   `json:"city,omitempty"`
+	Address   *string   `json:"address,omitempty"`
+	Phone     *string   `json:"phone,omitempty"`
+	Email     *string   `json:"email,omitempty"`
+	IsActive  bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SchoolAdmin links a school-role user to the school they act for
+type SchoolAdmin struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SchoolID  uuid.UUID `json:"school_id" gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+
+	School *School `json:"school,omitempty" gorm:"foreignKey:SchoolID"`
+	User   *User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
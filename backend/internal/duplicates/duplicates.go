@@ -0,0 +1,239 @@
+package duplicates
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Points each kind of evidence adds to a candidate pair's score. Names must be at
+// least similar for a pair to be considered at all.
+const (
+	scoreSameName      = 50
+	scoreSimilarName   = 35
+	scoreSameDOB       = 35
+	scoreDOBSwapped    = 20 // day and month swapped, a common data entry mistake
+	scoreSameBirthYear = 10
+	scoreSameAcademy   = 10
+	scoreSameSchool    = 10
+)
+
+// DefaultMinScore is a similar name with the same date of birth, or the same name
+// with a matching academy or school and birth year
+const DefaultMinScore = 60
+
+// Reasons a pair was matched
+const (
+	ReasonSameName      = "same_name"
+	ReasonSimilarName   = "similar_name"
+	ReasonSameDOB       = "same_date_of_birth"
+	ReasonDOBSwapped    = "date_of_birth_day_month_swapped"
+	ReasonSameBirthYear = "same_birth_year"
+	ReasonSameAcademy   = "same_academy"
+	ReasonSameSchool    = "same_school"
+)
+
+// Candidate is a pair of players that may be the same person. PlayerID is the
+// older record, which is usually the one to keep.
+type Candidate struct {
+	PlayerID      uuid.UUID `json:"player_id"`
+	OtherPlayerID uuid.UUID `json:"other_player_id"`
+	Score         int       `json:"score"`
+	Reasons       []string  `json:"reasons"`
+}
+
+// candidateFields are the player fields the detector compares
+const candidateFields = "id, first_name, last_name, date_of_birth, country, academy_id, school_id, school_name, created_at"
+
+// Find returns candidate pairs scoring at least minScore, best first. Players are
+// only compared within the same country and name initials, and pairs an admin
+// has dismissed are left out.
+func Find(ctx context.Context, db *gorm.DB, minScore int) ([]Candidate, error) {
+	db = db.WithContext(ctx)
+	var players []domain.Player
+	if err := db.Select(candidateFields).Where("deleted_at IS NULL").Order("created_at").Find(&players).Error; err != nil {
+		return nil, err
+	}
+
+	var dismissals []domain.PlayerDuplicateDismissal
+	if err := db.Find(&dismissals).Error; err != nil {
+		return nil, err
+	}
+	dismissed := map[[2]uuid.UUID]bool{}
+	for _, d := range dismissals {
+		dismissed[[2]uuid.UUID{d.PlayerID, d.OtherPlayerID}] = true
+	}
+
+	blocks := map[string][]*domain.Player{}
+	for i := range players {
+		p := &players[i]
+		if key := blockKey(p); key != "" {
+			blocks[key] = append(blocks[key], p)
+		}
+	}
+
+	candidates := make([]Candidate, 0)
+	for _, block := range blocks {
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				a, b := block[i], block[j]
+				if dismissed[PairKey(a.ID, b.ID)] {
+					continue
+				}
+				score, reasons := Score(a, b)
+				if score < minScore {
+					continue
+				}
+				candidates = append(candidates, Candidate{PlayerID: a.ID, OtherPlayerID: b.ID, Score: score, Reasons: reasons})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates, nil
+}
+
+// Score rates how likely two players are the same person, with the reasons. Pairs
+// whose names aren't similar score 0.
+func Score(a, b *domain.Player) (int, []string) {
+	exact, similar := NameMatch(a.FirstName, a.LastName, b.FirstName, b.LastName)
+	var score int
+	var reasons []string
+	switch {
+	case exact:
+		score, reasons = scoreSameName, []string{ReasonSameName}
+	case similar:
+		score, reasons = scoreSimilarName, []string{ReasonSimilarName}
+	default:
+		return 0, nil
+	}
+
+	ay, am, ad := a.DateOfBirth.Date()
+	by, bm, bd := b.DateOfBirth.Date()
+	switch {
+	case ay == by && am == bm && ad == bd:
+		score += scoreSameDOB
+		reasons = append(reasons, ReasonSameDOB)
+	case ay == by && int(am) == bd && ad == int(bm):
+		score += scoreDOBSwapped
+		reasons = append(reasons, ReasonDOBSwapped)
+	case ay == by:
+		score += scoreSameBirthYear
+		reasons = append(reasons, ReasonSameBirthYear)
+	}
+
+	if a.AcademyID != nil && b.AcademyID != nil && *a.AcademyID == *b.AcademyID {
+		score += scoreSameAcademy
+		reasons = append(reasons, ReasonSameAcademy)
+	}
+	if sameSchool(a, b) {
+		score += scoreSameSchool
+		reasons = append(reasons, ReasonSameSchool)
+	}
+	return score, reasons
+}
+
+func sameSchool(a, b *domain.Player) bool {
+	if a.SchoolID != nil && b.SchoolID != nil {
+		return *a.SchoolID == *b.SchoolID
+	}
+	if a.SchoolName == nil || b.SchoolName == nil {
+		return false
+	}
+	name := NormalizeName(*a.SchoolName)
+	return name != "" && name == NormalizeName(*b.SchoolName)
+}
+
+// blockKey groups players that are worth comparing: same country and the same
+// pair of initials in either order
+func blockKey(p *domain.Player) string {
+	first, last := NormalizeName(p.FirstName), NormalizeName(p.LastName)
+	if first == "" || last == "" {
+		return ""
+	}
+	initials := []string{string([]rune(first)[:1]), string([]rune(last)[:1])}
+	sort.Strings(initials)
+	return strings.ToLower(p.Country) + "|" + initials[0] + initials[1]
+}
+
+// PairKey orders two player IDs the way dismissals store them
+func PairKey(a, b uuid.UUID) [2]uuid.UUID {
+	if strings.Compare(a.String(), b.String()) > 0 {
+		a, b = b, a
+	}
+	return [2]uuid.UUID{a, b}
+}
+
+// Dismiss records that the two players are different people so the pair isn't
+// suggested again
+func Dismiss(db *gorm.DB, playerID, otherPlayerID, adminID uuid.UUID) error {
+	key := PairKey(playerID, otherPlayerID)
+	return db.Where("player_id = ? AND other_player_id = ?", key[0], key[1]).
+		FirstOrCreate(&domain.PlayerDuplicateDismissal{
+			PlayerID:      key[0],
+			OtherPlayerID: key[1],
+			DismissedBy:   adminID,
+			CreatedAt:     time.Now(),
+		}).Error
+}
+
+// NameMatch compares two full names, in either order of first and last name.
+// similar allows one typo in short names and two in longer ones.
+func NameMatch(firstA, lastA, firstB, lastB string) (exact, similar bool) {
+	fa, la := NormalizeName(firstA), NormalizeName(lastA)
+	fb, lb := NormalizeName(firstB), NormalizeName(lastB)
+	if fa == "" || la == "" || fb == "" || lb == "" {
+		return false, false
+	}
+	name := fa + " " + la
+	straight, swapped := fb+" "+lb, lb+" "+fb
+	if name == straight || name == swapped {
+		return true, true
+	}
+	limit := 1
+	if len([]rune(name)) >= 10 {
+		limit = 2
+	}
+	return false, levenshtein(name, straight) <= limit || levenshtein(name, swapped) <= limit
+}
+
+// NormalizeName lowercases the name and keeps only letters and single spaces
+func NormalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-':
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,271 @@
+package duplicates
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Merge statuses
+const (
+	MergeMerged = "merged"
+	MergeUndone = "undone"
+)
+
+var (
+	ErrSamePlayer     = errors.New("a player can't be merged into itself")
+	ErrPlayerNotFound = errors.New("player not found")
+	ErrMergeNotFound  = errors.New("merge not found")
+	ErrMergeUndone    = errors.New("merge has already been undone")
+	ErrMergedRestored = errors.New("the merged player has been restored or merged again")
+)
+
+// reference is a column holding a player ID that a merge moves to the survivor.
+// Rows whose unique key the survivor already has are left on the merged player:
+// the survivor's own row wins.
+type reference struct {
+	table  string
+	column string
+	id     string // row identity recorded for undo
+	unique string // other column in a unique key with the player, if any
+}
+
+func (r reference) source() string {
+	return r.table + "." + r.column
+}
+
+// references are moved in this order. Moments follow their match appearance, and
+// substitutions point at the player too.
+var references = []reference{
+	{table: "match_players", column: "player_id", id: "id", unique: "match_id"},
+	{table: "match_players", column: "subbed_in_for", id: "id"},
+	{table: "match_player_moments", column: "player_id", id: "id"},
+	{table: "player_highlights", column: "player_id", id: "id"},
+	{table: "highlight_ingest_items", column: "player_id", id: "id"},
+	{table: "player_videos", column: "player_id", id: "video_id", unique: "video_id"},
+	{table: "academy_players", column: "player_id", id: "id", unique: "academy_id"},
+	{table: "saved_players", column: "player_id", id: "id", unique: "user_id"},
+	{table: "contact_requests", column: "player_id", id: "id"},
+	{table: "tournament_registrations", column: "player_id", id: "id", unique: "tournament_id"},
+}
+
+func referenceFor(source string) (reference, bool) {
+	for _, r := range references {
+		if r.source() == source {
+			return r, true
+		}
+	}
+	return reference{}, false
+}
+
+// MergeResult is a completed merge with how many rows moved from each source and
+// how many stayed behind because the survivor already had them
+type MergeResult struct {
+	Merge   *domain.PlayerMerge `json:"merge"`
+	Moved   map[string]int      `json:"moved"`
+	Skipped map[string]int      `json:"skipped"`
+}
+
+// Merge folds the merged player into the survivor in one transaction: their
+// appearances, highlights, videos, academy memberships, scouts' saves, contact
+// requests and tournament registrations are re-pointed, and the merged player is
+// soft-deleted. The merged player's login moves over if the survivor has none,
+// and is deactivated otherwise. Everything moved is recorded so Undo can put it
+// back.
+func Merge(db *gorm.DB, survivorID, mergedID, adminID uuid.UUID) (*MergeResult, error) {
+	if survivorID == mergedID {
+		return nil, ErrSamePlayer
+	}
+
+	result := &MergeResult{Moved: map[string]int{}, Skipped: map[string]int{}}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var players []domain.Player
+		if err := tx.Select("id, user_id").
+			Where("id IN ? AND deleted_at IS NULL", []uuid.UUID{survivorID, mergedID}).
+			Find(&players).Error; err != nil {
+			return err
+		}
+		if len(players) != 2 {
+			return ErrPlayerNotFound
+		}
+		var survivor, merged domain.Player
+		for _, p := range players {
+			if p.ID == survivorID {
+				survivor = p
+			} else {
+				merged = p
+			}
+		}
+
+		now := time.Now()
+		merge := &domain.PlayerMerge{
+			SurvivorID: survivorID,
+			MergedID:   mergedID,
+			Status:     MergeMerged,
+			MergedBy:   adminID,
+			CreatedAt:  now,
+		}
+		if err := tx.Create(merge).Error; err != nil {
+			return err
+		}
+
+		for _, ref := range references {
+			ids, skipped, err := movableRows(tx, ref, survivorID, mergedID)
+			if err != nil {
+				return err
+			}
+			if skipped > 0 {
+				result.Skipped[ref.source()] = skipped
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ? AND %s IN ?", ref.table, ref.column, ref.column, ref.id),
+				survivorID, mergedID, ids).Error; err != nil {
+				return err
+			}
+			rows := make([]domain.PlayerMergeRow, len(ids))
+			for i, id := range ids {
+				rows[i] = domain.PlayerMergeRow{MergeID: merge.ID, Source: ref.source(), RowID: id}
+			}
+			if err := tx.CreateInBatches(&rows, 500).Error; err != nil {
+				return err
+			}
+			result.Moved[ref.source()] = len(ids)
+		}
+
+		if merged.UserID != nil {
+			if survivor.UserID == nil {
+				if err := tx.Model(&domain.Player{}).Where("id = ?", mergedID).Update("user_id", nil).Error; err != nil {
+					return err
+				}
+				if err := tx.Model(&domain.Player{}).Where("id = ?", survivorID).Update("user_id", *merged.UserID).Error; err != nil {
+					return err
+				}
+				merge.MovedUserID = merged.UserID
+			} else {
+				if err := tx.Model(&domain.User{}).Where("id = ?", *merged.UserID).
+					Updates(map[string]interface{}{"is_active": false, "updated_at": now}).Error; err != nil {
+					return err
+				}
+				if err := tx.Where("user_id = ?", *merged.UserID).Delete(&domain.RefreshToken{}).Error; err != nil {
+					return err
+				}
+				merge.DeactivatedUserID = merged.UserID
+			}
+			if err := tx.Model(merge).Updates(map[string]interface{}{
+				"moved_user_id":       merge.MovedUserID,
+				"deactivated_user_id": merge.DeactivatedUserID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&domain.Player{}).Where("id = ?", mergedID).
+			Updates(map[string]interface{}{"deleted_at": now, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		result.Merge = merge
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// movableRows returns the merged player's rows in ref that can move, and how many
+// can't because the survivor already has a row with the same key
+func movableRows(tx *gorm.DB, ref reference, survivorID, mergedID uuid.UUID) ([]uuid.UUID, int, error) {
+	var total int64
+	if err := tx.Table(ref.table).Where(ref.column+" = ?", mergedID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	query := tx.Table(ref.table+" t").Where("t."+ref.column+" = ?", mergedID)
+	if ref.unique != "" {
+		query = query.Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s s WHERE s.%s = ? AND s.%s = t.%s)",
+			ref.table, ref.column, ref.unique, ref.unique), survivorID)
+	}
+	var ids []uuid.UUID
+	if err := query.Pluck("t."+ref.id, &ids).Error; err != nil {
+		return nil, 0, err
+	}
+	return ids, int(total) - len(ids), nil
+}
+
+// Undo reverses a merge: the moved rows go back to the merged player, which is
+// restored along with its login. Rows that have since moved on from the survivor
+// are left where they are.
+func Undo(db *gorm.DB, mergeID, adminID uuid.UUID) (*domain.PlayerMerge, error) {
+	var merge domain.PlayerMerge
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Rows").First(&merge, "id = ?", mergeID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMergeNotFound
+			}
+			return err
+		}
+		if merge.Status != MergeMerged {
+			return ErrMergeUndone
+		}
+
+		restored := tx.Model(&domain.Player{}).
+			Where("id = ? AND deleted_at IS NOT NULL", merge.MergedID).
+			Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now()})
+		if restored.Error != nil {
+			return restored.Error
+		}
+		if restored.RowsAffected == 0 {
+			return ErrMergedRestored
+		}
+
+		bySource := map[string][]uuid.UUID{}
+		for _, row := range merge.Rows {
+			bySource[row.Source] = append(bySource[row.Source], row.RowID)
+		}
+		for source, ids := range bySource {
+			ref, ok := referenceFor(source)
+			if !ok {
+				continue
+			}
+			if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ? AND %s IN ?", ref.table, ref.column, ref.column, ref.id),
+				merge.MergedID, merge.SurvivorID, ids).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		if merge.MovedUserID != nil {
+			if err := tx.Model(&domain.Player{}).Where("id = ? AND user_id = ?", merge.SurvivorID, *merge.MovedUserID).
+				Update("user_id", nil).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&domain.Player{}).Where("id = ?", merge.MergedID).
+				Update("user_id", *merge.MovedUserID).Error; err != nil {
+				return err
+			}
+		}
+		if merge.DeactivatedUserID != nil {
+			if err := tx.Model(&domain.User{}).Where("id = ?", *merge.DeactivatedUserID).
+				Updates(map[string]interface{}{"is_active": true, "updated_at": now}).Error; err != nil {
+				return err
+			}
+		}
+
+		merge.Status, merge.UndoneBy, merge.UndoneAt = MergeUndone, &adminID, &now
+		return tx.Model(&merge).Updates(map[string]interface{}{"status": MergeUndone, "undone_by": adminID, "undone_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &merge, nil
+}
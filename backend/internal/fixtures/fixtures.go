@@ -0,0 +1,455 @@
+// Package fixtures builds a tournament's structure: round-robin group fixtures,
+// seeded knockout brackets, and moving knockout winners on to their next match.
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Stages
+const (
+	StageGroup        = "group"
+	StageQuarterfinal = "quarterfinal"
+	StageSemifinal    = "semifinal"
+	StageFinal        = "final"
+)
+
+// Sides of a match
+const (
+	SideHome = "home"
+	SideAway = "away"
+)
+
+var (
+	ErrFixturesExist    = errors.New("fixtures have already been generated; replace them to start over")
+	ErrFixturesStarted  = errors.New("some generated matches have already been played, so they can't be replaced")
+	ErrNoGroups         = errors.New("the tournament has no groups")
+	ErrNotEnoughTeams   = errors.New("at least two teams are needed")
+	ErrNoWinner         = errors.New("a knockout match needs a winner: add penalty scores for a draw")
+	ErrNextMatchStarted = errors.New("the winner's next match has already started")
+)
+
+// Schedule spaces generated matches out: round n is played DaysBetweenRounds
+// days after round n-1
+type Schedule struct {
+	Start             time.Time
+	DaysBetweenRounds int
+	Location          *string
+}
+
+func (s Schedule) date(round int) time.Time {
+	return s.Start.AddDate(0, 0, (round-1)*s.DaysBetweenRounds)
+}
+
+// KnockoutStage names a knockout round by how many teams are left in it
+func KnockoutStage(teams int) string {
+	switch {
+	case teams <= 2:
+		return StageFinal
+	case teams <= 4:
+		return StageSemifinal
+	case teams <= 8:
+		return StageQuarterfinal
+	default:
+		return fmt.Sprintf("round_of_%d", teams)
+	}
+}
+
+// IsKnockout reports whether the match is part of a generated bracket
+func IsKnockout(m *domain.Match) bool {
+	return m.BracketSlot != nil
+}
+
+// RoundRobin pairs n teams (by index) so each meets every other once, by the
+// circle method. With an odd number of teams one team sits out each round.
+// Home and away alternate so no team is always at home.
+func RoundRobin(n int) [][][2]int {
+	if n < 2 {
+		return nil
+	}
+	teams := make([]int, n)
+	for i := range teams {
+		teams[i] = i
+	}
+	if n%2 == 1 {
+		teams = append(teams, -1) // bye
+	}
+	size := len(teams)
+	rounds := make([][][2]int, size-1)
+	for r := range rounds {
+		for i := 0; i < size/2; i++ {
+			home, away := teams[i], teams[size-1-i]
+			if home < 0 || away < 0 {
+				continue
+			}
+			if (i == 0 && r%2 == 1) || (i > 0 && i%2 == 1) {
+				home, away = away, home
+			}
+			rounds[r] = append(rounds[r], [2]int{home, away})
+		}
+		// Keep the first team fixed and rotate the rest
+		last := teams[size-1]
+		copy(teams[2:], teams[1:size-1])
+		teams[1] = last
+	}
+	return rounds
+}
+
+// Seeding returns the bracket order of seeds 1..size (a power of two), so the top
+// seeds can only meet in the latest rounds: 1, 8, 4, 5, 2, 7, 3, 6 for eight
+func Seeding(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		n := len(order) * 2
+		next := make([]int, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// GenerateGroupFixtures creates the round-robin matches of every group in the
+// tournament. With replace, previously generated group matches are deleted first,
+// as long as none of them has been played.
+func GenerateGroupFixtures(tx *gorm.DB, tournamentID uuid.UUID, schedule Schedule, doubleRoundRobin, replace bool, createdBy uuid.UUID) ([]domain.Match, error) {
+	var groups []domain.TournamentGroup
+	if err := tx.Preload("Teams", func(db *gorm.DB) *gorm.DB { return db.Order("seed NULLS LAST, name") }).
+		Where("tournament_id = ?", tournamentID).Order("position, name").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, ErrNoGroups
+	}
+	for _, g := range groups {
+		if len(g.Teams) < 2 {
+			return nil, fmt.Errorf("%s: %w", g.Name, ErrNotEnoughTeams)
+		}
+	}
+	if err := clearGenerated(tx, tournamentID, "group_id IS NOT NULL", replace); err != nil {
+		return nil, err
+	}
+
+	number, err := nextMatchNumber(tx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	stage := StageGroup
+	var matches []domain.Match
+	for _, g := range groups {
+		rounds := RoundRobin(len(g.Teams))
+		legs := 1
+		if doubleRoundRobin {
+			legs = 2
+		}
+		for leg := 0; leg < legs; leg++ {
+			for r, pairs := range rounds {
+				round := leg*len(rounds) + r + 1
+				for _, pair := range pairs {
+					home, away := g.Teams[pair[0]], g.Teams[pair[1]]
+					if leg == 1 {
+						home, away = away, home
+					}
+					groupID, n, rd := g.ID, number, round
+					number++
+					matches = append(matches, domain.Match{
+						Title:        fmt.Sprintf("%s vs %s", home.Name, away.Name),
+						MatchDate:    schedule.date(round),
+						Location:     schedule.Location,
+						Stage:        &stage,
+						HomeTeam:     &home.Name,
+						AwayTeam:     &away.Name,
+						MatchNumber:  &n,
+						Status:       "scheduled",
+						TournamentID: &tournamentID,
+						HomeTeamID:   &home.ID,
+						AwayTeamID:   &away.ID,
+						GroupID:      &groupID,
+						Round:        &rd,
+						CreatedBy:    createdBy,
+					})
+				}
+			}
+		}
+	}
+	if err := tx.CreateInBatches(&matches, 100).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// GenerateBracket creates a single-elimination bracket for the teams, given in
+// seed order. When the number of teams isn't a power of two the top seeds get a
+// bye and start in the second round. With replace, a previously generated bracket
+// is deleted first, as long as none of it has been played.
+func GenerateBracket(tx *gorm.DB, tournamentID uuid.UUID, teams []domain.Team, schedule Schedule, replace bool, createdBy uuid.UUID) ([]domain.Match, error) {
+	if len(teams) < 2 {
+		return nil, ErrNotEnoughTeams
+	}
+	if err := clearGenerated(tx, tournamentID, "bracket_slot IS NOT NULL", replace); err != nil {
+		return nil, err
+	}
+
+	size := 2
+	for size < len(teams) {
+		size *= 2
+	}
+	rounds := 0
+	for n := size; n > 1; n /= 2 {
+		rounds++
+	}
+
+	// Create the final first so every match can point at the one its winner plays next
+	bracket := make([][]*domain.Match, rounds+1) // by round, from 1
+	for round := rounds; round >= 1; round-- {
+		count := size >> round
+		stage := KnockoutStage(count * 2)
+		for slot := 1; slot <= count; slot++ {
+			r, s := round, slot
+			match := &domain.Match{
+				Title:        knockoutTitle(stage, slot, count),
+				MatchDate:    schedule.date(round),
+				Location:     schedule.Location,
+				Stage:        &stage,
+				Status:       "scheduled",
+				TournamentID: &tournamentID,
+				Round:        &r,
+				BracketSlot:  &s,
+				CreatedBy:    createdBy,
+			}
+			if round < rounds {
+				next := bracket[round+1][(slot-1)/2]
+				side := SideHome
+				if slot%2 == 0 {
+					side = SideAway
+				}
+				match.NextMatchID, match.NextMatchSide = &next.ID, &side
+			}
+			bracket[round] = append(bracket[round], match)
+		}
+		// First-round matches with a bye are never played
+		if round == 1 {
+			break
+		}
+		if err := tx.Create(bracket[round]).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	seeds := Seeding(size)
+	var firstRound []*domain.Match
+	for i, match := range bracket[1] {
+		home, away := seeds[2*i], seeds[2*i+1]
+		switch {
+		case away > len(teams):
+			// The home seed has a bye and goes straight into the second round
+			setSide(bracket[2][i/2], *match.NextMatchSide, &teams[home-1])
+		default:
+			setSide(match, SideHome, &teams[home-1])
+			setSide(match, SideAway, &teams[away-1])
+			firstRound = append(firstRound, match)
+		}
+	}
+	if len(firstRound) > 0 {
+		if err := tx.Create(firstRound).Error; err != nil {
+			return nil, err
+		}
+	}
+	// Later rounds were created before the byes were placed
+	for _, matches := range bracket[2:] {
+		for _, m := range matches {
+			if err := tx.Model(m).Updates(map[string]interface{}{
+				"home_team_id": m.HomeTeamID, "home_team": m.HomeTeam,
+				"away_team_id": m.AwayTeamID, "away_team": m.AwayTeam,
+			}).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Number matches in the order they're played
+	number, err := nextMatchNumber(tx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	var matches []domain.Match
+	for round := 1; round <= rounds; round++ {
+		for _, m := range bracket[round] {
+			if m.ID == uuid.Nil {
+				continue // a bye
+			}
+			n := number
+			number++
+			if err := tx.Model(m).Update("match_number", n).Error; err != nil {
+				return nil, err
+			}
+			m.MatchNumber = &n
+			matches = append(matches, *m)
+		}
+	}
+	return matches, nil
+}
+
+func knockoutTitle(stage string, slot, count int) string {
+	name := map[string]string{
+		StageFinal:        "Final",
+		StageSemifinal:    "Semifinal",
+		StageQuarterfinal: "Quarterfinal",
+	}[stage]
+	if name == "" {
+		name = fmt.Sprintf("Round of %d", count*2)
+	}
+	if count == 1 {
+		return name
+	}
+	return fmt.Sprintf("%s %d", name, slot)
+}
+
+func setSide(m *domain.Match, side string, team *domain.Team) {
+	var id *uuid.UUID
+	var name *string
+	if team != nil {
+		id, name = &team.ID, &team.Name
+	}
+	if side == SideHome {
+		m.HomeTeamID, m.HomeTeam = id, name
+	} else {
+		m.AwayTeamID, m.AwayTeam = id, name
+	}
+}
+
+// clearGenerated deletes the tournament's generated matches matching where, or
+// refuses if there are some and replace isn't set
+func clearGenerated(tx *gorm.DB, tournamentID uuid.UUID, where string, replace bool) error {
+	var existing []domain.Match
+	if err := tx.Select("id, status").Where("tournament_id = ?", tournamentID).Where(where).Find(&existing).Error; err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+	if !replace {
+		return ErrFixturesExist
+	}
+	ids := make([]uuid.UUID, len(existing))
+	for i, m := range existing {
+		if m.Status != "scheduled" && m.Status != "cancelled" {
+			return ErrFixturesStarted
+		}
+		ids[i] = m.ID
+	}
+	return tx.Delete(&domain.Match{}, "id IN ?", ids).Error
+}
+
+func nextMatchNumber(tx *gorm.DB, tournamentID uuid.UUID) (int, error) {
+	var last int
+	if err := tx.Model(&domain.Match{}).Where("tournament_id = ?", tournamentID).
+		Select("COALESCE(MAX(match_number), 0)").Row().Scan(&last); err != nil {
+		return 0, err
+	}
+	return last + 1, nil
+}
+
+// Winner returns the team that won a completed match, going to penalties if the
+// score was level. It returns nil for a draw, or if the teams aren't known.
+func Winner(m *domain.Match) *uuid.UUID {
+	if m.HomeTeamID == nil || m.AwayTeamID == nil || m.HomeScore == nil || m.AwayScore == nil {
+		return nil
+	}
+	home, away := *m.HomeScore, *m.AwayScore
+	if home == away && m.HomePenalties != nil && m.AwayPenalties != nil {
+		home, away = *m.HomePenalties, *m.AwayPenalties
+	}
+	switch {
+	case home > away:
+		return m.HomeTeamID
+	case away > home:
+		return m.AwayTeamID
+	}
+	return nil
+}
+
+// Advance brings a knockout match's winner in line with its result: a completed
+// match records its winner and puts them into their next match, and a match that
+// is no longer completed takes its winner back out. The next match can't change
+// once it has started.
+func Advance(tx *gorm.DB, m *domain.Match) error {
+	if !IsKnockout(m) {
+		return nil
+	}
+	var winner *uuid.UUID
+	if m.Status == "completed" {
+		if winner = Winner(m); winner == nil {
+			return ErrNoWinner
+		}
+	}
+	if err := tx.Model(&domain.Match{}).Where("id = ?", m.ID).Update("winner_team_id", winner).Error; err != nil {
+		return err
+	}
+	m.WinnerTeamID = winner
+	if m.NextMatchID == nil || m.NextMatchSide == nil {
+		return nil
+	}
+
+	var next domain.Match
+	if err := tx.First(&next, "id = ?", *m.NextMatchID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	current := next.HomeTeamID
+	if *m.NextMatchSide == SideAway {
+		current = next.AwayTeamID
+	}
+	if sameTeam(current, winner) {
+		return nil
+	}
+	if next.Status != "scheduled" {
+		return ErrNextMatchStarted
+	}
+
+	var team *domain.Team
+	if winner != nil {
+		team = &domain.Team{}
+		if err := tx.First(team, "id = ?", *winner).Error; err != nil {
+			return err
+		}
+	}
+	setSide(&next, *m.NextMatchSide, team)
+	column := *m.NextMatchSide
+	return tx.Model(&next).Updates(map[string]interface{}{
+		column + "_team_id": teamID(team),
+		column + "_team":    teamName(team),
+		"updated_at":        time.Now(),
+	}).Error
+}
+
+func sameTeam(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func teamID(t *domain.Team) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.ID
+}
+
+func teamName(t *domain.Team) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Name
+}
@@ -0,0 +1,158 @@
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the MIME type for HLS playlists
+const ContentType = "application/vnd.apple.mpegurl"
+
+var uriAttrPattern = regexp.MustCompile(`URI="([^"]*)"`)
+
+// URLFunc turns a URI from the playlist (segment, init section or key) into the URL
+// the player should fetch
+type URLFunc func(uri string) (string, error)
+
+// IsMaster reports whether a playlist is a master (multivariant) playlist
+func IsMaster(src []byte) bool {
+	return bytes.Contains(src, []byte("#EXT-X-STREAM-INF"))
+}
+
+// Window cuts a VOD media playlist down to the segments overlapping
+// [startSeconds, startSeconds+durationSeconds) and rewrites every URI with urlFor.
+// Segments are kept whole, so the window can run slightly long at either edge.
+// Returns the playlist and the actual start/duration covered.
+func Window(src []byte, startSeconds, durationSeconds float64, urlFor URLFunc) ([]byte, float64, float64, error) {
+	if IsMaster(src) {
+		return nil, 0, 0, errors.New("hls: master playlists can't be windowed, use a media playlist")
+	}
+	endSeconds := startSeconds + durationSeconds
+
+	var header, body bytes.Buffer
+	var pending []string // tags attached to the next segment
+	var keyLine, mapLine string
+	var elapsed, pendingDuration, windowStart, windowDuration float64
+	firstIncluded := -1
+	segment := 0
+	mediaSequence := 0
+
+	rewrite := func(line string) (string, error) {
+		var rewriteErr error
+		out := uriAttrPattern.ReplaceAllStringFunc(line, func(m string) string {
+			u, err := urlFor(uriAttrPattern.FindStringSubmatch(m)[1])
+			if err != nil {
+				rewriteErr = err
+			}
+			return `URI="` + u + `"`
+		})
+		return out, rewriteErr
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+
+		case strings.HasPrefix(line, "#EXTINF:"):
+			value := strings.TrimPrefix(line, "#EXTINF:")
+			if idx := strings.Index(value, ","); idx >= 0 {
+				value = value[:idx]
+			}
+			d, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("hls: invalid segment duration %q", line)
+			}
+			pendingDuration = d
+			pending = append(pending, line)
+
+		case strings.HasPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"):
+			mediaSequence, _ = strconv.Atoi(strings.TrimPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"))
+
+		case strings.HasPrefix(line, "#EXT-X-PLAYLIST-TYPE"), strings.HasPrefix(line, "#EXT-X-ENDLIST"):
+			// Re-emitted below; the window is always a finished VOD playlist
+
+		case strings.HasPrefix(line, "#EXT-X-KEY"), strings.HasPrefix(line, "#EXT-X-MAP"):
+			if strings.HasPrefix(line, "#EXT-X-KEY") {
+				keyLine = line
+			} else {
+				mapLine = line
+			}
+			// Changes inside the window are passed through with the next segment
+			if firstIncluded >= 0 {
+				pending = append(pending, line)
+			}
+
+		case strings.HasPrefix(line, "#EXTM3U"):
+
+		case strings.HasPrefix(line, "#EXT-X-BYTERANGE"), strings.HasPrefix(line, "#EXT-X-DISCONTINUITY"), strings.HasPrefix(line, "#EXT-X-PROGRAM-DATE-TIME"):
+			pending = append(pending, line)
+
+		case strings.HasPrefix(line, "#"):
+			if segment == 0 {
+				header.WriteString(line + "\n")
+			}
+
+		default:
+			// Segment URI
+			segStart := elapsed
+			elapsed += pendingDuration
+			if segStart < endSeconds && elapsed > startSeconds {
+				if firstIncluded < 0 {
+					firstIncluded = segment
+					windowStart = segStart
+					// Carry the key and init section in effect at the window's first segment
+					for _, tag := range []string{keyLine, mapLine} {
+						if tag == "" {
+							continue
+						}
+						rewritten, err := rewrite(tag)
+						if err != nil {
+							return nil, 0, 0, err
+						}
+						body.WriteString(rewritten + "\n")
+					}
+				}
+				for _, tag := range pending {
+					rewritten, err := rewrite(tag)
+					if err != nil {
+						return nil, 0, 0, err
+					}
+					body.WriteString(rewritten + "\n")
+				}
+				u, err := urlFor(line)
+				if err != nil {
+					return nil, 0, 0, err
+				}
+				body.WriteString(u + "\n")
+				windowDuration = elapsed - windowStart
+			}
+			pending = pending[:0]
+			pendingDuration = 0
+			segment++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+	if firstIncluded < 0 {
+		return nil, 0, 0, errors.New("hls: no segments in the requested window")
+	}
+
+	var out bytes.Buffer
+	out.WriteString("#EXTM3U\n")
+	out.Write(header.Bytes())
+	out.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+	out.WriteString(fmt.Sprintf("#EXT-X-MEDIA-SEQUENCE:%d\n", mediaSequence+firstIncluded))
+	out.Write(body.Bytes())
+	out.WriteString("#EXT-X-ENDLIST\n")
+	return out.Bytes(), windowStart, windowDuration, nil
+}
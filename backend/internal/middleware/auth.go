@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // JWTClaims represents JWT claims
@@ -72,6 +73,53 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
+// PlayerMiddleware ensures user has player role
+func PlayerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("user_role")
+		if !exists || role != "player" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Player access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// SchoolMiddleware ensures user has school role
+func SchoolMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("user_role")
+		if !exists || role != "school" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "School access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// PasswordChangeMiddleware blocks players and school staff still on their temporary
+// password. The /auth routes (change-password, me, logout) sit outside this middleware.
+func PasswordChangeMiddleware(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, _ := c.Get("user_role"); role != "player" && role != "school" {
+			c.Next()
+			return
+		}
+
+		var mustChange bool
+		userID, _ := c.Get("user_id")
+		db.Table("users").Select("must_change_password").Where("id = ?", userID).Scan(&mustChange)
+		if mustChange {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Password change required", "code": "PASSWORD_CHANGE_REQUIRED"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // CORSMiddleware handles CORS
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -1,38 +1,51 @@
 package admin
 
 import (
-	"context"
 	"crypto/rand"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
+	"github.com/unicorn-sport/backend/internal/access"
+	"github.com/unicorn-sport/backend/internal/agegroup"
+	"github.com/unicorn-sport/backend/internal/consent"
 	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/duplicates"
+	"github.com/unicorn-sport/backend/internal/playerimport"
+	"github.com/unicorn-sport/backend/internal/registrations"
+	"github.com/unicorn-sport/backend/internal/standings"
+	"github.com/unicorn-sport/backend/internal/storage"
+	"github.com/unicorn-sport/backend/internal/uploads"
+	"github.com/unicorn-sport/backend/internal/verification"
+	"github.com/unicorn-sport/backend/internal/views"
 )
 
 // AdminModule handles admin operations
 type AdminModule struct {
-	db       *gorm.DB
-	s3Client *s3.Client
-	s3Bucket string
+	db      *gorm.DB
+	store   storage.Store
+	uploads *uploads.Service
+	views   *views.Tracker
 }
 
 // NewAdminModule creates a new admin module
-func NewAdminModule(db *gorm.DB, s3Client *s3.Client, s3Bucket string) *AdminModule {
+func NewAdminModule(db *gorm.DB, store storage.Store, uploadService *uploads.Service, viewTracker *views.Tracker) *AdminModule {
 	return &AdminModule{
-		db:       db,
-		s3Client: s3Client,
-		s3Bucket: s3Bucket,
+		db:      db,
+		store:   store,
+		uploads: uploadService,
+		views:   viewTracker,
 	}
 }
 
@@ -357,6 +370,338 @@ func (m *AdminModule) DeleteAcademy(c *gin.Context) {
 	})
 }
 
+// --- School Management ---
+
+// SchoolRequest creates or updates a partner school
+type SchoolRequest struct {
+	Name     string  `json:"name" binding:"required,max=255"`
+	Country  string  `json:"country" binding:"required"`
+	State    *string `json:"state,omitempty"`
+	City     *string `json:"city,omitempty"`
+	Address  *string `json:"address,omitempty"`
+	Phone    *string `json:"phone,omitempty"`
+	Email    *string `json:"email,omitempty" binding:"omitempty,email"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+// schoolSummary is a school with its student and queue counts
+type schoolSummary struct {
+	domain.School
+	StudentCount        int64 `json:"student_count"`
+	PendingAttestations int64 `json:"pending_attestations"`
+}
+
+const schoolCountsSelect = `schools.*,
+	(SELECT COUNT(*) FROM players p WHERE p.school_id = schools.id AND p.deleted_at IS NULL) AS student_count,
+	(SELECT COUNT(*) FROM school_attestations a WHERE a.school_id = schools.id AND a.status = 'pending') AS pending_attestations`
+
+// ListSchools returns partner schools with their student and pending attestation counts
+func (m *AdminModule) ListSchools(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	query := m.db.Model(&domain.School{})
+	if search := c.Query("q"); search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(city) LIKE ?", pattern, pattern)
+	}
+	if country := c.Query("country"); country != "" {
+		query = query.Where("LOWER(country) = LOWER(?)", country)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var schools []schoolSummary
+	if err := query.Select(schoolCountsSelect).Order("name ASC").Offset((page - 1) * perPage).Limit(perPage).Scan(&schools).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to fetch schools"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"schools":  schools,
+			"total":    total,
+			"page":     page,
+			"per_page": perPage,
+		},
+	})
+}
+
+// CreateSchool adds a partner school
+func (m *AdminModule) CreateSchool(c *gin.Context) {
+	var req SchoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	school := domain.School{IsActive: true, CreatedAt: time.Now()}
+	applySchoolRequest(&school, req)
+	if err := m.db.Create(&school).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to create school"}})
+		return
+	}
+
+	m.logAudit(c, "create_school", "school", &school.ID, nil)
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": school})
+}
+
+// GetSchool returns a school with its staff accounts and counts
+func (m *AdminModule) GetSchool(c *gin.Context) {
+	var school schoolSummary
+	if err := m.db.Model(&domain.School{}).Select(schoolCountsSelect).Where("id = ?", c.Param("id")).Take(&school).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "School not found"}})
+		return
+	}
+
+	var admins []domain.SchoolAdmin
+	m.db.Preload("User").Where("school_id = ?", school.ID).Order("created_at ASC").Find(&admins)
+	staff := make([]gin.H, 0, len(admins))
+	for _, a := range admins {
+		if a.User == nil {
+			continue
+		}
+		staff = append(staff, gin.H{
+			"user_id":       a.UserID,
+			"email":         a.User.Email,
+			"first_name":    a.User.FirstName,
+			"last_name":     a.User.LastName,
+			"is_active":     a.User.IsActive,
+			"last_login_at": a.User.LastLoginAt,
+			"added_at":      a.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"school": school, "admins": staff}})
+}
+
+// UpdateSchool edits a school. Linked players' school_name follows a rename.
+func (m *AdminModule) UpdateSchool(c *gin.Context) {
+	var school domain.School
+	if err := m.db.First(&school, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "School not found"}})
+		return
+	}
+
+	var req SchoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	renamed := req.Name != school.Name
+	applySchoolRequest(&school, req)
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&school).Error; err != nil {
+			return err
+		}
+		if !renamed {
+			return nil
+		}
+		return tx.Model(&domain.Player{}).Where("school_id = ?", school.ID).Update("school_name", school.Name).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update school"}})
+		return
+	}
+
+	m.logAudit(c, "update_school", "school", &school.ID, nil)
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": school})
+}
+
+func applySchoolRequest(school *domain.School, req SchoolRequest) {
+	school.Name = strings.TrimSpace(req.Name)
+	school.Country = req.Country
+	school.State = req.State
+	school.City = req.City
+	school.Address = req.Address
+	school.Phone = req.Phone
+	school.Email = req.Email
+	if req.IsActive != nil {
+		school.IsActive = *req.IsActive
+	}
+	school.UpdatedAt = time.Now()
+}
+
+// AddSchoolAdminRequest creates a staff account for a school
+type AddSchoolAdminRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	FirstName string `json:"first_name" binding:"required"`
+	LastName  string `json:"last_name" binding:"required"`
+}
+
+// AddSchoolAdmin creates a school-role account with a temporary password that must
+// be changed on first login
+func (m *AdminModule) AddSchoolAdmin(c *gin.Context) {
+	var school domain.School
+	if err := m.db.First(&school, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "School not found"}})
+		return
+	}
+
+	var req AddSchoolAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	var existing int64
+	m.db.Model(&domain.User{}).Where("email = ?", email).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "EMAIL_EXISTS", "message": "An account with this email already exists"}})
+		return
+	}
+
+	tempPassword := generateTempPassword()
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(tempPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to create account"}})
+		return
+	}
+
+	user := domain.User{
+		Email:              email,
+		PasswordHash:       string(hashedPassword),
+		FirstName:          req.FirstName,
+		LastName:           req.LastName,
+		Role:               "school",
+		IsActive:           true,
+		MustChangePassword: true,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&domain.SchoolAdmin{SchoolID: school.ID, UserID: user.ID, CreatedAt: time.Now()}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to create account"}})
+		return
+	}
+
+	details := fmt.Sprintf(`{"user_id":"%s"}`, user.ID)
+	m.logAudit(c, "add_school_admin", "school", &school.ID, &details)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"user_id":     user.ID,
+			"credentials": Credentials{Email: email, TempPassword: tempPassword},
+		},
+	})
+}
+
+// RemoveSchoolAdmin unlinks a staff account from the school and deactivates it
+func (m *AdminModule) RemoveSchoolAdmin(c *gin.Context) {
+	schoolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid school ID"}})
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid user ID"}})
+		return
+	}
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("school_id = ? AND user_id = ?", schoolID, userID).Delete(&domain.SchoolAdmin{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(&domain.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{"is_active": false, "updated_at": time.Now()}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.RefreshToken{}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "School admin not found"}})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "DELETE_FAILED", "message": "Failed to remove school admin"}})
+		return
+	}
+
+	details := fmt.Sprintf(`{"user_id":"%s"}`, userID)
+	m.logAudit(c, "remove_school_admin", "school", &schoolID, &details)
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "School admin removed"})
+}
+
+// ListSchoolAttestations returns school attestations, pending by default. Use
+// status=disputed for the ones that need an admin to follow up.
+func (m *AdminModule) ListSchoolAttestations(c *gin.Context) {
+	query := m.db.Preload("School").Preload("Player")
+	if status := c.DefaultQuery("status", verification.AttestationPending); status != "all" {
+		query = query.Where("status = ?", status)
+	}
+	if schoolID := c.Query("school_id"); schoolID != "" {
+		query = query.Where("school_id = ?", schoolID)
+	}
+
+	var attestations []domain.SchoolAttestation
+	if err := query.Order("updated_at DESC").Limit(200).Find(&attestations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to fetch attestations"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": attestations})
+}
+
+// RequestSchoolAttestation asks the player's school to confirm them again, e.g.
+// after a dispute has been sorted out
+func (m *AdminModule) RequestSchoolAttestation(c *gin.Context) {
+	player, ok := m.findPlayer(c)
+	if !ok {
+		return
+	}
+	if player.SchoolID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "NO_SCHOOL", "message": "Link the player to a school first"}})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	attestation, err := verification.RequestAttestation(m.db, player.ID, *player.SchoolID, adminID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to request attestation"}})
+		return
+	}
+
+	m.logAudit(c, "request_school_attestation", "school_attestation", &attestation.ID, nil)
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": attestation})
+}
+
+// findSchoolByID loads the school a player is being linked to, writing the error
+// response when it's invalid
+func (m *AdminModule) findSchoolByID(c *gin.Context, id string) (*domain.School, bool) {
+	sid, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid school_id"}})
+		return nil, false
+	}
+	var school domain.School
+	if err := m.db.First(&school, "id = ?", sid).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "School not found"}})
+		return nil, false
+	}
+	return &school, true
+}
+
 // --- Contact Request Management ---
 
 // ListContactRequests returns contact requests filtered by status
@@ -448,103 +793,575 @@ func (m *AdminModule) RejectContactRequest(c *gin.Context) {
 	})
 }
 
-// --- Player Management ---
+// --- Player Change Requests ---
 
-// CreatePlayerRequest represents the request to create a player
-type CreatePlayerRequest struct {
-	FirstName           string  `json:"first_name" binding:"required"`
-	LastName            string  `json:"last_name" binding:"required"`
-	DateOfBirth         string  `json:"date_of_birth" binding:"required"`
-	Position            string  `json:"position" binding:"required"`
-	PreferredFoot       *string `json:"preferred_foot,omitempty"`
-	HeightCm            *int    `json:"height_cm,omitempty"`
-	WeightKg            *int    `json:"weight_kg,omitempty"`
-	Country             string  `json:"country" binding:"required"`
-	State               *string `json:"state,omitempty"`
-	City                *string `json:"city,omitempty"`
-	AcademyID           *string `json:"academy_id,omitempty"`
-	SchoolName          *string `json:"school_name,omitempty"`
-	TournamentID        *string `json:"tournament_id,omitempty"`
-	VerificationStatus  string  `json:"verification_status,omitempty"`
-	VerificationDocType *string `json:"verification_doc_type,omitempty"`
-}
+// ListPlayerChangeRequests returns profile edits submitted by players, oldest first,
+// each with the player's current values for comparison
+func (m *AdminModule) ListPlayerChangeRequests(c *gin.Context) {
+	status := c.DefaultQuery("status", "pending")
 
-// CreatePlayerResponse includes generated credentials
-type CreatePlayerResponse struct {
-	ID          uuid.UUID    `json:"id"`
-	FirstName   string       `json:"first_name"`
-	LastName    string       `json:"last_name"`
-	Credentials *Credentials `json:"credentials,omitempty"`
-	CreatedAt   time.Time    `json:"created_at"`
-}
+	var changes []domain.PlayerChangeRequest
+	query := m.db.Preload("Player")
+	if status != "all" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at ASC").Limit(200).Find(&changes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to fetch change requests"}})
+		return
+	}
 
-// Credentials for player login
-type Credentials struct {
-	Email        string `json:"email"`
-	TempPassword string `json:"temp_password"`
-}
+	response := make([]gin.H, 0, len(changes))
+	for _, change := range changes {
+		item := gin.H{
+			"id":          change.ID,
+			"player_id":   change.PlayerID,
+			"status":      change.Status,
+			"note":        change.Note,
+			"review_note": change.ReviewNote,
+			"reviewed_at": change.ReviewedAt,
+			"created_at":  change.CreatedAt,
+		}
 
-// CreatePlayer creates a new player profile (admin only)
-func (m *AdminModule) CreatePlayer(c *gin.Context) {
-	var req CreatePlayerRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
-		return
+		proposed := gin.H{}
+		if change.SchoolName != nil {
+			proposed["school_name"] = *change.SchoolName
+		}
+		if change.City != nil {
+			proposed["city"] = *change.City
+		}
+		if change.HeightCm != nil {
+			proposed["height_cm"] = *change.HeightCm
+		}
+		if change.SecondaryPositions != nil {
+			proposed["secondary_positions"] = *change.SecondaryPositions
+		}
+		if change.PhotoURL != nil {
+			proposed["profile_photo_url"] = storage.SignedURL(m.store, *change.PhotoURL)
+		}
+		item["proposed"] = proposed
+
+		if p := change.Player; p != nil {
+			var photoURL *string
+			if p.ProfilePhotoURL != nil && *p.ProfilePhotoURL != "" {
+				url := storage.SignedURL(m.store, *p.ProfilePhotoURL)
+				photoURL = &url
+			}
+			item["player"] = gin.H{
+				"first_name": p.FirstName,
+				"last_name":  p.LastName,
+				"position":   p.Position,
+			}
+			item["current"] = gin.H{
+				"school_name":         p.SchoolName,
+				"city":                p.City,
+				"height_cm":           p.HeightCm,
+				"secondary_positions": p.SecondaryPositions,
+				"profile_photo_url":   photoURL,
+			}
+		}
+		response = append(response, item)
 	}
 
-	// Parse date of birth
-	dob, err := time.Parse("2006-01-02", req.DateOfBirth)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DATE", "message": "Invalid date_of_birth format, use YYYY-MM-DD"}})
-		return
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": response})
+}
+
+// ApprovePlayerChangeRequest applies a pending change request to the player profile
+func (m *AdminModule) ApprovePlayerChangeRequest(c *gin.Context) {
+	var req struct {
+		Note *string `json:"note"`
 	}
+	c.ShouldBindJSON(&req)
 
-	// Get admin user ID from context
-	adminID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": gin.H{"code": "UNAUTHORIZED", "message": "User not found"}})
+	change, ok := m.pendingChangeRequest(c)
+	if !ok {
 		return
 	}
 
-	// Parse tournament ID if provided
-	var tournamentID *uuid.UUID
-	var tournamentYear *int
-	if req.TournamentID != nil && *req.TournamentID != "" {
-		tid, err := uuid.Parse(*req.TournamentID)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid tournament_id"}})
-			return
-		}
-		tournamentID = &tid
-
-		// Get tournament year
-		var tournament domain.Tournament
-		if err := m.db.First(&tournament, "id = ?", tid).Error; err == nil {
-			tournamentYear = &tournament.Year
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	leavesSchool := false
+	if change.SchoolName != nil {
+		updates["school_name"] = *change.SchoolName
+
+		// Moving to another school ends the partner school link and its attestation
+		var player domain.Player
+		if err := m.db.Preload("School").Select("id, school_id").First(&player, "id = ?", change.PlayerID).Error; err == nil &&
+			player.School != nil && !strings.EqualFold(strings.TrimSpace(*change.SchoolName), player.School.Name) {
+			updates["school_id"] = nil
+			leavesSchool = true
 		}
 	}
+	if change.City != nil {
+		updates["city"] = *change.City
+	}
+	if change.HeightCm != nil {
+		updates["height_cm"] = *change.HeightCm
+	}
+	if change.SecondaryPositions != nil {
+		updates["secondary_positions"] = *change.SecondaryPositions
+	}
+	if change.PhotoURL != nil {
+		updates["profile_photo_url"] = *change.PhotoURL
+	}
 
-	// Parse academy ID if provided
-	var academyID *uuid.UUID
-	if req.AcademyID != nil && *req.AcademyID != "" {
-		aid, err := uuid.Parse(*req.AcademyID)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid academy_id"}})
-			return
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := m.reviewChangeRequest(tx, change, "approved", adminID, req.Note); err != nil {
+			return err
 		}
-		// Verify academy exists
-		var academy domain.Academy
-		if err := m.db.First(&academy, "id = ?", aid).Error; err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Academy not found"}})
-			return
+		if leavesSchool {
+			if err := verification.CancelAttestations(tx, change.PlayerID); err != nil {
+				return err
+			}
 		}
-		academyID = &aid
+		return tx.Model(&domain.Player{}).Where("id = ?", change.PlayerID).Updates(updates).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to apply changes"}})
+		return
 	}
-
-	// Set verification status
-	verificationStatus := "pending"
-	if req.VerificationStatus != "" {
-		verificationStatus = req.VerificationStatus
+	if change.HeightCm != nil {
+		agegroup.Recheck(m.db, change.PlayerID)
+	}
+
+	details := fmt.Sprintf(`{"player_id":"%s"}`, change.PlayerID)
+	m.logAudit(c, "approve_player_change", "player_change_request", &change.ID, &details)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": change})
+}
+
+// RejectPlayerChangeRequest declines a pending change request. The reason is shown
+// to the player.
+func (m *AdminModule) RejectPlayerChangeRequest(c *gin.Context) {
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": "A reason is required"}})
+		return
+	}
+
+	change, ok := m.pendingChangeRequest(c)
+	if !ok {
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	if err := m.reviewChangeRequest(m.db, change, "rejected", adminID, &req.Reason); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to reject changes"}})
+		return
+	}
+
+	details := fmt.Sprintf(`{"player_id":"%s"}`, change.PlayerID)
+	m.logAudit(c, "reject_player_change", "player_change_request", &change.ID, &details)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": change})
+}
+
+// pendingChangeRequest loads the change request in the URL, writing the error
+// response when it doesn't exist or was already handled
+func (m *AdminModule) pendingChangeRequest(c *gin.Context) (*domain.PlayerChangeRequest, bool) {
+	var change domain.PlayerChangeRequest
+	if err := m.db.First(&change, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Change request not found"}})
+		return nil, false
+	}
+	if change.Status != "pending" {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "ALREADY_REVIEWED", "message": "Change request is already " + change.Status}})
+		return nil, false
+	}
+	return &change, true
+}
+
+// reviewChangeRequest closes a change request, failing if someone else (or the
+// player cancelling) got there first
+func (m *AdminModule) reviewChangeRequest(tx *gorm.DB, change *domain.PlayerChangeRequest, status string, adminID uuid.UUID, note *string) error {
+	now := time.Now()
+	result := tx.Model(&domain.PlayerChangeRequest{}).
+		Where("id = ? AND status = ?", change.ID, "pending").
+		Updates(map[string]interface{}{
+			"status":      status,
+			"review_note": note,
+			"reviewed_by": adminID,
+			"reviewed_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("change request %s is no longer pending", change.ID)
+	}
+
+	change.Status = status
+	change.ReviewNote = note
+	change.ReviewedBy = &adminID
+	change.ReviewedAt = &now
+	return nil
+}
+
+// --- Player Verification ---
+
+// ListVerifications returns the verification queue. status is open (default),
+// all, or one status; claimed=me narrows it to the reviewer's own claims and
+// claimed=none to unclaimed submissions.
+func (m *AdminModule) ListVerifications(c *gin.Context) {
+	adminID := c.MustGet("user_id").(uuid.UUID)
+
+	query := m.db.Preload("Player")
+	switch status := c.DefaultQuery("status", "open"); status {
+	case "open":
+		query = query.Where("status IN ?", verification.OpenStatuses)
+	case "all":
+	default:
+		query = query.Where("status = ?", status)
+	}
+	switch c.Query("claimed") {
+	case "me":
+		query = query.Where("claimed_by = ?", adminID)
+	case "none":
+		query = query.Where("claimed_by IS NULL")
+	}
+
+	var submissions []domain.PlayerVerification
+	if err := query.Order("created_at ASC").Limit(200).Find(&submissions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to fetch verifications"}})
+		return
+	}
+
+	response := make([]gin.H, len(submissions))
+	for i, v := range submissions {
+		response[i] = m.verificationResponse(v)
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": response})
+}
+
+// GetVerification returns a submission with its document and history
+func (m *AdminModule) GetVerification(c *gin.Context) {
+	v, ok := m.findVerification(c)
+	if !ok {
+		return
+	}
+
+	var events []domain.VerificationEvent
+	m.db.Where("verification_id = ?", v.ID).Order("created_at ASC").Find(&events)
+
+	response := m.verificationResponse(*v)
+	response["doc_url"] = storage.SignedURL(m.store, v.DocURL)
+	response["events"] = events
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": response})
+}
+
+// SubmitVerificationRequest submits a document on a player's behalf. DocKey is the
+// s3_key of a verification_doc upload.
+type SubmitVerificationRequest struct {
+	DocType string `json:"doc_type" binding:"required"`
+	DocKey  string `json:"doc_key" binding:"required"`
+}
+
+// SubmitPlayerVerification queues an identity document uploaded by staff
+func (m *AdminModule) SubmitPlayerVerification(c *gin.Context) {
+	var req SubmitVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+	if _, ok := verification.DocTypes[req.DocType]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DOC_TYPE", "message": "Unknown document type"}})
+		return
+	}
+	if !strings.HasPrefix(req.DocKey, verification.DocPrefix) {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DOCUMENT", "message": "Document must be a verification_doc upload"}})
+		return
+	}
+
+	player, ok := m.findPlayer(c)
+	if !ok {
+		return
+	}
+
+	if _, err := m.uploads.VerifyByKey(c.Request.Context(), req.DocKey); err != nil {
+		var mismatch *storage.MismatchError
+		if errors.As(err, &mismatch) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"code": "UPLOAD_VERIFICATION_FAILED", "message": mismatch.Reason}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "VERIFICATION_ERROR", "message": "Failed to verify document upload"}})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	v, err := verification.Submit(m.db, player, req.DocType, storage.ObjectURL(m.store, req.DocKey), adminID)
+	if err != nil {
+		verificationError(c, err)
+		return
+	}
+
+	m.logAudit(c, "submit_verification", "player_verification", &v.ID, nil)
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": m.verificationResponse(*v)})
+}
+
+// ClaimVerification assigns a submission to the calling reviewer
+func (m *AdminModule) ClaimVerification(c *gin.Context) {
+	m.transitionVerification(c, "claim_verification", func(v *domain.PlayerVerification, adminID uuid.UUID) error {
+		return verification.Claim(m.db, v, adminID)
+	})
+}
+
+// ReleaseVerification hands a claimed submission back to the queue
+func (m *AdminModule) ReleaseVerification(c *gin.Context) {
+	m.transitionVerification(c, "release_verification", func(v *domain.PlayerVerification, adminID uuid.UUID) error {
+		return verification.Release(m.db, v, adminID)
+	})
+}
+
+// ApproveVerification marks the player verified with the submitted document
+func (m *AdminModule) ApproveVerification(c *gin.Context) {
+	var req struct {
+		Note *string `json:"note"`
+	}
+	c.ShouldBindJSON(&req)
+
+	m.transitionVerification(c, "approve_verification", func(v *domain.PlayerVerification, adminID uuid.UUID) error {
+		return verification.Approve(m.db, v, adminID, req.Note)
+	})
+}
+
+// RejectVerification closes a submission. The reason is shown to the player.
+func (m *AdminModule) RejectVerification(c *gin.Context) {
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": "A reason is required"}})
+		return
+	}
+
+	m.transitionVerification(c, "reject_verification", func(v *domain.PlayerVerification, adminID uuid.UUID) error {
+		return verification.Reject(m.db, v, adminID, req.Reason)
+	})
+}
+
+// RequestVerificationInfo asks the player for a better or different document
+func (m *AdminModule) RequestVerificationInfo(c *gin.Context) {
+	var req struct {
+		Message string `json:"message" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": "Say what information is needed"}})
+		return
+	}
+
+	m.transitionVerification(c, "request_verification_info", func(v *domain.PlayerVerification, adminID uuid.UUID) error {
+		return verification.RequestInfo(m.db, v, adminID, req.Message)
+	})
+}
+
+// GetPlayerVerificationHistory lists every verification event for a player,
+// including status changes made outside the queue
+func (m *AdminModule) GetPlayerVerificationHistory(c *gin.Context) {
+	player, ok := m.findPlayer(c)
+	if !ok {
+		return
+	}
+
+	var submissions []domain.PlayerVerification
+	m.db.Where("player_id = ?", player.ID).Order("created_at DESC").Find(&submissions)
+	var events []domain.VerificationEvent
+	m.db.Where("player_id = ?", player.ID).Order("created_at ASC").Find(&events)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"verification_status": player.VerificationStatus,
+			"verified_at":         player.VerifiedAt,
+			"submissions":         submissions,
+			"events":              events,
+		},
+	})
+}
+
+// transitionVerification loads the submission in the URL, applies a queue action
+// as the calling reviewer and writes the response
+func (m *AdminModule) transitionVerification(c *gin.Context, auditAction string, apply func(*domain.PlayerVerification, uuid.UUID) error) {
+	v, ok := m.findVerification(c)
+	if !ok {
+		return
+	}
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	if err := apply(v, adminID); err != nil {
+		verificationError(c, err)
+		return
+	}
+
+	details := fmt.Sprintf(`{"player_id":"%s"}`, v.PlayerID)
+	m.logAudit(c, auditAction, "player_verification", &v.ID, &details)
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": m.verificationResponse(*v)})
+}
+
+// findVerification loads the submission in the URL, writing the error response when missing
+func (m *AdminModule) findVerification(c *gin.Context) (*domain.PlayerVerification, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid verification ID"}})
+		return nil, false
+	}
+	var v domain.PlayerVerification
+	if err := m.db.Preload("Player").First(&v, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Verification not found"}})
+		return nil, false
+	}
+	return &v, true
+}
+
+func (m *AdminModule) verificationResponse(v domain.PlayerVerification) gin.H {
+	item := gin.H{
+		"id":           v.ID,
+		"player_id":    v.PlayerID,
+		"doc_type":     v.DocType,
+		"status":       v.Status,
+		"submitted_by": v.SubmittedBy,
+		"claimed_by":   v.ClaimedBy,
+		"claimed_at":   v.ClaimedAt,
+		"reason":       v.Reason,
+		"decided_by":   v.DecidedBy,
+		"decided_at":   v.DecidedAt,
+		"created_at":   v.CreatedAt,
+		"updated_at":   v.UpdatedAt,
+	}
+	if p := v.Player; p != nil {
+		item["player"] = gin.H{
+			"first_name":    p.FirstName,
+			"last_name":     p.LastName,
+			"date_of_birth": p.DateOfBirth.Format("2006-01-02"),
+			"country":       p.Country,
+		}
+	}
+	return item
+}
+
+// verificationError maps verification workflow errors to responses
+func verificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, verification.ErrAlreadyVerified):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "ALREADY_VERIFIED", "message": err.Error()}})
+	case errors.Is(err, verification.ErrUnderReview), errors.Is(err, verification.ErrClaimed),
+		errors.Is(err, verification.ErrAwaitingInfo), errors.Is(err, verification.ErrClosed):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "INVALID_STATE", "message": err.Error()}})
+	case errors.Is(err, verification.ErrNotClaimed):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "NOT_CLAIMED", "message": err.Error()}})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update verification"}})
+	}
+}
+
+// --- Player Management ---
+
+// CreatePlayerRequest represents the request to create a player
+type CreatePlayerRequest struct {
+	FirstName           string  `json:"first_name" binding:"required"`
+	LastName            string  `json:"last_name" binding:"required"`
+	DateOfBirth         string  `json:"date_of_birth" binding:"required"`
+	Position            string  `json:"position" binding:"required"`
+	PreferredFoot       *string `json:"preferred_foot,omitempty"`
+	HeightCm            *int    `json:"height_cm,omitempty"`
+	WeightKg            *int    `json:"weight_kg,omitempty"`
+	Country             string  `json:"country" binding:"required"`
+	State               *string `json:"state,omitempty"`
+	City                *string `json:"city,omitempty"`
+	AcademyID           *string `json:"academy_id,omitempty"`
+	SchoolName          *string `json:"school_name,omitempty"`
+	SchoolID            *string `json:"school_id,omitempty"` // partner school, asked to attest enrollment and age
+	TournamentID        *string `json:"tournament_id,omitempty"`
+	VerificationStatus  string  `json:"verification_status,omitempty"`
+	VerificationDocType *string `json:"verification_doc_type,omitempty"`
+}
+
+// CreatePlayerResponse includes generated credentials
+type CreatePlayerResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	FirstName   string       `json:"first_name"`
+	LastName    string       `json:"last_name"`
+	Credentials *Credentials `json:"credentials,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// Credentials for player login
+type Credentials struct {
+	Email        string `json:"email"`
+	TempPassword string `json:"temp_password"`
+}
+
+// CreatePlayer creates a new player profile (admin only)
+func (m *AdminModule) CreatePlayer(c *gin.Context) {
+	var req CreatePlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	// Parse date of birth
+	dob, err := time.Parse("2006-01-02", req.DateOfBirth)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DATE", "message": "Invalid date_of_birth format, use YYYY-MM-DD"}})
+		return
+	}
+
+	// Get admin user ID from context
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": gin.H{"code": "UNAUTHORIZED", "message": "User not found"}})
+		return
+	}
+
+	// Parse tournament ID if provided
+	var tournamentID *uuid.UUID
+	var tournamentYear *int
+	if req.TournamentID != nil && *req.TournamentID != "" {
+		tid, err := uuid.Parse(*req.TournamentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid tournament_id"}})
+			return
+		}
+		tournamentID = &tid
+
+		// Get tournament year
+		var tournament domain.Tournament
+		if err := m.db.First(&tournament, "id = ?", tid).Error; err == nil {
+			tournamentYear = &tournament.Year
+		}
+	}
+
+	// Parse academy ID if provided
+	var academyID *uuid.UUID
+	if req.AcademyID != nil && *req.AcademyID != "" {
+		aid, err := uuid.Parse(*req.AcademyID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid academy_id"}})
+			return
+		}
+		// Verify academy exists
+		var academy domain.Academy
+		if err := m.db.First(&academy, "id = ?", aid).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Academy not found"}})
+			return
+		}
+		academyID = &aid
+	}
+
+	// A partner school replaces the free-text school name
+	var school *domain.School
+	if req.SchoolID != nil && *req.SchoolID != "" {
+		var ok bool
+		if school, ok = m.findSchoolByID(c, *req.SchoolID); !ok {
+			return
+		}
+		req.SchoolName = &school.Name
+	}
+
+	// Set verification status
+	verificationStatus := "pending"
+	if req.VerificationStatus != "" {
+		verificationStatus = req.VerificationStatus
 	}
 
 	// Create player
@@ -570,21 +1387,31 @@ func (m *AdminModule) CreatePlayer(c *gin.Context) {
 		UpdatedAt:           time.Now(),
 	}
 
+	if school != nil {
+		player.SchoolID = &school.ID
+	}
+
 	// If verified, set verification metadata
 	if verificationStatus == "verified" {
 		now := time.Now()
 		aid := adminID.(uuid.UUID)
+		source := verification.SourceAdmin
 		player.VerifiedAt = &now
 		player.VerifiedBy = &aid
+		player.VerificationSource = &source
 	}
 
 	if err := m.db.Create(&player).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to create player"}})
 		return
 	}
+	if tournamentID != nil {
+		registrations.Register(m.db, *tournamentID, player.ID, &player.CreatedBy)
+	}
+	agegroup.Recheck(m.db, player.ID)
 
 	// Generate credentials for the player
-	credentials, err := m.generatePlayerCredentials(&player)
+	credentials, err := m.generatePlayerCredentials(m.db, &player)
 	if err != nil {
 		// Player created but credentials failed - log but don't fail request
 		c.JSON(http.StatusCreated, gin.H{
@@ -599,6 +1426,13 @@ func (m *AdminModule) CreatePlayer(c *gin.Context) {
 		return
 	}
 
+	if player.VerificationStatus == "verified" {
+		verification.RecordManual(m.db, []uuid.UUID{player.ID}, *player.VerifiedBy, true)
+	}
+	if school != nil {
+		verification.RequestAttestation(m.db, player.ID, school.ID, player.CreatedBy)
+	}
+
 	// Log audit
 	m.logAudit(c, "create_player", "player", &player.ID, nil)
 
@@ -615,7 +1449,7 @@ func (m *AdminModule) CreatePlayer(c *gin.Context) {
 }
 
 // generatePlayerCredentials creates user account and credentials for a player
-func (m *AdminModule) generatePlayerCredentials(player *domain.Player) (*Credentials, error) {
+func (m *AdminModule) generatePlayerCredentials(db *gorm.DB, player *domain.Player) (*Credentials, error) {
 	// Generate email: firstname.lastname.year@unicornsport.africa
 	year := player.DateOfBirth.Year()
 	email := fmt.Sprintf("%s.%s.%d@unicornsport.africa",
@@ -625,7 +1459,7 @@ func (m *AdminModule) generatePlayerCredentials(player *domain.Player) (*Credent
 
 	// Check if email already exists, append number if needed
 	var count int64
-	m.db.Model(&domain.User{}).Where("email LIKE ?", strings.TrimSuffix(email, "@unicornsport.africa")+"%").Count(&count)
+	db.Model(&domain.User{}).Where("email LIKE ?", strings.TrimSuffix(email, "@unicornsport.africa")+"%").Count(&count)
 	if count > 0 {
 		email = fmt.Sprintf("%s.%s.%d.%d@unicornsport.africa",
 			strings.ToLower(player.FirstName),
@@ -642,22 +1476,23 @@ func (m *AdminModule) generatePlayerCredentials(player *domain.Player) (*Credent
 		return nil, err
 	}
 
-	// Create user account
+	// Create user account; the temporary password must be replaced on first login
 	user := domain.User{
-		Email:        email,
-		PasswordHash: string(hashedPassword),
-		Role:         "player",
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		Email:              email,
+		PasswordHash:       string(hashedPassword),
+		Role:               "player",
+		MustChangePassword: true,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
 	}
 
-	if err := m.db.Create(&user).Error; err != nil {
+	if err := db.Create(&user).Error; err != nil {
 		return nil, err
 	}
 
 	// Link player to user
 	player.UserID = &user.ID
-	if err := m.db.Save(player).Error; err != nil {
+	if err := db.Save(player).Error; err != nil {
 		return nil, err
 	}
 
@@ -680,41 +1515,12 @@ type UpdatePlayerRequest struct {
 	City               *string `json:"city,omitempty"`
 	AcademyID          *string `json:"academy_id,omitempty"`
 	SchoolName         *string `json:"school_name,omitempty"`
+	SchoolID           *string `json:"school_id,omitempty"` // "" unlinks the partner school
 	VerificationStatus *string `json:"verification_status,omitempty"`
 	ProfilePhotoURL    *string `json:"profile_photo_url,omitempty"`
 	ThumbnailURL       *string `json:"thumbnail_url,omitempty"`
 }
 
-// getPresignedURL converts an s3:// URL to a presigned URL for direct access
-func (m *AdminModule) getPresignedURL(s3Url string) string {
-	if s3Url == "" || m.s3Client == nil {
-		return s3Url
-	}
-
-	// Handle s3://bucket/key format
-	if strings.HasPrefix(s3Url, "s3://") {
-		parts := strings.SplitN(strings.TrimPrefix(s3Url, "s3://"), "/", 2)
-		if len(parts) != 2 {
-			return s3Url
-		}
-		bucket := parts[0]
-		key := parts[1]
-
-		presigner := s3.NewPresignClient(m.s3Client)
-		presignedReq, err := presigner.PresignGetObject(context.Background(), &s3.GetObjectInput{
-			Bucket: aws.String(bucket),
-			Key:    aws.String(key),
-		}, s3.WithPresignExpires(time.Hour))
-		if err != nil {
-			return s3Url
-		}
-		return presignedReq.URL
-	}
-
-	// Already a direct URL or CloudFront URL
-	return s3Url
-}
-
 // GetPlayer returns a single player by ID
 func (m *AdminModule) GetPlayer(c *gin.Context) {
 	playerID := c.Param("id")
@@ -725,7 +1531,7 @@ func (m *AdminModule) GetPlayer(c *gin.Context) {
 	}
 
 	var player domain.Player
-	if err := m.db.Preload("Tournament").Preload("Academy").Where("deleted_at IS NULL").First(&player, "id = ?", pid).Error; err != nil {
+	if err := m.db.Preload("Tournament").Preload("Academy").Preload("School").Where("deleted_at IS NULL").First(&player, "id = ?", pid).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Player not found"}})
 		return
 	}
@@ -733,7 +1539,7 @@ func (m *AdminModule) GetPlayer(c *gin.Context) {
 	// Convert profile photo URL to presigned URL
 	var profilePhotoURL *string
 	if player.ProfilePhotoURL != nil && *player.ProfilePhotoURL != "" {
-		url := m.getPresignedURL(*player.ProfilePhotoURL)
+		url := storage.SignedURL(m.store, *player.ProfilePhotoURL)
 		profilePhotoURL = &url
 	}
 
@@ -751,7 +1557,10 @@ func (m *AdminModule) GetPlayer(c *gin.Context) {
 		"state":               player.State,
 		"city":                player.City,
 		"school_name":         player.SchoolName,
+		"school_id":           player.SchoolID,
+		"school":              player.School,
 		"verification_status": player.VerificationStatus,
+		"verification_source": player.VerificationSource,
 		"profile_photo_url":   profilePhotoURL,
 		"thumbnail_url":       player.ThumbnailURL,
 		"academy_id":          player.AcademyID,
@@ -762,7 +1571,30 @@ func (m *AdminModule) GetPlayer(c *gin.Context) {
 		"updated_at":          player.UpdatedAt,
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	var cutoff time.Time
+	if player.Tournament != nil {
+		cutoff = agegroup.TournamentCutoff(player.Tournament)
+	} else {
+		cutoff = agegroup.Cutoff(time.Now().Year(), nil)
+	}
+	playerResponse["age"] = player.GetAge()
+	playerResponse["age_group"] = agegroup.Of(player.DateOfBirth, cutoff)
+
+	var flags []domain.PlayerFlag
+	m.db.Where("status = ? AND (player_id = ? OR related_player_id = ?)", agegroup.FlagOpen, player.ID, player.ID).
+		Order("created_at DESC").Find(&flags)
+	playerResponse["flags"] = flags
+
+	var entries []domain.TournamentRegistration
+	m.db.Preload("Tournament").Where("player_id = ?", player.ID).Order("created_at DESC").Find(&entries)
+	playerResponse["registrations"] = entries
+
+	// Surface uploads that failed the malware scan so admins can follow up
+	infected, _ := m.uploads.InfectedUploads(c.Request.Context(), "player", player.ID)
+	playerResponse["has_infected_upload"] = len(infected) > 0
+	playerResponse["infected_uploads"] = infected
+
+	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
 			"player": playerResponse,
@@ -842,6 +1674,19 @@ func (m *AdminModule) UpdatePlayer(c *gin.Context) {
 	if req.SchoolName != nil {
 		updates["school_name"] = *req.SchoolName
 	}
+	var newSchool *domain.School
+	if req.SchoolID != nil {
+		if *req.SchoolID == "" {
+			updates["school_id"] = nil
+		} else {
+			var ok bool
+			if newSchool, ok = m.findSchoolByID(c, *req.SchoolID); !ok {
+				return
+			}
+			updates["school_id"] = newSchool.ID
+			updates["school_name"] = newSchool.Name
+		}
+	}
 	if req.ProfilePhotoURL != nil {
 		updates["profile_photo_url"] = *req.ProfilePhotoURL
 	}
@@ -855,12 +1700,15 @@ func (m *AdminModule) UpdatePlayer(c *gin.Context) {
 			now := time.Now()
 			updates["verified_at"] = now
 			updates["verified_by"] = adminID
+			updates["verification_source"] = verification.SourceAdmin
 		}
 	}
 	updates["updated_at"] = time.Now()
 
 	// Track if position is being changed for syncing to match rosters
 	oldPosition := player.Position
+	oldStatus := player.VerificationStatus
+	oldSchoolID := player.SchoolID
 	newPosition := ""
 	if req.Position != nil {
 		newPosition = *req.Position
@@ -871,6 +1719,20 @@ func (m *AdminModule) UpdatePlayer(c *gin.Context) {
 		return
 	}
 
+	// Linking a different school asks it to attest; unlinking withdraws the request
+	if req.SchoolID != nil {
+		switch {
+		case newSchool != nil && (oldSchoolID == nil || *oldSchoolID != newSchool.ID):
+			verification.RequestAttestation(m.db, pid, newSchool.ID, c.MustGet("user_id").(uuid.UUID))
+		case newSchool == nil && oldSchoolID != nil:
+			verification.CancelAttestations(m.db, pid)
+		}
+	}
+
+	if req.VerificationStatus != nil && (*req.VerificationStatus == "verified") != (oldStatus == "verified") {
+		verification.RecordManual(m.db, []uuid.UUID{pid}, c.MustGet("user_id").(uuid.UUID), *req.VerificationStatus == "verified")
+	}
+
 	// If position was changed, sync to all match rosters
 	// This ensures academy corrections propagate everywhere
 	if newPosition != "" && newPosition != oldPosition {
@@ -879,13 +1741,15 @@ func (m *AdminModule) UpdatePlayer(c *gin.Context) {
 			Update("position_played", newPosition)
 	}
 
+	agegroup.Recheck(m.db, pid)
+
 	// Reload the player to get updated values
 	m.db.First(&player, "id = ?", pid)
 
 	// Convert profile photo URL to presigned URL
 	var profilePhotoURL *string
 	if player.ProfilePhotoURL != nil && *player.ProfilePhotoURL != "" {
-		url := m.getPresignedURL(*player.ProfilePhotoURL)
+		url := storage.SignedURL(m.store, *player.ProfilePhotoURL)
 		profilePhotoURL = &url
 	}
 
@@ -926,6 +1790,8 @@ func (m *AdminModule) DeletePlayer(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "DELETE_FAILED", "message": "Failed to delete player"}})
 		return
 	}
+	agegroup.Recheck(m.db, pid)
+	standings.RefreshPlayers(m.db, pid)
 
 	m.logAudit(c, "delete_player", "player", &pid, nil)
 
@@ -967,7 +1833,7 @@ func (m *AdminModule) ListPlayers(c *gin.Context) {
 		}
 	}
 	if tournamentID := c.Query("tournament_id"); tournamentID != "" {
-		query = query.Where("tournament_id = ?", tournamentID)
+		query = query.Scopes(registrations.Entered(tournamentID))
 	}
 	if academyID := c.Query("academy_id"); academyID != "" {
 		query = query.Where("academy_id = ?", academyID)
@@ -982,12 +1848,19 @@ func (m *AdminModule) ListPlayers(c *gin.Context) {
 	m.db.Model(&domain.Player{}).Where("deleted_at IS NULL AND verification_status = ?", "verified").Count(&verifiedCount)
 	pendingCount = totalPlayers - verifiedCount
 
+	// Flag players with uploads that failed the malware scan
+	playerIDs := make([]uuid.UUID, len(players))
+	for i, p := range players {
+		playerIDs[i] = p.ID
+	}
+	infected, _ := m.uploads.InfectedEntityIDs(c.Request.Context(), "player", playerIDs)
+
 	// Convert profile photo URLs to presigned URLs
 	playerResponses := make([]gin.H, len(players))
 	for i, p := range players {
 		var profilePhotoURL *string
 		if p.ProfilePhotoURL != nil && *p.ProfilePhotoURL != "" {
-			url := m.getPresignedURL(*p.ProfilePhotoURL)
+			url := storage.SignedURL(m.store, *p.ProfilePhotoURL)
 			profilePhotoURL = &url
 		}
 		playerResponses[i] = gin.H{
@@ -1012,6 +1885,7 @@ func (m *AdminModule) ListPlayers(c *gin.Context) {
 			"academy":             p.Academy,
 			"created_at":          p.CreatedAt,
 			"updated_at":          p.UpdatedAt,
+			"has_infected_upload": infected[p.ID],
 		}
 	}
 
@@ -1035,6 +1909,197 @@ func (m *AdminModule) ListPlayers(c *gin.Context) {
 	})
 }
 
+// ImportedPlayer is a player created by an import, with their login if one was
+// generated
+type ImportedPlayer struct {
+	Row         int          `json:"row"`
+	ID          uuid.UUID    `json:"id"`
+	FirstName   string       `json:"first_name"`
+	LastName    string       `json:"last_name"`
+	Credentials *Credentials `json:"credentials,omitempty"`
+}
+
+// ImportPlayers creates players from a CSV or XLSX file (multipart "file"). It is
+// a dry run unless dry_run=false: the rows are validated and a row-level report
+// returned, but nothing is created. Form fields:
+//   - mapping: JSON object of field to column header, for files whose headers
+//     aren't the field names (see playerimport.Fields)
+//   - date_format: YYYY-MM-DD (default), DD/MM/YYYY or MM/DD/YYYY
+//   - country, academy_id, tournament_id: used for rows that leave them blank
+//   - credentials=true: generate a login for each player
+//
+// A real import creates the players, their academy memberships and tournament
+// registrations in one transaction, and only if every row is valid.
+func (m *AdminModule) ImportPlayers(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "FILE_REQUIRED", "message": "CSV or XLSX file is required"}})
+		return
+	}
+	if file.Size > 5<<20 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "FILE_TOO_LARGE", "message": "File too large (max 5MB)"}})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_FILE", "message": "Failed to read file"}})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_FILE", "message": "Failed to read file"}})
+		return
+	}
+
+	opts := playerimport.Options{
+		DateFormat: c.DefaultPostForm("date_format", playerimport.DefaultDateFormat),
+		Country:    strings.TrimSpace(c.PostForm("country")),
+	}
+	if _, ok := playerimport.DateFormats[opts.DateFormat]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DATE_FORMAT", "message": "date_format must be YYYY-MM-DD, DD/MM/YYYY or MM/DD/YYYY"}})
+		return
+	}
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_MAPPING", "message": "mapping must be a JSON object of field to column header"}})
+			return
+		}
+	}
+	if v := c.PostForm("academy_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid academy_id"}})
+			return
+		}
+		var academy domain.Academy
+		if err := m.db.Select("id").First(&academy, "id = ?", id).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Academy not found"}})
+			return
+		}
+		opts.AcademyID = &id
+	}
+	if v := c.PostForm("tournament_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid tournament_id"}})
+			return
+		}
+		var tournament domain.Tournament
+		if err := m.db.Select("id").First(&tournament, "id = ?", id).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Tournament not found"}})
+			return
+		}
+		opts.TournamentID = &id
+	}
+	dryRun := c.DefaultPostForm("dry_run", "true") != "false"
+	withCredentials := c.PostForm("credentials") == "true"
+
+	table, err := playerimport.ReadTable(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_FILE", "message": err.Error()}})
+		return
+	}
+	if len(table.Records) > playerimport.MaxRows {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "TOO_MANY_ROWS", "message": fmt.Sprintf("File has too many rows (max %d)", playerimport.MaxRows)}})
+		return
+	}
+	cols, err := playerimport.MapColumns(table.Header, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_MAPPING", "message": err.Error()}})
+		return
+	}
+	rows, rowErrors, err := playerimport.Validate(m.db, table, cols, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_FAILED", "message": "Failed to validate the file"}})
+		return
+	}
+
+	columns := gin.H{}
+	for field, i := range cols {
+		columns[field] = table.Header[i]
+	}
+	report := gin.H{
+		"dry_run":     dryRun,
+		"total_rows":  len(table.Records),
+		"valid_rows":  len(rows),
+		"error_count": len(rowErrors),
+		"errors":      rowErrors,
+		"columns":     columns,
+	}
+
+	if dryRun {
+		preview := make([]gin.H, len(rows))
+		for i, row := range rows {
+			entry := gin.H{
+				"row":           row.Line,
+				"first_name":    row.Player.FirstName,
+				"last_name":     row.Player.LastName,
+				"date_of_birth": row.Player.DateOfBirth.Format("2006-01-02"),
+				"position":      row.Player.Position,
+				"country":       row.Player.Country,
+			}
+			if row.Academy != nil {
+				entry["academy"] = gin.H{"id": row.Academy.ID, "name": row.Academy.Name}
+			}
+			if row.Tournament != nil {
+				entry["tournament"] = gin.H{"id": row.Tournament.ID, "name": row.Tournament.Name, "year": row.Tournament.Year}
+			}
+			preview[i] = entry
+		}
+		report["rows"] = preview
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": report})
+		return
+	}
+
+	if len(rowErrors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   gin.H{"code": "INVALID_ROWS", "message": "Fix the rows below and import the file again. No players were created."},
+			"data":    report,
+		})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "NO_ROWS", "message": "File has no players to import"}})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	imported := make([]ImportedPlayer, len(rows))
+	var players []domain.Player
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if players, err = playerimport.Create(tx, rows, adminID); err != nil {
+			return err
+		}
+		for i := range players {
+			imported[i] = ImportedPlayer{Row: rows[i].Line, ID: players[i].ID, FirstName: players[i].FirstName, LastName: players[i].LastName}
+			if withCredentials {
+				if imported[i].Credentials, err = m.generatePlayerCredentials(tx, &players[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "IMPORT_FAILED", "message": "Failed to import players. No players were created."}})
+		return
+	}
+	for i := range players {
+		agegroup.Recheck(m.db, players[i].ID)
+	}
+
+	encoded, _ := json.Marshal(gin.H{"file": file.Filename, "count": len(players), "credentials": withCredentials})
+	details := string(encoded)
+	m.logAudit(c, "import_players", "players", nil, &details)
+
+	report["created_count"] = len(players)
+	report["players"] = imported
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": report})
+}
+
 // --- Tournament Management ---
 
 // CreateTournamentRequest represents the request to create a tournament
@@ -1049,6 +2114,9 @@ type CreateTournamentRequest struct {
 	IsPublic      bool    `json:"is_public"`
 	Featured      bool    `json:"featured"`
 	CoverImageURL *string `json:"cover_image_url,omitempty"`
+	AgeGroup      *string `json:"age_group,omitempty"`       // U13, U15, U17 or U20
+	AgeCutoffDate *string `json:"age_cutoff_date,omitempty"` // defaults to 1 January of the year
+	TieBreakers   *string `json:"tie_breakers,omitempty"`    // comma-separated, e.g. "head_to_head,goal_difference"
 }
 
 // CreateTournament creates a new tournament
@@ -1086,6 +2154,31 @@ func (m *AdminModule) CreateTournament(c *gin.Context) {
 			tournament.EndDate = &t
 		}
 	}
+	if req.AgeGroup != nil && *req.AgeGroup != "" {
+		group, ok := agegroup.Parse(*req.AgeGroup)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_AGE_GROUP", "message": "age_group must be one of U13, U15, U17, U20"}})
+			return
+		}
+		tournament.AgeGroup = &group.Name
+	}
+	if req.AgeCutoffDate != nil && *req.AgeCutoffDate != "" {
+		t, err := time.Parse("2006-01-02", *req.AgeCutoffDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_CUTOFF", "message": "age_cutoff_date must be YYYY-MM-DD"}})
+			return
+		}
+		tournament.AgeCutoffDate = &t
+	}
+	if req.TieBreakers != nil && *req.TieBreakers != "" {
+		list, err := standings.ParseTieBreakers(*req.TieBreakers)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_TIE_BREAKERS", "message": err.Error()}})
+			return
+		}
+		joined := strings.Join(list, ",")
+		tournament.TieBreakers = &joined
+	}
 
 	if err := m.db.Create(&tournament).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to create tournament"}})
@@ -1139,6 +2232,8 @@ func (m *AdminModule) ListTournaments(c *gin.Context) {
 		IsPublic       bool      `json:"is_public"`
 		Featured       bool      `json:"featured"`
 		CoverImageURL  *string   `json:"cover_image_url,omitempty"`
+		AgeGroup       *string   `json:"age_group,omitempty"`
+		AgeCutoffDate  string    `json:"age_cutoff_date"`
 		MatchCount     int64     `json:"match_count"`
 		VideoCount     int64     `json:"video_count"`
 		HighlightCount int64     `json:"highlight_count"`
@@ -1150,7 +2245,7 @@ func (m *AdminModule) ListTournaments(c *gin.Context) {
 	for i, t := range tournaments {
 		// Get counts
 		var playerCount, matchCount, videoCount, highlightCount int64
-		m.db.Model(&domain.Player{}).Where("tournament_id = ? AND deleted_at IS NULL", t.ID).Count(&playerCount)
+		m.db.Model(&domain.Player{}).Where("deleted_at IS NULL").Scopes(registrations.Entered(t.ID)).Count(&playerCount)
 		m.db.Model(&domain.Match{}).Where("tournament_id = ?", t.ID).Count(&matchCount)
 		m.db.Model(&domain.Video{}).Where("tournament_id = ? AND video_type = 'full_match'", t.ID).Count(&videoCount)
 		m.db.Model(&domain.Video{}).Where("tournament_id = ? AND video_type = 'highlight'", t.ID).Count(&highlightCount)
@@ -1158,7 +2253,7 @@ func (m *AdminModule) ListTournaments(c *gin.Context) {
 		// Convert cover image URL
 		var coverImageURL *string
 		if t.CoverImageURL != nil && *t.CoverImageURL != "" {
-			url := m.getPresignedURL(*t.CoverImageURL)
+			url := storage.SignedURL(m.store, *t.CoverImageURL)
 			coverImageURL = &url
 		}
 
@@ -1185,6 +2280,8 @@ func (m *AdminModule) ListTournaments(c *gin.Context) {
 			IsPublic:       t.IsPublic,
 			Featured:       t.Featured,
 			CoverImageURL:  coverImageURL,
+			AgeGroup:       t.AgeGroup,
+			AgeCutoffDate:  agegroup.TournamentCutoff(&t).Format("2006-01-02"),
 			MatchCount:     matchCount,
 			VideoCount:     videoCount,
 			HighlightCount: highlightCount,
@@ -1205,134 +2302,1199 @@ func (m *AdminModule) ListTournaments(c *gin.Context) {
 			},
 		},
 	})
-}
-
-// UpdateTournament updates a tournament
-func (m *AdminModule) UpdateTournament(c *gin.Context) {
-	tournamentID := c.Param("id")
-	tid, err := uuid.Parse(tournamentID)
+}
+
+// UpdateTournament updates a tournament
+func (m *AdminModule) UpdateTournament(c *gin.Context) {
+	tournamentID := c.Param("id")
+	tid, err := uuid.Parse(tournamentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid tournament ID"}})
+		return
+	}
+
+	var tournament domain.Tournament
+	if err := m.db.First(&tournament, "id = ?", tid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Tournament not found"}})
+		return
+	}
+
+	var req map[string]interface{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+	// An empty age group or cut-off clears it
+	if v, ok := req["age_group"]; ok {
+		name, _ := v.(string)
+		if name == "" {
+			req["age_group"] = nil
+		} else if group, ok := agegroup.Parse(name); ok {
+			req["age_group"] = group.Name
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_AGE_GROUP", "message": "age_group must be one of U13, U15, U17, U20"}})
+			return
+		}
+	}
+	if v, ok := req["age_cutoff_date"]; ok {
+		date, _ := v.(string)
+		if date == "" {
+			req["age_cutoff_date"] = nil
+		} else if _, err := time.Parse("2006-01-02", date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_CUTOFF", "message": "age_cutoff_date must be YYYY-MM-DD"}})
+			return
+		}
+	}
+	// An empty list goes back to the default tie-breakers
+	if v, ok := req["tie_breakers"]; ok {
+		value, _ := v.(string)
+		if list, err := standings.ParseTieBreakers(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_TIE_BREAKERS", "message": err.Error()}})
+			return
+		} else if strings.TrimSpace(value) == "" {
+			req["tie_breakers"] = nil
+		} else {
+			req["tie_breakers"] = strings.Join(list, ",")
+		}
+	}
+
+	if err := m.db.Model(&tournament).Updates(req).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update tournament"}})
+		return
+	}
+	if _, ok := req["tie_breakers"]; ok {
+		standings.Refresh(m.db, &tid)
+	}
+
+	m.logAudit(c, "update_tournament", "tournament", &tid, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"id":         tournament.ID,
+			"name":       tournament.Name,
+			"updated_at": time.Now(),
+		},
+	})
+}
+
+// GetTournamentRoster lists the tournament's registered players with their squad
+// and their age on its cut-off date. In an age-group tournament, players too old
+// for the group are marked ineligible; eligible=false lists only those.
+func (m *AdminModule) GetTournamentRoster(c *gin.Context) {
+	tid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid tournament ID"}})
+		return
+	}
+
+	var tournament domain.Tournament
+	if err := m.db.First(&tournament, "id = ?", tid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Tournament not found"}})
+		return
+	}
+
+	cutoff := agegroup.TournamentCutoff(&tournament)
+	var group *agegroup.Group
+	if tournament.AgeGroup != nil {
+		if g, ok := agegroup.Parse(*tournament.AgeGroup); ok {
+			group = &g
+		}
+	}
+
+	query := m.db.Model(&domain.Player{}).
+		Joins("JOIN tournament_registrations tr ON tr.player_id = players.id AND tr.tournament_id = ?", tid).
+		Where("players.deleted_at IS NULL")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("tr.status = ?", status)
+	}
+	if name := c.Query("age_group"); name != "" {
+		g, ok := agegroup.Parse(name)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_AGE_GROUP", "message": "age_group must be one of U13, U15, U17, U20"}})
+			return
+		}
+		query = query.Where("date_of_birth > ?", g.BornAfter(cutoff))
+	}
+	if c.Query("eligible") == "false" && group != nil {
+		query = query.Where("date_of_birth <= ?", group.BornAfter(cutoff))
+	}
+
+	var players []domain.Player
+	if err := query.Order("players.last_name ASC, players.first_name ASC").Find(&players).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to fetch roster"}})
+		return
+	}
+
+	var entries []domain.TournamentRegistration
+	m.db.Where("tournament_id = ?", tid).Find(&entries)
+	entryByPlayer := make(map[uuid.UUID]domain.TournamentRegistration, len(entries))
+	for _, e := range entries {
+		entryByPlayer[e.PlayerID] = e
+	}
+
+	// Open flags per player, counting duplicate flags on both players of the pair
+	ids := make([]uuid.UUID, len(players))
+	for i := range players {
+		ids[i] = players[i].ID
+	}
+	openFlags := map[uuid.UUID]int{}
+	if len(ids) > 0 {
+		var flags []domain.PlayerFlag
+		m.db.Select("player_id, related_player_id").
+			Where("status = ? AND (player_id IN ? OR related_player_id IN ?)", agegroup.FlagOpen, ids, ids).Find(&flags)
+		for _, f := range flags {
+			openFlags[f.PlayerID]++
+			if f.RelatedPlayerID != nil {
+				openFlags[*f.RelatedPlayerID]++
+			}
+		}
+	}
+
+	roster := make([]gin.H, len(players))
+	ineligible := 0
+	for i, p := range players {
+		eligible := group == nil || group.Eligible(p.DateOfBirth, cutoff)
+		if !eligible {
+			ineligible++
+		}
+		entry := entryByPlayer[p.ID]
+		roster[i] = gin.H{
+			"id":                  p.ID,
+			"registration_id":     entry.ID,
+			"squad":               entry.Squad,
+			"shirt_number":        entry.ShirtNumber,
+			"status":              entry.Status,
+			"first_name":          p.FirstName,
+			"last_name":           p.LastName,
+			"date_of_birth":       p.DateOfBirth.Format("2006-01-02"),
+			"position":            p.Position,
+			"verification_status": p.VerificationStatus,
+			"age_on_cutoff":       domain.AgeAt(p.DateOfBirth, cutoff),
+			"age_group":           agegroup.Of(p.DateOfBirth, cutoff),
+			"eligible":            eligible,
+			"open_flags":          openFlags[p.ID],
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"tournament": gin.H{
+				"id":              tournament.ID,
+				"name":            tournament.Name,
+				"year":            tournament.Year,
+				"age_group":       tournament.AgeGroup,
+				"age_cutoff_date": cutoff.Format("2006-01-02"),
+			},
+			"players":          roster,
+			"ineligible_count": ineligible,
+		},
+	})
+}
+
+// TournamentRegistrationRequest enters a player in a tournament or edits their entry
+type TournamentRegistrationRequest struct {
+	PlayerID    string  `json:"player_id"` // required when registering
+	Squad       *string `json:"squad,omitempty"`
+	ShirtNumber *int    `json:"shirt_number,omitempty" binding:"omitempty,min=0,max=999"`
+	Status      *string `json:"status,omitempty"` // registered, participated, withdrawn
+}
+
+func validRegistrationStatus(status *string) bool {
+	if status == nil {
+		return true
+	}
+	for _, s := range registrations.Statuses {
+		if *status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterTournamentPlayer enters a player in the tournament
+func (m *AdminModule) RegisterTournamentPlayer(c *gin.Context) {
+	tid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid tournament ID"}})
+		return
+	}
+	var req TournamentRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+	pid, err := uuid.Parse(req.PlayerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid player_id"}})
+		return
+	}
+	if !validRegistrationStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_STATUS", "message": "status must be registered, participated or withdrawn"}})
+		return
+	}
+
+	var tournament domain.Tournament
+	if err := m.db.Select("id").First(&tournament, "id = ?", tid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Tournament not found"}})
+		return
+	}
+	var player domain.Player
+	if err := m.db.Select("id").Where("deleted_at IS NULL").First(&player, "id = ?", pid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Player not found"}})
+		return
+	}
+	var existing int64
+	m.db.Model(&domain.TournamentRegistration{}).Where("tournament_id = ? AND player_id = ?", tid, pid).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "ALREADY_REGISTERED", "message": "Player is already registered for this tournament"}})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	now := time.Now()
+	entry := domain.TournamentRegistration{
+		TournamentID: tid,
+		PlayerID:     pid,
+		Squad:        req.Squad,
+		ShirtNumber:  req.ShirtNumber,
+		Status:       registrations.StatusRegistered,
+		CreatedBy:    &adminID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if req.Status != nil {
+		entry.Status = *req.Status
+	}
+	if err := m.db.Create(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to register player"}})
+		return
+	}
+
+	m.logAudit(c, "register_tournament_player", "tournament_registration", &entry.ID, nil)
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": entry})
+}
+
+// UpdateTournamentRegistration edits a player's squad, shirt number or status. An
+// empty squad clears it.
+func (m *AdminModule) UpdateTournamentRegistration(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid registration ID"}})
+		return
+	}
+	var req TournamentRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+	if !validRegistrationStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_STATUS", "message": "status must be registered, participated or withdrawn"}})
+		return
+	}
+
+	var entry domain.TournamentRegistration
+	if err := m.db.First(&entry, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Registration not found"}})
+		return
+	}
+
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	if req.Squad != nil {
+		if *req.Squad == "" {
+			updates["squad"] = nil
+		} else {
+			updates["squad"] = *req.Squad
+		}
+	}
+	if req.ShirtNumber != nil {
+		updates["shirt_number"] = *req.ShirtNumber
+	}
+	if req.Status != nil {
+		updates["status"] = *req.Status
+	}
+	if err := m.db.Model(&entry).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update registration"}})
+		return
+	}
+
+	m.logAudit(c, "update_tournament_registration", "tournament_registration", &id, nil)
+
+	m.db.First(&entry, "id = ?", id)
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": entry})
+}
+
+// DeleteTournamentRegistration removes an entry made by mistake. Players who pulled
+// out should be marked withdrawn instead, which keeps the record.
+func (m *AdminModule) DeleteTournamentRegistration(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid registration ID"}})
+		return
+	}
+
+	result := m.db.Delete(&domain.TournamentRegistration{}, "id = ?", id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "DELETE_FAILED", "message": "Failed to delete registration"}})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Registration not found"}})
+		return
+	}
+
+	m.logAudit(c, "delete_tournament_registration", "tournament_registration", &id, nil)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Registration deleted"})
+}
+
+// --- Player Flags ---
+
+// ListPlayerFlags lists the automated age-data flags, open ones by default
+func (m *AdminModule) ListPlayerFlags(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit > 100 {
+		limit = 100
+	}
+	offset := (page - 1) * limit
+
+	query := m.db.Model(&domain.PlayerFlag{})
+	if status := c.DefaultQuery("status", agegroup.FlagOpen); status != "all" {
+		query = query.Where("status = ?", status)
+	}
+	if flagType := c.Query("type"); flagType != "" {
+		query = query.Where("type = ?", flagType)
+	}
+	if playerID := c.Query("player_id"); playerID != "" {
+		query = query.Where("player_id = ? OR related_player_id = ?", playerID, playerID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var flags []domain.PlayerFlag
+	if err := query.Preload("Player").Preload("RelatedPlayer").
+		Order("created_at DESC").Offset(offset).Limit(limit).Find(&flags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to fetch flags"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"flags": flags,
+			"types": agegroup.FlagTypes,
+			"pagination": gin.H{
+				"page":        page,
+				"limit":       limit,
+				"total":       total,
+				"total_pages": (total + int64(limit) - 1) / int64(limit),
+			},
+		},
+	})
+}
+
+// DismissPlayerFlag closes an open flag the admin has checked and found to be
+// fine, e.g. a genuinely tall player. Dismissed flags aren't raised again.
+func (m *AdminModule) DismissPlayerFlag(c *gin.Context) {
+	var req struct {
+		Note *string `json:"note"`
+	}
+	c.ShouldBindJSON(&req)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid flag ID"}})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	now := time.Now()
+	result := m.db.Model(&domain.PlayerFlag{}).
+		Where("id = ? AND status = ?", id, agegroup.FlagOpen).
+		Updates(map[string]interface{}{
+			"status":      agegroup.FlagDismissed,
+			"note":        req.Note,
+			"resolved_by": adminID,
+			"resolved_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to dismiss flag"}})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Open flag not found"}})
+		return
+	}
+
+	m.logAudit(c, "dismiss_player_flag", "player_flag", &id, nil)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Flag dismissed"})
+}
+
+// ScanPlayerFlags re-runs the checks on every player. Players are checked when
+// they change, so this is for catching up after imports or rule changes.
+func (m *AdminModule) ScanPlayerFlags(c *gin.Context) {
+	checked, err := agegroup.Scan(c.Request.Context(), m.db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "SCAN_FAILED", "message": "Failed to check players"}})
+		return
+	}
+
+	var open int64
+	m.db.Model(&domain.PlayerFlag{}).Where("status = ?", agegroup.FlagOpen).Count(&open)
+
+	m.logAudit(c, "scan_player_flags", "player_flag", nil, nil)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"checked": checked, "open_flags": open}})
+}
+
+// --- Duplicate Players ---
+
+// ListDuplicateCandidates lists pairs of players that may be the same person, most
+// likely first. Raise min_score to see fewer, surer pairs.
+func (m *AdminModule) ListDuplicateCandidates(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	minScore := duplicates.DefaultMinScore
+	if v, err := strconv.Atoi(c.Query("min_score")); err == nil && v > 0 {
+		minScore = v
+	}
+
+	candidates, err := duplicates.Find(c.Request.Context(), m.db, minScore)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to find duplicates"}})
+		return
+	}
+	total := int64(len(candidates))
+	start := (page - 1) * limit
+	if start > len(candidates) {
+		start = len(candidates)
+	}
+	end := start + limit
+	if end > len(candidates) {
+		end = len(candidates)
+	}
+	candidates = candidates[start:end]
+
+	var ids []uuid.UUID
+	for _, cand := range candidates {
+		ids = append(ids, cand.PlayerID, cand.OtherPlayerID)
+	}
+	players := map[uuid.UUID]gin.H{}
+	if len(ids) > 0 {
+		var rows []domain.Player
+		m.db.Preload("Tournament").Preload("Academy").Preload("School").Where("id IN ?", ids).Find(&rows)
+		for i := range rows {
+			players[rows[i].ID] = duplicatePlayerSummary(&rows[i])
+		}
+	}
+
+	pairs := make([]gin.H, len(candidates))
+	for i, cand := range candidates {
+		pairs[i] = gin.H{
+			"player":       players[cand.PlayerID],
+			"other_player": players[cand.OtherPlayerID],
+			"score":        cand.Score,
+			"reasons":      cand.Reasons,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"candidates": pairs,
+			"min_score":  minScore,
+			"pagination": gin.H{
+				"page":        page,
+				"limit":       limit,
+				"total":       total,
+				"total_pages": (total + int64(limit) - 1) / int64(limit),
+			},
+		},
+	})
+}
+
+func duplicatePlayerSummary(p *domain.Player) gin.H {
+	summary := gin.H{
+		"id":                  p.ID,
+		"first_name":          p.FirstName,
+		"last_name":           p.LastName,
+		"date_of_birth":       p.DateOfBirth.Format("2006-01-02"),
+		"position":            p.Position,
+		"country":             p.Country,
+		"school_name":         p.SchoolName,
+		"verification_status": p.VerificationStatus,
+		"has_account":         p.UserID != nil,
+		"created_at":          p.CreatedAt,
+	}
+	if p.Tournament != nil {
+		summary["tournament"] = gin.H{"id": p.Tournament.ID, "name": p.Tournament.Name, "year": p.Tournament.Year}
+	}
+	if p.Academy != nil {
+		summary["academy"] = gin.H{"id": p.Academy.ID, "name": p.Academy.Name}
+	}
+	if p.School != nil {
+		summary["school"] = gin.H{"id": p.School.ID, "name": p.School.Name}
+	}
+	return summary
+}
+
+// DuplicatePairRequest names a candidate pair
+type DuplicatePairRequest struct {
+	PlayerID      uuid.UUID `json:"player_id" binding:"required"`
+	OtherPlayerID uuid.UUID `json:"other_player_id" binding:"required"`
+}
+
+// DismissDuplicateCandidate marks a pair as two different players so it isn't
+// suggested again
+func (m *AdminModule) DismissDuplicateCandidate(c *gin.Context) {
+	var req DuplicatePairRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+	if req.PlayerID == req.OtherPlayerID {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": "Choose two different players"}})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	if err := duplicates.Dismiss(m.db, req.PlayerID, req.OtherPlayerID, adminID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to dismiss pair"}})
+		return
+	}
+
+	details := fmt.Sprintf(`{"player_id":"%s","other_player_id":"%s"}`, req.PlayerID, req.OtherPlayerID)
+	m.logAudit(c, "dismiss_duplicate_players", "player", &req.PlayerID, &details)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Pair dismissed"})
+}
+
+// MergePlayersRequest is the record to keep and the duplicate to fold into it
+type MergePlayersRequest struct {
+	SurvivorID uuid.UUID `json:"survivor_id" binding:"required"`
+	MergedID   uuid.UUID `json:"merged_id" binding:"required"`
+}
+
+// MergePlayers folds a duplicate player into the one to keep. The merge can be
+// undone from the merge history.
+func (m *AdminModule) MergePlayers(c *gin.Context) {
+	var req MergePlayersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	result, err := duplicates.Merge(m.db, req.SurvivorID, req.MergedID, adminID)
+	if err != nil {
+		mergeError(c, err)
+		return
+	}
+	agegroup.Recheck(m.db, req.SurvivorID, req.MergedID)
+	standings.RefreshPlayers(m.db, req.SurvivorID, req.MergedID)
+
+	details := fmt.Sprintf(`{"merge_id":"%s","survivor_id":"%s","merged_id":"%s"}`, result.Merge.ID, req.SurvivorID, req.MergedID)
+	m.logAudit(c, "merge_players", "player", &req.SurvivorID, &details)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+}
+
+// ListPlayerMerges lists past merges, newest first
+func (m *AdminModule) ListPlayerMerges(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit > 100 {
+		limit = 100
+	}
+	offset := (page - 1) * limit
+
+	query := m.db.Model(&domain.PlayerMerge{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if playerID := c.Query("player_id"); playerID != "" {
+		query = query.Where("survivor_id = ? OR merged_id = ?", playerID, playerID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var merges []domain.PlayerMerge
+	if err := query.Preload("Survivor").Preload("Merged").
+		Order("created_at DESC").Offset(offset).Limit(limit).Find(&merges).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to fetch merges"}})
+		return
+	}
+
+	response := make([]gin.H, len(merges))
+	for i, merge := range merges {
+		response[i] = gin.H{
+			"id":                  merge.ID,
+			"status":              merge.Status,
+			"merged_by":           merge.MergedBy,
+			"created_at":          merge.CreatedAt,
+			"undone_by":           merge.UndoneBy,
+			"undone_at":           merge.UndoneAt,
+			"moved_user_id":       merge.MovedUserID,
+			"deactivated_user_id": merge.DeactivatedUserID,
+		}
+		if merge.Survivor != nil {
+			response[i]["survivor"] = duplicatePlayerSummary(merge.Survivor)
+		}
+		if merge.Merged != nil {
+			response[i]["merged"] = duplicatePlayerSummary(merge.Merged)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"merges": response,
+			"pagination": gin.H{
+				"page":        page,
+				"limit":       limit,
+				"total":       total,
+				"total_pages": (total + int64(limit) - 1) / int64(limit),
+			},
+		},
+	})
+}
+
+// UndoPlayerMerge reverses a merge and restores the merged player
+func (m *AdminModule) UndoPlayerMerge(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid merge ID"}})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	merge, err := duplicates.Undo(m.db, id, adminID)
+	if err != nil {
+		mergeError(c, err)
+		return
+	}
+	agegroup.Recheck(m.db, merge.SurvivorID, merge.MergedID)
+	standings.RefreshPlayers(m.db, merge.SurvivorID, merge.MergedID)
+
+	details := fmt.Sprintf(`{"merge_id":"%s","survivor_id":"%s","merged_id":"%s"}`, merge.ID, merge.SurvivorID, merge.MergedID)
+	m.logAudit(c, "undo_player_merge", "player", &merge.MergedID, &details)
+
+	merge.Rows = nil
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": merge})
+}
+
+func mergeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, duplicates.ErrSamePlayer):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+	case errors.Is(err, duplicates.ErrPlayerNotFound), errors.Is(err, duplicates.ErrMergeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": err.Error()}})
+	case errors.Is(err, duplicates.ErrMergeUndone), errors.Is(err, duplicates.ErrMergedRestored):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "INVALID_STATE", "message": err.Error()}})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "MERGE_FAILED", "message": "Failed to update players"}})
+	}
+}
+
+// --- User Management ---
+
+// ListUsers lists all users (admin only)
+func (m *AdminModule) ListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset := (page - 1) * limit
+
+	var total int64
+
+	query := m.db.Table("users").
+		Select("users.id, users.email, users.role, users.email_verified, users.is_active, users.created_at, users.last_login_at, scouts.organization_name, subscriptions.tier as subscription_tier").
+		Joins("LEFT JOIN scouts ON scouts.user_id = users.id").
+		Joins("LEFT JOIN subscriptions ON subscriptions.user_id = users.id")
+
+	// Apply filters
+	if role := c.Query("role"); role != "" {
+		query = query.Where("users.role = ?", role)
+	}
+	if tier := c.Query("subscription_tier"); tier != "" {
+		query = query.Where("subscriptions.tier = ?", tier)
+	}
+
+	query.Count(&total)
+
+	var results []map[string]interface{}
+	query.Offset(offset).Limit(limit).Order("users.created_at DESC").Scan(&results)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"users": results,
+			"pagination": gin.H{
+				"page":        page,
+				"limit":       limit,
+				"total":       total,
+				"total_pages": (total + int64(limit) - 1) / int64(limit),
+			},
+		},
+	})
+}
+
+// UpdateUser updates a user's status
+func (m *AdminModule) UpdateUser(c *gin.Context) {
+	userID := c.Param("id")
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid user ID"}})
+		return
+	}
+
+	var user domain.User
+	if err := m.db.First(&user, "id = ?", uid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "User not found"}})
+		return
+	}
+
+	var req map[string]interface{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	// Only allow updating specific fields
+	updates := make(map[string]interface{})
+	if val, ok := req["is_active"]; ok {
+		updates["is_active"] = val
+	}
+	if val, ok := req["role"]; ok {
+		updates["role"] = val
+	}
+	updates["updated_at"] = time.Now()
+
+	if err := m.db.Model(&user).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update user"}})
+		return
+	}
+
+	m.logAudit(c, "update_user", "user", &uid, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"id":         user.ID,
+			"is_active":  user.IsActive,
+			"updated_at": time.Now(),
+		},
+	})
+}
+
+// --- Guardians & Consent ---
+
+// GetPlayerConsent returns a player's guardians, consent history and which
+// consents are missing before a minor's data can be published
+func (m *AdminModule) GetPlayerConsent(c *gin.Context) {
+	player, ok := m.findPlayer(c)
+	if !ok {
+		return
+	}
+
+	var guardians []domain.PlayerGuardian
+	m.db.Preload("Guardian").Where("player_id = ?", player.ID).Order("is_primary DESC, created_at").Find(&guardians)
+
+	var records []domain.ConsentRecord
+	m.db.Preload("Guardian").Where("player_id = ?", player.ID).Order("signed_at DESC").Find(&records)
+
+	history := make([]gin.H, len(records))
+	for i, r := range records {
+		history[i] = m.consentResponse(r)
+	}
+
+	current, err := consent.Current(m.db, player.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to load consents"}})
+		return
+	}
+	isMinor := consent.IsMinor(player)
+	status := make([]gin.H, 0, len(consent.Types))
+	missing := []string{}
+	for _, t := range consent.Types {
+		entry := gin.H{"consent_type": t, "required_version": consent.CurrentVersions[t], "granted": current[t] != nil}
+		if current[t] != nil {
+			entry["consent_id"] = current[t].ID
+			entry["signed_at"] = current[t].SignedAt
+		} else if isMinor {
+			missing = append(missing, t)
+		}
+		status = append(status, entry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"player_id": player.ID,
+			"age":       player.GetAge(),
+			"is_minor":  isMinor,
+			"guardians": guardians,
+			"status":    status,
+			"missing":   missing,
+			"history":   history,
+		},
+	})
+}
+
+// AddGuardianRequest links a new or existing guardian to a player
+type AddGuardianRequest struct {
+	GuardianID   *string `json:"guardian_id,omitempty"` // link an existing guardian (e.g. a sibling's)
+	FirstName    string  `json:"first_name"`
+	LastName     string  `json:"last_name"`
+	Email        *string `json:"email,omitempty" binding:"omitempty,email"`
+	Phone        *string `json:"phone,omitempty"`
+	Address      *string `json:"address,omitempty"`
+	Relationship string  `json:"relationship" binding:"omitempty,oneof=parent legal_guardian relative other"`
+	IsPrimary    bool    `json:"is_primary"`
+}
+
+// AddPlayerGuardian links a guardian to a player, creating the guardian unless
+// guardian_id is given
+func (m *AdminModule) AddPlayerGuardian(c *gin.Context) {
+	player, ok := m.findPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req AddGuardianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+	if req.Relationship == "" {
+		req.Relationship = "parent"
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	var guardian domain.Guardian
+	if req.GuardianID != nil && *req.GuardianID != "" {
+		if err := m.db.First(&guardian, "id = ?", *req.GuardianID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Guardian not found"}})
+			return
+		}
+	} else {
+		if strings.TrimSpace(req.FirstName) == "" || strings.TrimSpace(req.LastName) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": "first_name and last_name are required for a new guardian"}})
+			return
+		}
+		guardian = domain.Guardian{
+			FirstName: strings.TrimSpace(req.FirstName),
+			LastName:  strings.TrimSpace(req.LastName),
+			Email:     req.Email,
+			Phone:     req.Phone,
+			Address:   req.Address,
+			CreatedBy: adminID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+	}
+
+	link := domain.PlayerGuardian{
+		PlayerID:     player.ID,
+		GuardianID:   guardian.ID,
+		Relationship: req.Relationship,
+		IsPrimary:    req.IsPrimary,
+		CreatedAt:    time.Now(),
+	}
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if guardian.ID == uuid.Nil {
+			if err := tx.Create(&guardian).Error; err != nil {
+				return err
+			}
+			link.GuardianID = guardian.ID
+		}
+		if link.IsPrimary {
+			if err := tx.Model(&domain.PlayerGuardian{}).Where("player_id = ?", player.ID).Update("is_primary", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&link).Error
+	})
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid tournament ID"}})
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "LINK_FAILED", "message": "Failed to link guardian (already linked?)"}})
 		return
 	}
 
-	var tournament domain.Tournament
-	if err := m.db.First(&tournament, "id = ?", tid).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Tournament not found"}})
+	m.logAudit(c, "add_player_guardian", "player", &player.ID, nil)
+
+	link.Guardian = &guardian
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": link})
+}
+
+// UpdateGuardian updates a guardian's contact details
+func (m *AdminModule) UpdateGuardian(c *gin.Context) {
+	var guardian domain.Guardian
+	if err := m.db.First(&guardian, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Guardian not found"}})
 		return
 	}
 
-	var req map[string]interface{}
+	var req struct {
+		FirstName *string `json:"first_name,omitempty"`
+		LastName  *string `json:"last_name,omitempty"`
+		Email     *string `json:"email,omitempty" binding:"omitempty,email"`
+		Phone     *string `json:"phone,omitempty"`
+		Address   *string `json:"address,omitempty"`
+	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
 		return
 	}
 
-	if err := m.db.Model(&tournament).Updates(req).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update tournament"}})
-		return
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	if req.FirstName != nil && strings.TrimSpace(*req.FirstName) != "" {
+		updates["first_name"] = strings.TrimSpace(*req.FirstName)
+	}
+	if req.LastName != nil && strings.TrimSpace(*req.LastName) != "" {
+		updates["last_name"] = strings.TrimSpace(*req.LastName)
+	}
+	if req.Email != nil {
+		updates["email"] = *req.Email
+	}
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
 	}
 
-	m.logAudit(c, "update_tournament", "tournament", &tid, nil)
+	if err := m.db.Model(&guardian).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update guardian"}})
+		return
+	}
+	m.logAudit(c, "update_guardian", "guardian", &guardian.ID, nil)
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"id":         tournament.ID,
-			"name":       tournament.Name,
-			"updated_at": time.Now(),
-		},
-	})
+	m.db.First(&guardian, "id = ?", guardian.ID)
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": guardian})
 }
 
-// --- User Management ---
+// RemovePlayerGuardian unlinks a guardian from a player. Consents they gave stay
+// on record until withdrawn.
+func (m *AdminModule) RemovePlayerGuardian(c *gin.Context) {
+	result := m.db.Where("player_id = ? AND guardian_id = ?", c.Param("id"), c.Param("guardianId")).Delete(&domain.PlayerGuardian{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Guardian is not linked to this player"}})
+		return
+	}
 
-// ListUsers lists all users (admin only)
-func (m *AdminModule) ListUsers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset := (page - 1) * limit
+	if pid, err := uuid.Parse(c.Param("id")); err == nil {
+		m.logAudit(c, "remove_player_guardian", "player", &pid, nil)
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Guardian removed"})
+}
 
-	var total int64
+// RecordConsentRequest records consent given on one form, which may cover several types
+type RecordConsentRequest struct {
+	ConsentTypes []string `json:"consent_types" binding:"required,min=1"`
+	GuardianID   *string  `json:"guardian_id,omitempty"` // required for minors
+	Method       string   `json:"method" binding:"required,oneof=signed_form in_person electronic"`
+	SignedAt     *string  `json:"signed_at,omitempty"`    // YYYY-MM-DD, defaults to today
+	EvidenceKey  *string  `json:"evidence_key,omitempty"` // s3_key of a consent_evidence upload, required for signed forms
+	Notes        *string  `json:"notes,omitempty"`
+}
 
-	query := m.db.Table("users").
-		Select("users.id, users.email, users.role, users.email_verified, users.is_active, users.created_at, users.last_login_at, scouts.organization_name, subscriptions.tier as subscription_tier").
-		Joins("LEFT JOIN scouts ON scouts.user_id = users.id").
-		Joins("LEFT JOIN subscriptions ON subscriptions.user_id = users.id")
+// RecordConsent stores consent against the current version of each consent form
+func (m *AdminModule) RecordConsent(c *gin.Context) {
+	player, ok := m.findPlayer(c)
+	if !ok {
+		return
+	}
 
-	// Apply filters
-	if role := c.Query("role"); role != "" {
-		query = query.Where("users.role = ?", role)
+	var req RecordConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
 	}
-	if tier := c.Query("subscription_tier"); tier != "" {
-		query = query.Where("subscriptions.tier = ?", tier)
+	for _, t := range req.ConsentTypes {
+		if !consent.IsValidType(t) {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_CONSENT_TYPE", "message": fmt.Sprintf("Unknown consent type %q", t)}})
+			return
+		}
 	}
 
-	query.Count(&total)
-
-	var results []map[string]interface{}
-	query.Offset(offset).Limit(limit).Order("users.created_at DESC").Scan(&results)
+	signedAt := time.Now()
+	if req.SignedAt != nil && *req.SignedAt != "" {
+		parsed, err := time.Parse("2006-01-02", *req.SignedAt)
+		if err != nil || parsed.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DATE", "message": "signed_at must be a past date in YYYY-MM-DD format"}})
+			return
+		}
+		signedAt = parsed
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"users": results,
-			"pagination": gin.H{
-				"page":        page,
-				"limit":       limit,
-				"total":       total,
-				"total_pages": (total + int64(limit) - 1) / int64(limit),
-			},
-		},
-	})
-}
+	// Minors' consent comes from a guardian linked to the player
+	var guardianID *uuid.UUID
+	if req.GuardianID != nil && *req.GuardianID != "" {
+		gid, err := uuid.Parse(*req.GuardianID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid guardian_id"}})
+			return
+		}
+		var linked int64
+		m.db.Model(&domain.PlayerGuardian{}).Where("player_id = ? AND guardian_id = ?", player.ID, gid).Count(&linked)
+		if linked == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "GUARDIAN_NOT_LINKED", "message": "Guardian is not linked to this player"}})
+			return
+		}
+		guardianID = &gid
+	} else if consent.IsMinor(player) {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "GUARDIAN_REQUIRED", "message": "Consent for a minor must be given by a linked guardian"}})
+		return
+	}
 
-// UpdateUser updates a user's status
-func (m *AdminModule) UpdateUser(c *gin.Context) {
-	userID := c.Param("id")
-	uid, err := uuid.Parse(userID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid user ID"}})
+	var evidenceURL *string
+	if req.EvidenceKey != nil && *req.EvidenceKey != "" {
+		if !strings.HasPrefix(*req.EvidenceKey, "private/consents/") {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_EVIDENCE", "message": "Evidence must be a consent_evidence upload"}})
+			return
+		}
+		if _, err := m.uploads.VerifyByKey(c.Request.Context(), *req.EvidenceKey); err != nil {
+			var mismatch *storage.MismatchError
+			if errors.As(err, &mismatch) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"code": "UPLOAD_VERIFICATION_FAILED", "message": mismatch.Reason}})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "VERIFICATION_ERROR", "message": "Failed to verify evidence upload"}})
+			return
+		}
+		url := storage.ObjectURL(m.store, *req.EvidenceKey)
+		evidenceURL = &url
+	} else if req.Method == "signed_form" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "EVIDENCE_REQUIRED", "message": "Upload the signed form as evidence"}})
 		return
 	}
 
-	var user domain.User
-	if err := m.db.First(&user, "id = ?", uid).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "User not found"}})
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	records := make([]domain.ConsentRecord, 0, len(req.ConsentTypes))
+	seen := map[string]bool{}
+	for _, t := range req.ConsentTypes {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		records = append(records, domain.ConsentRecord{
+			PlayerID:    player.ID,
+			GuardianID:  guardianID,
+			ConsentType: t,
+			Version:     consent.CurrentVersions[t],
+			Method:      req.Method,
+			SignedAt:    signedAt,
+			EvidenceURL: evidenceURL,
+			Notes:       req.Notes,
+			RecordedBy:  adminID,
+			CreatedAt:   time.Now(),
+		})
+	}
+	if err := m.db.Create(&records).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to record consent"}})
 		return
 	}
 
-	var req map[string]interface{}
+	details := fmt.Sprintf(`{"consent_types":"%s","method":"%s"}`, strings.Join(req.ConsentTypes, ","), req.Method)
+	m.logAudit(c, "record_consent", "player", &player.ID, &details)
+
+	response := make([]gin.H, len(records))
+	for i, r := range records {
+		response[i] = m.consentResponse(r)
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": response})
+}
+
+// WithdrawConsent marks a consent as withdrawn. Publication stops immediately
+// for minors without another current consent of the same type.
+func (m *AdminModule) WithdrawConsent(c *gin.Context) {
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": "A reason is required"}})
 		return
 	}
 
-	// Only allow updating specific fields
-	updates := make(map[string]interface{})
-	if val, ok := req["is_active"]; ok {
-		updates["is_active"] = val
+	var record domain.ConsentRecord
+	if err := m.db.First(&record, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Consent record not found"}})
+		return
 	}
-	if val, ok := req["role"]; ok {
-		updates["role"] = val
+	if record.WithdrawnAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "ALREADY_WITHDRAWN", "message": "Consent was already withdrawn"}})
+		return
 	}
-	updates["updated_at"] = time.Now()
 
-	if err := m.db.Model(&user).Updates(updates).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update user"}})
+	now := time.Now()
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	record.WithdrawnAt = &now
+	record.WithdrawnBy = &adminID
+	record.WithdrawalReason = &req.Reason
+	if err := m.db.Model(&record).Updates(map[string]interface{}{
+		"withdrawn_at":      now,
+		"withdrawn_by":      adminID,
+		"withdrawal_reason": req.Reason,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to withdraw consent"}})
 		return
 	}
 
-	m.logAudit(c, "update_user", "user", &uid, nil)
+	details := fmt.Sprintf(`{"consent_id":"%s","consent_type":"%s"}`, record.ID, record.ConsentType)
+	m.logAudit(c, "withdraw_consent", "player", &record.PlayerID, &details)
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"id":         user.ID,
-			"is_active":  user.IsActive,
-			"updated_at": time.Now(),
-		},
-	})
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": m.consentResponse(record)})
+}
+
+// consentResponse adds a short-lived link to the evidence for admins
+func (m *AdminModule) consentResponse(r domain.ConsentRecord) gin.H {
+	response := gin.H{
+		"id":                r.ID,
+		"consent_type":      r.ConsentType,
+		"version":           r.Version,
+		"is_current":        r.WithdrawnAt == nil && r.Version == consent.CurrentVersions[r.ConsentType],
+		"method":            r.Method,
+		"signed_at":         r.SignedAt,
+		"guardian_id":       r.GuardianID,
+		"notes":             r.Notes,
+		"withdrawn_at":      r.WithdrawnAt,
+		"withdrawal_reason": r.WithdrawalReason,
+		"created_at":        r.CreatedAt,
+	}
+	if r.Guardian != nil {
+		response["guardian_name"] = r.Guardian.FirstName + " " + r.Guardian.LastName
+	}
+	if r.EvidenceURL != nil {
+		response["evidence_url"] = storage.SignedURL(m.store, *r.EvidenceURL)
+	}
+	return response
+}
+
+// findPlayer loads the player in the URL, writing the error response when missing
+func (m *AdminModule) findPlayer(c *gin.Context) (*domain.Player, bool) {
+	pid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid player ID"}})
+		return nil, false
+	}
+	var player domain.Player
+	if err := m.db.First(&player, "id = ? AND deleted_at IS NULL", pid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Player not found"}})
+		return nil, false
+	}
+	return &player, true
 }
 
 // --- Helper Functions ---
@@ -1450,6 +3612,136 @@ func (m *AdminModule) ListAuditLogs(c *gin.Context) {
 	})
 }
 
+// ListMinorAccessLogs returns who read or exported minors' data. Filters: player_id,
+// user_id, action, from and to (YYYY-MM-DD, to is inclusive).
+func (m *AdminModule) ListMinorAccessLogs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	offset := (page - 1) * limit
+
+	query := m.db.Model(&domain.MinorAccessLog{})
+	for _, filter := range []string{"player_id", "user_id"} {
+		if value := c.Query(filter); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid " + filter}})
+				return
+			}
+			query = query.Where(filter+" = ?", id)
+		}
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DATE", "message": "from must be YYYY-MM-DD"}})
+			return
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DATE", "message": "to must be YYYY-MM-DD"}})
+			return
+		}
+		query = query.Where("created_at < ?", t.AddDate(0, 0, 1))
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var logs []domain.MinorAccessLog
+	query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&logs)
+
+	var userIDs, playerIDs []uuid.UUID
+	for _, entry := range logs {
+		userIDs = append(userIDs, entry.UserID)
+		playerIDs = append(playerIDs, entry.PlayerID)
+	}
+	users := make(map[uuid.UUID]domain.User)
+	players := make(map[uuid.UUID]domain.Player)
+	if len(logs) > 0 {
+		var userList []domain.User
+		m.db.Where("id IN ?", userIDs).Find(&userList)
+		for _, u := range userList {
+			users[u.ID] = u
+		}
+		var playerList []domain.Player
+		m.db.Where("id IN ?", playerIDs).Find(&playerList)
+		for _, p := range playerList {
+			players[p.ID] = p
+		}
+	}
+
+	response := make([]gin.H, len(logs))
+	for i, entry := range logs {
+		u := users[entry.UserID]
+		p := players[entry.PlayerID]
+		response[i] = gin.H{
+			"id":            entry.ID,
+			"user":          gin.H{"id": entry.UserID, "email": u.Email, "name": u.FirstName + " " + u.LastName, "role": entry.UserRole},
+			"player":        gin.H{"id": entry.PlayerID, "name": p.FirstName + " " + p.LastName},
+			"action":        entry.Action,
+			"resource_type": entry.ResourceType,
+			"resource_id":   entry.ResourceID,
+			"ip_address":    entry.IPAddress,
+			"user_agent":    entry.UserAgent,
+			"created_at":    entry.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"logs":    response,
+			"actions": access.Actions,
+			"total":   total,
+			"page":    page,
+			"limit":   limit,
+		},
+	})
+}
+
+// GetMinorAccessAnomalies flags accounts that viewed unusually many minors in the
+// last days (default 1): more distinct minors than threshold (default 50), or a
+// spike against the account's own rate over the 30 days before
+func (m *AdminModule) GetMinorAccessAnomalies(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "1"))
+	if days < 1 || days > 90 {
+		days = 1
+	}
+	threshold, _ := strconv.Atoi(c.DefaultQuery("threshold", "50"))
+	if threshold < 1 {
+		threshold = 50
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	anomalies, err := access.Anomalies(c.Request.Context(), m.db, since, threshold)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "QUERY_FAILED", "message": "Failed to compute access anomalies"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"anomalies": anomalies,
+			"since":     since,
+			"days":      days,
+			"threshold": threshold,
+		},
+	})
+}
+
 // ==================== BULK OPERATIONS ====================
 
 // BulkPlayersRequest is the request for bulk player operations
@@ -1479,16 +3771,30 @@ func (m *AdminModule) BulkUpdatePlayers(c *gin.Context) {
 
 	var result *gorm.DB
 	var auditAction string
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	now := time.Now()
 
 	switch req.Action {
 	case "delete":
-		result = m.db.Model(&domain.Player{}).Where("id IN ?", playerIDs).Update("deleted_at", time.Now())
+		result = m.db.Model(&domain.Player{}).Where("id IN ?", playerIDs).Update("deleted_at", now)
 		auditAction = "bulk_delete_players"
 	case "verify":
-		result = m.db.Model(&domain.Player{}).Where("id IN ?", playerIDs).Update("verification_status", "verified")
+		result = m.db.Model(&domain.Player{}).Where("id IN ?", playerIDs).Updates(map[string]interface{}{
+			"verification_status": "verified",
+			"verification_source": verification.SourceAdmin,
+			"verified_at":         now,
+			"verified_by":         adminID,
+			"updated_at":          now,
+		})
 		auditAction = "bulk_verify_players"
 	case "unverify":
-		result = m.db.Model(&domain.Player{}).Where("id IN ?", playerIDs).Update("verification_status", "pending")
+		result = m.db.Model(&domain.Player{}).Where("id IN ?", playerIDs).Updates(map[string]interface{}{
+			"verification_status": "pending",
+			"verification_source": nil,
+			"verified_at":         nil,
+			"verified_by":         nil,
+			"updated_at":          now,
+		})
 		auditAction = "bulk_unverify_players"
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid action"})
@@ -1500,6 +3806,12 @@ func (m *AdminModule) BulkUpdatePlayers(c *gin.Context) {
 		return
 	}
 
+	if req.Action != "delete" {
+		verification.RecordManual(m.db, playerIDs, adminID, req.Action == "verify")
+	} else {
+		standings.RefreshPlayers(m.db, playerIDs...)
+	}
+
 	details := fmt.Sprintf(`{"count": %d, "player_ids": %v}`, len(playerIDs), req.PlayerIDs)
 	m.logAudit(c, auditAction, "players", nil, &details)
 
@@ -1608,6 +3920,7 @@ func (m *AdminModule) ExportPlayers(c *gin.Context) {
 	}
 
 	m.logAudit(c, "export_players", "players", nil, nil)
+	access.Record(m.db, c, access.ActionExport, "players", nil, players...)
 }
 
 // ExportUsers exports users as CSV
@@ -1752,9 +4065,65 @@ func (m *AdminModule) GetAnalytics(c *gin.Context) {
 		Limit(10).
 		Find(&countryCounts)
 
+	// Free previews of locked matches, and how many logged-in previewers went on to
+	// buy that match or subscribe (Scout+) after their first preview
+	var previewViews []dailyCount
+	m.db.Model(&domain.MatchPreviewView{}).
+		Select("DATE(created_at) as date, COUNT(*) as count").
+		Where("created_at >= ?", startDate).
+		Group("DATE(created_at)").
+		Order("date").
+		Find(&previewViews)
+
+	var previewFunnel struct {
+		Previewers int64 `json:"previewers"`
+		Purchased  int64 `json:"purchased"`
+		Subscribed int64 `json:"subscribed"`
+		Converted  int64 `json:"converted"`
+	}
+	m.db.Raw(`
+		WITH first_preview AS (
+			SELECT viewer_id, match_video_id, MIN(created_at) AS first_at
+			FROM match_preview_views
+			WHERE viewer_id IS NOT NULL AND created_at >= ?
+			GROUP BY viewer_id, match_video_id
+		), outcomes AS (
+			SELECT fp.viewer_id,
+				EXISTS (
+					SELECT 1 FROM match_purchases mp
+					WHERE mp.user_id = fp.viewer_id AND mp.match_video_id = fp.match_video_id
+					  AND mp.status = 'completed' AND mp.created_at >= fp.first_at
+				) AS purchased,
+				EXISTS (
+					SELECT 1 FROM subscriptions s
+					WHERE s.user_id = fp.viewer_id AND s.status = 'active'
+					  AND s.tier IN ('scout', 'pro', 'club') AND s.updated_at >= fp.first_at
+				) AS subscribed
+			FROM first_preview fp
+		)
+		SELECT
+			COUNT(DISTINCT viewer_id) AS previewers,
+			COUNT(DISTINCT viewer_id) FILTER (WHERE purchased) AS purchased,
+			COUNT(DISTINCT viewer_id) FILTER (WHERE subscribed) AS subscribed,
+			COUNT(DISTINCT viewer_id) FILTER (WHERE purchased OR subscribed) AS converted
+		FROM outcomes`, startDate).Scan(&previewFunnel)
+
+	conversionRate := 0.0
+	if previewFunnel.Previewers > 0 {
+		conversionRate = float64(previewFunnel.Converted) / float64(previewFunnel.Previewers)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
+			"preview_views": previewViews,
+			"preview_conversion": gin.H{
+				"previewers":      previewFunnel.Previewers,
+				"purchased":       previewFunnel.Purchased,
+				"subscribed":      previewFunnel.Subscribed,
+				"converted":       previewFunnel.Converted,
+				"conversion_rate": conversionRate,
+			},
 			"user_signups":       userSignups,
 			"player_signups":     playerSignups,
 			"video_uploads":      videoUploads,
@@ -1767,6 +4136,99 @@ func (m *AdminModule) GetAnalytics(c *gin.Context) {
 	})
 }
 
+// GetViewAnalytics returns daily views (from the rollups) and the most viewed
+// highlights and players over the last ?days= days
+func (m *AdminModule) GetViewAnalytics(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days < 1 {
+		days = 1
+	}
+	if days > 365 {
+		days = 365
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	ctx := c.Request.Context()
+	from := time.Now().AddDate(0, 0, -days)
+
+	daily, err := m.views.Daily(ctx, from, "", nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "QUERY_FAILED", "message": "Failed to load view analytics"}})
+		return
+	}
+	highlightDaily, _ := m.views.Daily(ctx, from, views.AssetHighlight, nil)
+	videoDaily, _ := m.views.Daily(ctx, from, views.AssetVideo, nil)
+
+	// Most viewed highlights, with title and player
+	topHighlights, _ := m.views.TopAssets(ctx, from, views.AssetHighlight, limit)
+	highlightIDs := make([]uuid.UUID, len(topHighlights))
+	for i, t := range topHighlights {
+		highlightIDs[i] = t.ID
+	}
+	highlightsByID := map[uuid.UUID]domain.PlayerHighlight{}
+	if len(highlightIDs) > 0 {
+		var highlights []domain.PlayerHighlight
+		m.db.Preload("Player").Where("id IN ?", highlightIDs).Find(&highlights)
+		for _, h := range highlights {
+			highlightsByID[h.ID] = h
+		}
+	}
+	highlightRows := make([]gin.H, len(topHighlights))
+	for i, t := range topHighlights {
+		row := gin.H{"highlight_id": t.ID, "views": t.Views, "unique_viewers": t.UniqueViewers}
+		if h, ok := highlightsByID[t.ID]; ok {
+			row["title"] = h.Title
+			row["highlight_type"] = h.HighlightType
+			row["total_views"] = h.ViewCount
+			if h.Player != nil {
+				row["player_id"] = h.PlayerID
+				row["player_name"] = h.Player.FirstName + " " + h.Player.LastName
+			}
+		}
+		highlightRows[i] = row
+	}
+
+	// Most viewed players across highlights and videos
+	topPlayers, _ := m.views.TopPlayers(ctx, from, limit)
+	playerIDs := make([]uuid.UUID, len(topPlayers))
+	for i, t := range topPlayers {
+		playerIDs[i] = t.ID
+	}
+	playersByID := map[uuid.UUID]domain.Player{}
+	if len(playerIDs) > 0 {
+		var players []domain.Player
+		m.db.Where("id IN ?", playerIDs).Find(&players)
+		for _, p := range players {
+			playersByID[p.ID] = p
+		}
+	}
+	playerRows := make([]gin.H, len(topPlayers))
+	for i, t := range topPlayers {
+		row := gin.H{"player_id": t.ID, "views": t.Views, "unique_viewers": t.UniqueViewers}
+		if p, ok := playersByID[t.ID]; ok {
+			row["player_name"] = p.FirstName + " " + p.LastName
+			row["position"] = p.Position
+			row["country"] = p.Country
+		}
+		playerRows[i] = row
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"days":            days,
+			"daily":           daily,
+			"highlight_daily": highlightDaily,
+			"video_daily":     videoDaily,
+			"top_highlights":  highlightRows,
+			"top_players":     playerRows,
+		},
+	})
+}
+
 func ptrUUID(u uuid.UUID) *uuid.UUID {
 	return &u
 }
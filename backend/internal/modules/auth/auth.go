@@ -82,6 +82,9 @@ type UserResponse struct {
 	Role          string    `json:"role"`
 	EmailVerified bool      `json:"email_verified"`
 	CreatedAt     time.Time `json:"created_at"`
+
+	// The client must send the user to change their password before anything else
+	MustChangePassword bool `json:"must_change_password"`
 }
 
 // SubscriptionResponse represents subscription info
@@ -254,6 +257,8 @@ func (a *AuthModule) Login(c *gin.Context) {
 			Role:          user.Role,
 			EmailVerified: user.EmailVerified,
 			CreatedAt:     user.CreatedAt,
+
+			MustChangePassword: user.MustChangePassword,
 		},
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -348,6 +353,8 @@ func (a *AuthModule) GetMe(c *gin.Context) {
 			"is_active":      user.IsActive,
 			"created_at":     user.CreatedAt,
 			"last_login_at":  user.LastLoginAt,
+
+			"must_change_password": user.MustChangePassword,
 		},
 	})
 }
@@ -705,8 +712,9 @@ func (a *AuthModule) ResetPassword(c *gin.Context) {
 
 	// Update user password
 	if err := tx.Model(&user).Updates(map[string]interface{}{
-		"password_hash": string(hashedPassword),
-		"updated_at":    time.Now(),
+		"password_hash":        string(hashedPassword),
+		"must_change_password": false,
+		"updated_at":           time.Now(),
 	}).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -814,8 +822,9 @@ func (a *AuthModule) ChangePassword(c *gin.Context) {
 
 	// Update password
 	if err := a.db.Model(&user).Updates(map[string]interface{}{
-		"password_hash": string(hashedPassword),
-		"updated_at":    time.Now(),
+		"password_hash":        string(hashedPassword),
+		"must_change_password": false,
+		"updated_at":           time.Now(),
 	}).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -955,3 +964,61 @@ func (a *AuthModule) UpdateNotifications(c *gin.Context) {
 		},
 	})
 }
+
+// --- Notifications ---
+
+// ListNotifications returns the user's latest notifications, optionally unread only
+func (a *AuthModule) ListNotifications(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	query := a.db.Where("user_id = ?", userID)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+	var notifications []domain.Notification
+	if err := query.Order("created_at DESC").Limit(50).Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to fetch notifications"}})
+		return
+	}
+
+	var unread int64
+	a.db.Model(&domain.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&unread)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"notifications": notifications,
+			"unread_count":  unread,
+		},
+	})
+}
+
+// MarkNotificationRead marks one of the user's notifications as read
+func (a *AuthModule) MarkNotificationRead(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	result := a.db.Model(&domain.Notification{}).
+		Where("id = ? AND user_id = ?", c.Param("id"), userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Notification not found"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Notification marked as read"})
+}
+
+// MarkAllNotificationsRead marks all of the user's notifications as read
+func (a *AuthModule) MarkAllNotificationsRead(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	result := a.db.Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update notifications"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"updated": result.RowsAffected}})
+}
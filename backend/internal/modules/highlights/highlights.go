@@ -1,15 +1,29 @@
 package highlights
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/unicorn-sport/backend/internal/cdn"
+	"github.com/unicorn-sport/backend/internal/consent"
 	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/privacy"
+	"github.com/unicorn-sport/backend/internal/registrations"
+	"github.com/unicorn-sport/backend/internal/storage"
+	"github.com/unicorn-sport/backend/internal/uploads"
+	"github.com/unicorn-sport/backend/internal/views"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -17,19 +31,21 @@ import (
 
 // Module holds dependencies for the highlights module
 type Module struct {
-	DB       *gorm.DB
-	S3Client *s3.Client
-	S3Bucket string
-	CDNHost  string
+	DB      *gorm.DB
+	Store   storage.Store
+	Uploads *uploads.Service
+	CDN     *cdn.CloudFront // nil when no CDN is configured
+	Views   *views.Tracker
 }
 
 // NewModule creates a new highlights module
-func NewModule(db *gorm.DB, s3Client *s3.Client, bucket, cdnHost string) *Module {
+func NewModule(db *gorm.DB, store storage.Store, uploadService *uploads.Service, cloudFront *cdn.CloudFront, viewTracker *views.Tracker) *Module {
 	return &Module{
-		DB:       db,
-		S3Client: s3Client,
-		S3Bucket: bucket,
-		CDNHost:  cdnHost,
+		DB:      db,
+		Store:   store,
+		Uploads: uploadService,
+		CDN:     cloudFront,
+		Views:   viewTracker,
 	}
 }
 
@@ -53,6 +69,8 @@ func (m *Module) InitHighlightUpload(c *gin.Context) {
 		FileName    string `json:"file_name" binding:"required"`
 		FileSize    int64  `json:"file_size" binding:"required"`
 		ContentType string `json:"content_type" binding:"required"`
+		// Optional SHA-256 of the file (hex or base64), verified after upload
+		ChecksumSHA256 string `json:"checksum_sha256"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -86,13 +104,7 @@ func (m *Module) InitHighlightUpload(c *gin.Context) {
 	s3Key := fmt.Sprintf("highlights/%s/%s/%s", playerID.String(), uuid.New().String()[:8], req.FileName)
 
 	// Generate presigned URL
-	presigner := s3.NewPresignClient(m.S3Client)
-	presignResult, err := presigner.PresignPutObject(c.Request.Context(), &s3.PutObjectInput{
-		Bucket:      aws.String(m.S3Bucket),
-		Key:         aws.String(s3Key),
-		ContentType: aws.String(req.ContentType),
-	}, s3.WithPresignExpires(1*time.Hour))
-
+	uploadURL, err := m.Store.PresignPut(c.Request.Context(), s3Key, req.ContentType, req.FileSize, 1*time.Hour)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to generate upload URL"})
 		return
@@ -117,6 +129,8 @@ func (m *Module) InitHighlightUpload(c *gin.Context) {
 		EntityID:    &playerID,
 		UploadedBy:  userID.(uuid.UUID),
 		ExpiresAt:   time.Now().Add(1 * time.Hour),
+
+		ChecksumSHA256: stringPtr(req.ChecksumSHA256),
 	}
 
 	if err := m.DB.Create(&session).Error; err != nil {
@@ -128,7 +142,7 @@ func (m *Module) InitHighlightUpload(c *gin.Context) {
 		"success": true,
 		"data": gin.H{
 			"session_id": session.ID,
-			"upload_url": presignResult.URL,
+			"upload_url": uploadURL,
 			"s3_key":     s3Key,
 			"expires_in": 3600,
 			"match_id":   matchID,
@@ -186,6 +200,21 @@ func (m *Module) CreateHighlight(c *gin.Context) {
 		}
 	}
 
+	// Make sure the uploaded file passed integrity checks (verifies direct uploads on first use)
+	session, err := m.Uploads.VerifyByKey(c.Request.Context(), req.S3Key)
+	if err != nil {
+		var mismatch *storage.MismatchError
+		if errors.As(err, &mismatch) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "message": "Upload verification failed: " + mismatch.Reason})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to verify upload"})
+		return
+	}
+	if session != nil && req.FileSizeBytes == 0 {
+		req.FileSizeBytes = session.FileSize
+	}
+
 	userID, _ := c.Get("user_id")
 
 	highlight := domain.PlayerHighlight{
@@ -246,12 +275,7 @@ func (m *Module) InitThumbnailUpload(c *gin.Context) {
 	s3Key := fmt.Sprintf("thumbnails/highlights/%s/%s", highlightID, req.FileName)
 
 	// Generate presigned PUT URL
-	presigner := s3.NewPresignClient(m.S3Client)
-	presignedReq, err := presigner.PresignPutObject(c.Request.Context(), &s3.PutObjectInput{
-		Bucket:      aws.String(m.S3Bucket),
-		Key:         aws.String(s3Key),
-		ContentType: aws.String(req.ContentType),
-	}, s3.WithPresignExpires(15*time.Minute))
+	uploadURL, err := m.Store.PresignPut(c.Request.Context(), s3Key, req.ContentType, 0, 15*time.Minute)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to generate upload URL"})
 		return
@@ -260,7 +284,7 @@ func (m *Module) InitThumbnailUpload(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"upload_url": presignedReq.URL,
+			"upload_url": uploadURL,
 			"s3_key":     s3Key,
 		},
 	})
@@ -285,7 +309,7 @@ func (m *Module) UpdateThumbnail(c *gin.Context) {
 	}
 
 	// Build the full S3 URL
-	thumbnailURL := fmt.Sprintf("s3://%s/%s", m.S3Bucket, req.S3Key)
+	thumbnailURL := storage.ObjectURL(m.Store, req.S3Key)
 
 	// Update highlight
 	if err := m.DB.Model(&domain.PlayerHighlight{}).Where("id = ?", hid).Update("thumbnail_url", thumbnailURL).Error; err != nil {
@@ -392,12 +416,13 @@ func (m *Module) ListMatchHighlights(c *gin.Context) {
 		} `json:"highlights"`
 	}
 
+	policy := privacy.For(c, m.DB)
 	playerMap := make(map[uuid.UUID]*playerHighlights)
 	for _, h := range highlights {
 		if _, exists := playerMap[h.PlayerID]; !exists {
 			playerMap[h.PlayerID] = &playerHighlights{
 				PlayerID:        h.PlayerID,
-				PlayerName:      fmt.Sprintf("%s %s", h.Player.FirstName, h.Player.LastName),
+				PlayerName:      policy.Name(h.Player),
 				ProfilePhotoURL: h.Player.ProfilePhotoURL,
 				Highlights: []struct {
 					ID               uuid.UUID `json:"id"`
@@ -459,24 +484,48 @@ func (m *Module) GetHighlight(c *gin.Context) {
 		return
 	}
 
-	// Record view (if not admin viewing)
-	userID, hasUser := c.Get("user_id")
-	source := c.Query("source")
-
-	view := domain.HighlightView{
-		HighlightID: hid,
-		Source:      stringPtr(source),
+	// Record view (deduplicated and written in the background; admins aren't counted)
+	if role, _ := c.Get("user_role"); role != "admin" {
+		event := views.Event{
+			AssetType: views.AssetHighlight,
+			AssetID:   hid,
+			PlayerID:  &highlight.PlayerID,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			Source:    c.Query("source"),
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			uid := userID.(uuid.UUID)
+			event.ViewerID = &uid
+		}
+		m.Views.Record(event)
 	}
-	if hasUser {
-		uid := userID.(uuid.UUID)
-		view.ViewerID = &uid
+
+	// The player is serialized through the privacy policy rather than as the full record
+	detail := struct {
+		domain.PlayerHighlight
+		Player gin.H `json:"player,omitempty"`
+	}{PlayerHighlight: highlight}
+	if highlight.Player != nil {
+		masked := privacy.For(c, m.DB).Mask(highlight.Player)
+		detail.Player = gin.H{
+			"id":                highlight.Player.ID,
+			"first_name":        highlight.Player.FirstName,
+			"last_name":         masked.LastName,
+			"last_name_init":    masked.LastNameInit,
+			"age":               masked.Age,
+			"age_group":         masked.AgeGroup,
+			"position":          highlight.Player.Position,
+			"country":           highlight.Player.Country,
+			"profile_photo_url": m.getThumbnailURL(highlight.Player.ProfilePhotoURL),
+			"is_verified":       highlight.Player.IsVerified(),
+		}
 	}
-	m.DB.Create(&view) // Fire and forget
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"highlight":  highlight,
+			"highlight":  detail,
 			"stream_url": m.getStreamURL(highlight.VideoURL),
 		},
 	})
@@ -559,12 +608,8 @@ func (m *Module) DeleteHighlight(c *gin.Context) {
 		return
 	}
 
-	// Delete from S3
-	_, err = m.S3Client.DeleteObject(c.Request.Context(), &s3.DeleteObjectInput{
-		Bucket: aws.String(m.S3Bucket),
-		Key:    aws.String(highlight.VideoURL),
-	})
-	if err != nil {
+	// Delete from storage
+	if err := m.Store.Delete(c.Request.Context(), highlight.VideoURL); err != nil {
 		fmt.Printf("Warning: Failed to delete S3 object: %v\n", err)
 	}
 
@@ -576,6 +621,698 @@ func (m *Module) DeleteHighlight(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Highlight deleted"})
 }
 
+// ==================== BULK INGEST ====================
+
+// maxIngestItems caps one manifest
+const maxIngestItems = 1000
+
+// ingestUploadExpiry is how long an ingest's upload URLs stay valid. It is longer
+// than for single uploads since hundreds of clips go up one after another.
+const ingestUploadExpiry = 6 * time.Hour
+
+// highlightVideoTypes are the content types accepted for highlight clips
+var highlightVideoTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mov":  "video/quicktime",
+	".avi":  "video/x-msvideo",
+	".webm": "video/webm",
+	".mkv":  "video/x-matroska",
+}
+
+// IngestManifestItem is one clip in an ingest manifest. The player is given by ID,
+// or by jersey number together with a match, and resolved through the match's
+// lineup. Timestamp is seconds, mm:ss or hh:mm:ss into the match.
+type IngestManifestItem struct {
+	FileName       string      `json:"file_name"`
+	FileSize       int64       `json:"file_size"`
+	ContentType    string      `json:"content_type"`
+	ChecksumSHA256 string      `json:"checksum_sha256"`
+	PlayerID       string      `json:"player_id"`
+	JerseyNumber   *int        `json:"jersey_number"`
+	MatchID        string      `json:"match_id"`
+	HighlightType  string      `json:"highlight_type"`
+	Timestamp      interface{} `json:"timestamp"` // string or number
+	Title          string      `json:"title"`
+	Description    string      `json:"description"`
+
+	row int
+}
+
+// StartHighlightIngest validates a manifest of clips and issues a presigned upload
+// for each. The manifest is a CSV or JSON file (multipart "manifest") or a JSON
+// request body: an array of items, or {"match_id", "items"}. CSV columns are named
+// like the JSON keys. A match_id form field or top-level key applies to items
+// without one. Nothing is created if any item is invalid. Once the files are up,
+// CompleteHighlightIngest creates the highlights.
+func (m *Module) StartHighlightIngest(c *gin.Context) {
+	var data []byte
+	manifestName := "manifest.json"
+	defaultMatch := c.PostForm("match_id")
+	if file, err := c.FormFile("manifest"); err == nil {
+		if file.Size > 5<<20 {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Manifest too large (max 5MB)"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read manifest"})
+			return
+		}
+		data, err = io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read manifest"})
+			return
+		}
+		manifestName = file.Filename
+	} else {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, 5<<20+1))
+		if err != nil || len(bytes.TrimSpace(body)) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "A manifest file or JSON body is required"})
+			return
+		}
+		if len(body) > 5<<20 {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Manifest too large (max 5MB)"})
+			return
+		}
+		data = body
+	}
+
+	items, matchID, err := parseIngestManifest(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	if defaultMatch == "" {
+		defaultMatch = matchID
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	resolved, rowErrors := m.resolveIngestItems(items, defaultMatch)
+	if len(rowErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Some items are invalid, nothing was started", "errors": rowErrors})
+		return
+	}
+
+	now := time.Now()
+	ingest := domain.HighlightIngest{
+		ManifestName: manifestName,
+		Status:       "uploading",
+		ItemCount:    len(resolved),
+		CreatedBy:    userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	sessions := make([]domain.UploadSession, len(resolved))
+	urls := make([]string, len(resolved))
+	for i, item := range resolved {
+		key := fmt.Sprintf("highlights/%s/%s/%s", item.PlayerID, uuid.New().String()[:8], path.Base(item.FileName))
+		urls[i], err = m.Store.PresignPut(c.Request.Context(), key, item.contentType, item.fileSize, ingestUploadExpiry)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to generate upload URLs"})
+			return
+		}
+		sessions[i] = domain.UploadSession{
+			UploadType:     "highlight",
+			ContentType:    item.contentType,
+			FileName:       item.FileName,
+			FileSize:       item.fileSize,
+			S3Key:          key,
+			Status:         "pending",
+			EntityType:     stringPtr("highlight"),
+			EntityID:       &resolved[i].PlayerID,
+			UploadedBy:     userID,
+			CreatedAt:      now,
+			ExpiresAt:      now.Add(ingestUploadExpiry),
+			ChecksumSHA256: stringPtr(item.checksum),
+		}
+	}
+
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&ingest).Error; err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(&sessions, 200).Error; err != nil {
+			return err
+		}
+		rows := make([]domain.HighlightIngestItem, len(resolved))
+		for i := range resolved {
+			rows[i] = resolved[i].HighlightIngestItem
+			rows[i].IngestID = ingest.ID
+			rows[i].UploadSessionID = sessions[i].ID
+			rows[i].S3Key = sessions[i].S3Key
+			rows[i].Status = "pending"
+			rows[i].CreatedAt = now
+			rows[i].UpdatedAt = now
+		}
+		if err := tx.CreateInBatches(&rows, 200).Error; err != nil {
+			return err
+		}
+		ingest.Items = rows
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to start ingest"})
+		return
+	}
+
+	m.audit(c, "highlight_ingest_start", "highlight_ingest", &ingest.ID, gin.H{"manifest": manifestName, "items": len(resolved)})
+
+	entries := make([]gin.H, len(ingest.Items))
+	for i, item := range ingest.Items {
+		entries[i] = gin.H{
+			"item_id":    item.ID,
+			"row":        item.Row,
+			"file_name":  item.FileName,
+			"player_id":  item.PlayerID,
+			"session_id": item.UploadSessionID,
+			"upload_url": urls[i],
+			"s3_key":     item.S3Key,
+		}
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("Upload %d files, then complete the ingest", len(entries)),
+		"data": gin.H{
+			"ingest_id":  ingest.ID,
+			"expires_in": int(ingestUploadExpiry.Seconds()),
+			"items":      entries,
+		},
+	})
+}
+
+// CompleteHighlightIngest creates the highlights for every uploaded clip of an
+// ingest. Clips whose upload fails verification are marked failed; clips not
+// uploaded yet stay pending, so it can be called again as uploads finish.
+func (m *Module) CompleteHighlightIngest(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid ingest ID"})
+		return
+	}
+	var ingest domain.HighlightIngest
+	if err := m.DB.First(&ingest, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Ingest not found"})
+		return
+	}
+
+	var items []domain.HighlightIngestItem
+	m.DB.Where("ingest_id = ? AND status = ?", id, "pending").Order("manifest_row").Find(&items)
+
+	var playerIDs []uuid.UUID
+	for _, item := range items {
+		playerIDs = append(playerIDs, item.PlayerID)
+	}
+	var active []uuid.UUID
+	if len(playerIDs) > 0 {
+		m.DB.Model(&domain.Player{}).Where("id IN ? AND deleted_at IS NULL", playerIDs).Pluck("id", &active)
+	}
+	exists := map[uuid.UUID]bool{}
+	for _, pid := range active {
+		exists[pid] = true
+	}
+
+	ctx := c.Request.Context()
+	userID := c.MustGet("user_id").(uuid.UUID)
+	now := time.Now()
+	var highlights []domain.PlayerHighlight
+	var ready []*domain.HighlightIngestItem
+	failed := map[uuid.UUID]string{}
+	var waiting []gin.H
+	for i := range items {
+		item := &items[i]
+		if !exists[item.PlayerID] {
+			failed[item.ID] = "player has been deleted"
+			continue
+		}
+		if _, err := m.Store.Head(ctx, item.S3Key); err != nil {
+			reason := "not uploaded yet"
+			if !errors.Is(err, storage.ErrNotFound) {
+				reason = "storage unavailable, try again"
+			}
+			waiting = append(waiting, gin.H{"item_id": item.ID, "row": item.Row, "file_name": item.FileName, "reason": reason})
+			continue
+		}
+		session, err := m.Uploads.VerifyByKey(ctx, item.S3Key)
+		if err != nil {
+			var mismatch *storage.MismatchError
+			if errors.As(err, &mismatch) {
+				failed[item.ID] = "upload verification failed: " + mismatch.Reason
+			} else {
+				waiting = append(waiting, gin.H{"item_id": item.ID, "row": item.Row, "file_name": item.FileName, "reason": "verification unavailable, try again"})
+			}
+			continue
+		}
+		var size *int64
+		if session != nil {
+			size = int64Ptr(session.FileSize)
+		}
+		highlights = append(highlights, domain.PlayerHighlight{
+			PlayerID:         item.PlayerID,
+			MatchID:          item.MatchID,
+			HighlightType:    item.HighlightType,
+			VideoURL:         item.S3Key,
+			FileSizeBytes:    size,
+			Title:            item.Title,
+			Description:      item.Description,
+			TimestampInMatch: item.TimestampInMatch,
+			Status:           "approved", // Auto-approve for admin uploads
+			UploadedBy:       userID,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		})
+		ready = append(ready, item)
+	}
+
+	status := ingest.Status
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		if len(highlights) > 0 {
+			if err := tx.CreateInBatches(&highlights, 200).Error; err != nil {
+				return err
+			}
+		}
+		for i, item := range ready {
+			if err := tx.Model(item).Updates(map[string]interface{}{
+				"status": "created", "highlight_id": highlights[i].ID, "error": nil, "updated_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		for itemID, reason := range failed {
+			if err := tx.Model(&domain.HighlightIngestItem{}).Where("id = ?", itemID).Updates(map[string]interface{}{
+				"status": "failed", "error": reason, "updated_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		updates := map[string]interface{}{"updated_at": now}
+		if len(waiting) == 0 {
+			status = "completed"
+			updates["status"] = status
+			updates["completed_at"] = now
+		}
+		return tx.Model(&ingest).Updates(updates).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to create highlights"})
+		return
+	}
+
+	m.audit(c, "highlight_ingest_complete", "highlight_ingest", &ingest.ID, gin.H{"created": len(highlights), "failed": len(failed), "pending": len(waiting)})
+
+	failures := make([]gin.H, 0, len(failed))
+	for i := range items {
+		if reason, ok := failed[items[i].ID]; ok {
+			failures = append(failures, gin.H{"item_id": items[i].ID, "row": items[i].Row, "file_name": items[i].FileName, "reason": reason})
+		}
+	}
+	if waiting == nil {
+		waiting = []gin.H{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("Created %d highlights", len(highlights)),
+		"data": gin.H{
+			"ingest_id": ingest.ID,
+			"status":    status,
+			"created":   len(highlights),
+			"failed":    failures,
+			"pending":   waiting,
+		},
+	})
+}
+
+// ListHighlightIngests returns recent ingests with a count of items per status
+func (m *Module) ListHighlightIngests(c *gin.Context) {
+	var ingests []domain.HighlightIngest
+	m.DB.Order("created_at DESC").Limit(50).Find(&ingests)
+
+	ids := make([]uuid.UUID, len(ingests))
+	for i := range ingests {
+		ids[i] = ingests[i].ID
+	}
+	var counts []struct {
+		IngestID uuid.UUID
+		Status   string
+		Count    int
+	}
+	if len(ids) > 0 {
+		m.DB.Model(&domain.HighlightIngestItem{}).Select("ingest_id, status, COUNT(*) AS count").
+			Where("ingest_id IN ?", ids).Group("ingest_id, status").Scan(&counts)
+	}
+	byIngest := map[uuid.UUID]gin.H{}
+	for _, row := range counts {
+		if byIngest[row.IngestID] == nil {
+			byIngest[row.IngestID] = gin.H{}
+		}
+		byIngest[row.IngestID][row.Status] = row.Count
+	}
+
+	response := make([]gin.H, len(ingests))
+	for i, ingest := range ingests {
+		items := byIngest[ingest.ID]
+		if items == nil {
+			items = gin.H{}
+		}
+		response[i] = gin.H{
+			"id":            ingest.ID,
+			"manifest_name": ingest.ManifestName,
+			"status":        ingest.Status,
+			"item_count":    ingest.ItemCount,
+			"items":         items,
+			"created_at":    ingest.CreatedAt,
+			"completed_at":  ingest.CompletedAt,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": response})
+}
+
+// GetHighlightIngest returns an ingest with all its items
+func (m *Module) GetHighlightIngest(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid ingest ID"})
+		return
+	}
+	var ingest domain.HighlightIngest
+	if err := m.DB.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("manifest_row") }).
+		First(&ingest, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Ingest not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": ingest})
+}
+
+// resolvedIngestItem is a validated manifest item with its player resolved
+type resolvedIngestItem struct {
+	domain.HighlightIngestItem
+	fileSize    int64
+	contentType string
+	checksum    string
+}
+
+// resolveIngestItems validates the items and resolves jersey numbers through each
+// match's lineup. Problems are returned per item; the items are only usable if
+// there are none.
+func (m *Module) resolveIngestItems(items []IngestManifestItem, defaultMatch string) ([]resolvedIngestItem, []gin.H) {
+	var rowErrors []gin.H
+	lineups := map[uuid.UUID]map[int]uuid.UUID{}
+	lineup := func(matchID uuid.UUID) (map[int]uuid.UUID, bool) {
+		if jerseys, ok := lineups[matchID]; ok {
+			return jerseys, jerseys != nil
+		}
+		var match domain.Match
+		if err := m.DB.Select("id").First(&match, "id = ?", matchID).Error; err != nil {
+			lineups[matchID] = nil
+			return nil, false
+		}
+		var rows []domain.MatchPlayer
+		m.DB.Where("match_id = ? AND jersey_number IS NOT NULL", matchID).Find(&rows)
+		jerseys := map[int]uuid.UUID{}
+		for _, mp := range rows {
+			jerseys[*mp.JerseyNumber] = mp.PlayerID
+		}
+		lineups[matchID] = jerseys
+		return jerseys, true
+	}
+
+	var playerIDs []uuid.UUID
+	for _, item := range items {
+		if pid, err := uuid.Parse(item.PlayerID); err == nil {
+			playerIDs = append(playerIDs, pid)
+		}
+	}
+	known := map[uuid.UUID]bool{}
+	if len(playerIDs) > 0 {
+		var found []uuid.UUID
+		m.DB.Model(&domain.Player{}).Where("id IN ? AND deleted_at IS NULL", playerIDs).Pluck("id", &found)
+		for _, pid := range found {
+			known[pid] = true
+		}
+	}
+
+	names := map[string]int{}
+	resolved := make([]resolvedIngestItem, 0, len(items))
+	for _, item := range items {
+		var problems []string
+		out := resolvedIngestItem{
+			HighlightIngestItem: domain.HighlightIngestItem{
+				Row:           item.row,
+				FileName:      strings.TrimSpace(item.FileName),
+				HighlightType: strings.ToLower(strings.TrimSpace(item.HighlightType)),
+				Title:         stringPtr(strings.TrimSpace(item.Title)),
+				Description:   stringPtr(strings.TrimSpace(item.Description)),
+			},
+			fileSize: item.FileSize,
+			checksum: strings.TrimSpace(item.ChecksumSHA256),
+		}
+
+		if out.FileName == "" {
+			problems = append(problems, "file_name is required")
+		} else if first, ok := names[out.FileName]; ok {
+			problems = append(problems, fmt.Sprintf("file_name is also used by item %d", first))
+		} else {
+			names[out.FileName] = item.row
+		}
+		switch {
+		case item.FileSize <= 0:
+			problems = append(problems, "file_size is required")
+		case item.FileSize > 1<<30:
+			problems = append(problems, "file is too large (max 1GB for highlights)")
+		}
+		out.contentType = strings.ToLower(strings.TrimSpace(item.ContentType))
+		if out.contentType == "" {
+			out.contentType = highlightVideoTypes[strings.ToLower(path.Ext(out.FileName))]
+		}
+		valid := false
+		for _, t := range highlightVideoTypes {
+			valid = valid || t == out.contentType
+		}
+		if !valid {
+			problems = append(problems, "file must be a video (mp4, mov, avi, webm or mkv)")
+		}
+		if !domain.IsValidHighlightType(out.HighlightType) {
+			problems = append(problems, fmt.Sprintf("invalid highlight_type %q", item.HighlightType))
+		}
+
+		matchRef := strings.TrimSpace(item.MatchID)
+		if matchRef == "" {
+			matchRef = defaultMatch
+		}
+		var jerseys map[int]uuid.UUID
+		if matchRef != "" {
+			if mid, err := uuid.Parse(matchRef); err != nil {
+				problems = append(problems, "invalid match_id")
+			} else if j, ok := lineup(mid); !ok {
+				problems = append(problems, "match not found")
+			} else {
+				out.MatchID, jerseys = &mid, j
+			}
+		}
+
+		switch {
+		case strings.TrimSpace(item.PlayerID) != "":
+			pid, err := uuid.Parse(strings.TrimSpace(item.PlayerID))
+			if err != nil {
+				problems = append(problems, "invalid player_id")
+			} else if !known[pid] {
+				problems = append(problems, "player not found")
+			} else {
+				out.PlayerID = pid
+			}
+		case item.JerseyNumber != nil:
+			if matchRef == "" {
+				problems = append(problems, "jersey_number needs a match_id")
+			} else if jerseys != nil {
+				if pid, ok := jerseys[*item.JerseyNumber]; ok {
+					out.PlayerID = pid
+				} else {
+					problems = append(problems, fmt.Sprintf("no player wears #%d in this match", *item.JerseyNumber))
+				}
+			}
+		default:
+			problems = append(problems, "player_id or jersey_number is required")
+		}
+
+		if item.Timestamp != nil {
+			ts := strings.TrimSpace(fmt.Sprint(item.Timestamp))
+			if ts != "" {
+				if seconds, err := parseClock(ts); err != nil {
+					problems = append(problems, "invalid timestamp, use seconds, mm:ss or hh:mm:ss")
+				} else {
+					out.TimestampInMatch = &seconds
+				}
+			}
+		}
+
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, gin.H{"row": item.row, "file_name": item.FileName, "error": strings.Join(problems, "; ")})
+			continue
+		}
+		resolved = append(resolved, out)
+	}
+	return resolved, rowErrors
+}
+
+// parseIngestManifest reads a JSON or CSV manifest, with the match ID given at the
+// top level of a JSON manifest if any
+func parseIngestManifest(data []byte) ([]IngestManifestItem, string, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	trimmed := bytes.TrimSpace(data)
+	var items []IngestManifestItem
+	var matchID string
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, "", fmt.Errorf("invalid JSON manifest: %v", err)
+		}
+		for i := range items {
+			items[i].row = i + 1
+		}
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var manifest struct {
+			MatchID string               `json:"match_id"`
+			Items   []IngestManifestItem `json:"items"`
+		}
+		if err := json.Unmarshal(trimmed, &manifest); err != nil {
+			return nil, "", fmt.Errorf("invalid JSON manifest: %v", err)
+		}
+		items, matchID = manifest.Items, manifest.MatchID
+		for i := range items {
+			items[i].row = i + 1
+		}
+	default:
+		var err error
+		if items, err = parseIngestCSV(data); err != nil {
+			return nil, "", err
+		}
+	}
+	if len(items) == 0 {
+		return nil, "", errors.New("manifest has no items")
+	}
+	if len(items) > maxIngestItems {
+		return nil, "", fmt.Errorf("manifest has too many items (max %d)", maxIngestItems)
+	}
+	return items, matchID, nil
+}
+
+// parseIngestCSV reads manifest rows keyed by the header row
+func parseIngestCSV(data []byte) ([]IngestManifestItem, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("manifest is empty or unreadable")
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"file_name", "file_size", "highlight_type"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("manifest is missing the %s column", required)
+		}
+	}
+	_, hasPlayer := cols["player_id"]
+	_, hasJersey := cols["jersey_number"]
+	if !hasPlayer && !hasJersey {
+		return nil, errors.New("manifest needs a player_id or jersey_number column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var items []IngestManifestItem
+	line := 1 // the header
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		if len(items) >= maxIngestItems {
+			return nil, fmt.Errorf("manifest has too many items (max %d)", maxIngestItems)
+		}
+		item := IngestManifestItem{
+			FileName:       field(record, "file_name"),
+			ContentType:    field(record, "content_type"),
+			ChecksumSHA256: field(record, "checksum_sha256"),
+			PlayerID:       field(record, "player_id"),
+			MatchID:        field(record, "match_id"),
+			HighlightType:  field(record, "highlight_type"),
+			Title:          field(record, "title"),
+			Description:    field(record, "description"),
+			row:            line,
+		}
+		if item.FileName == "" && item.HighlightType == "" && item.PlayerID == "" {
+			continue // blank line
+		}
+		if size := field(record, "file_size"); size != "" {
+			n, err := strconv.ParseInt(size, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid file_size %q on row %d", size, line)
+			}
+			item.FileSize = n
+		}
+		if jersey := field(record, "jersey_number"); jersey != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(jersey, "#"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid jersey_number %q on row %d", jersey, line)
+			}
+			item.JerseyNumber = &n
+		}
+		if ts := field(record, "timestamp"); ts != "" {
+			item.Timestamp = ts
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseClock reads seconds, mm:ss or hh:mm:ss
+func parseClock(v string) (int, error) {
+	parts := strings.Split(v, ":")
+	if len(parts) > 3 {
+		return 0, errors.New("invalid time")
+	}
+	total := 0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, errors.New("invalid time")
+		}
+		total = total*60 + n
+	}
+	return total, nil
+}
+
+// audit records an admin action in the audit log
+func (m *Module) audit(c *gin.Context, action, resourceType string, resourceID *uuid.UUID, details gin.H) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	ip := c.ClientIP()
+	encoded, _ := json.Marshal(details)
+	detailStr := string(encoded)
+	m.DB.Create(&domain.AuditLog{
+		UserID:       &userID,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Details:      &detailStr,
+		IPAddress:    &ip,
+		CreatedAt:    time.Now(),
+	})
+}
+
 // ==================== PUBLIC ENDPOINTS ====================
 
 // GetPlayerHighlightsPublic returns highlights for a player (public, FREE)
@@ -592,6 +1329,7 @@ func (m *Module) GetPlayerHighlightsPublic(c *gin.Context) {
 	limit := 20
 
 	query := m.DB.Where("player_id = ? AND status = 'approved'", pid).
+		Where("player_id IN (?)", consent.PublishedPlayerIDs(m.DB, consent.VideoPublication)).
 		Preload("Match").
 		Limit(limit)
 
@@ -654,6 +1392,7 @@ func (m *Module) ListFeaturedHighlights(c *gin.Context) {
 
 	var highlights []domain.PlayerHighlight
 	err := m.DB.Where("status = 'approved'").
+		Where("player_id IN (?)", consent.PublishedPlayerIDs(m.DB, consent.VideoPublication)).
 		Preload("Player").
 		Preload("Player.Academy").
 		Preload("Match").
@@ -680,11 +1419,12 @@ func (m *Module) ListFeaturedHighlights(c *gin.Context) {
 		Player          *struct {
 			ID                 uuid.UUID `json:"id"`
 			FirstName          string    `json:"first_name"`
-			LastName           string    `json:"last_name"`
+			LastName           *string   `json:"last_name,omitempty"`
+			LastNameInit       string    `json:"last_name_init"`
 			ProfilePhotoURL    *string   `json:"profile_photo_url,omitempty"`
 			Position           string    `json:"position"`
 			Country            string    `json:"country"`
-			DateOfBirth        time.Time `json:"date_of_birth"`
+			DateOfBirth        *string   `json:"date_of_birth,omitempty"`
 			HeightCm           *int      `json:"height_cm,omitempty"`
 			PreferredFoot      *string   `json:"preferred_foot,omitempty"`
 			VerificationStatus string    `json:"verification_status"`
@@ -700,6 +1440,15 @@ func (m *Module) ListFeaturedHighlights(c *gin.Context) {
 		} `json:"match,omitempty"`
 	}
 
+	policy := privacy.For(c, m.DB)
+	players := make([]domain.Player, 0, len(highlights))
+	for _, h := range highlights {
+		if h.Player != nil {
+			players = append(players, *h.Player)
+		}
+	}
+	policy.Load(players)
+
 	response := make([]highlightResponse, 0, len(highlights))
 	for _, h := range highlights {
 		hr := highlightResponse{
@@ -713,15 +1462,17 @@ func (m *Module) ListFeaturedHighlights(c *gin.Context) {
 			ViewCount:       h.ViewCount,
 			CreatedAt:       h.CreatedAt,
 		}
-		if h.Player.ID != uuid.Nil {
+		if h.Player != nil {
+			masked := policy.Mask(h.Player)
 			playerInfo := &struct {
 				ID                 uuid.UUID `json:"id"`
 				FirstName          string    `json:"first_name"`
-				LastName           string    `json:"last_name"`
+				LastName           *string   `json:"last_name,omitempty"`
+				LastNameInit       string    `json:"last_name_init"`
 				ProfilePhotoURL    *string   `json:"profile_photo_url,omitempty"`
 				Position           string    `json:"position"`
 				Country            string    `json:"country"`
-				DateOfBirth        time.Time `json:"date_of_birth"`
+				DateOfBirth        *string   `json:"date_of_birth,omitempty"`
 				HeightCm           *int      `json:"height_cm,omitempty"`
 				PreferredFoot      *string   `json:"preferred_foot,omitempty"`
 				VerificationStatus string    `json:"verification_status"`
@@ -733,11 +1484,12 @@ func (m *Module) ListFeaturedHighlights(c *gin.Context) {
 			}{
 				ID:                 h.Player.ID,
 				FirstName:          h.Player.FirstName,
-				LastName:           h.Player.LastName,
+				LastName:           masked.LastName,
+				LastNameInit:       masked.LastNameInit,
 				ProfilePhotoURL:    m.getThumbnailURL(h.Player.ProfilePhotoURL),
 				Position:           h.Player.Position,
 				Country:            h.Player.Country,
-				DateOfBirth:        h.Player.DateOfBirth,
+				DateOfBirth:        masked.DateOfBirth,
 				HeightCm:           h.Player.HeightCm,
 				PreferredFoot:      h.Player.PreferredFoot,
 				VerificationStatus: h.Player.VerificationStatus,
@@ -774,7 +1526,8 @@ func (m *Module) ListFeaturedHighlights(c *gin.Context) {
 	})
 }
 
-// GetPlayerTournamentAppearances returns tournaments/matches a player participated in
+// GetPlayerTournamentAppearances returns the tournaments a player entered, with
+// the matches they played in each
 func (m *Module) GetPlayerTournamentAppearances(c *gin.Context) {
 	playerID := c.Param("id")
 	pid, err := uuid.Parse(playerID)
@@ -783,6 +1536,15 @@ func (m *Module) GetPlayerTournamentAppearances(c *gin.Context) {
 		return
 	}
 
+	// Tournaments the player is registered for
+	var entries []domain.TournamentRegistration
+	if err := m.DB.Preload("Tournament").
+		Where("player_id = ? AND status <> ?", pid, registrations.StatusWithdrawn).
+		Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch data"})
+		return
+	}
+
 	// Get all matches this player participated in
 	var matchPlayers []domain.MatchPlayer
 	if err := m.DB.Where("player_id = ?", pid).Find(&matchPlayers).Error; err != nil {
@@ -790,7 +1552,7 @@ func (m *Module) GetPlayerTournamentAppearances(c *gin.Context) {
 		return
 	}
 
-	if len(matchPlayers) == 0 {
+	if len(entries) == 0 && len(matchPlayers) == 0 {
 		c.JSON(http.StatusOK, gin.H{"success": true, "data": []interface{}{}})
 		return
 	}
@@ -803,11 +1565,13 @@ func (m *Module) GetPlayerTournamentAppearances(c *gin.Context) {
 
 	// Get matches with tournaments and videos
 	var matches []domain.Match
-	m.DB.Where("id IN ?", matchIDs).
-		Preload("Tournament").
-		Preload("Video").
-		Order("match_date DESC").
-		Find(&matches)
+	if len(matchIDs) > 0 {
+		m.DB.Where("id IN ?", matchIDs).
+			Preload("Tournament").
+			Preload("Video").
+			Order("match_date DESC").
+			Find(&matches)
+	}
 
 	// Get highlight counts per match
 	type matchHighlightCount struct {
@@ -815,11 +1579,13 @@ func (m *Module) GetPlayerTournamentAppearances(c *gin.Context) {
 		HighlightCount int
 	}
 	var highlightCounts []matchHighlightCount
-	m.DB.Model(&domain.PlayerHighlight{}).
-		Select("match_id, COUNT(*) as highlight_count").
-		Where("player_id = ? AND match_id IN ?", pid, matchIDs).
-		Group("match_id").
-		Scan(&highlightCounts)
+	if len(matchIDs) > 0 {
+		m.DB.Model(&domain.PlayerHighlight{}).
+			Select("match_id, COUNT(*) as highlight_count").
+			Where("player_id = ? AND match_id IN ?", pid, matchIDs).
+			Group("match_id").
+			Scan(&highlightCounts)
+	}
 
 	highlightMap := make(map[uuid.UUID]int)
 	for _, hc := range highlightCounts {
@@ -850,6 +1616,9 @@ func (m *Module) GetPlayerTournamentAppearances(c *gin.Context) {
 		TournamentID    uuid.UUID           `json:"tournament_id"`
 		TournamentName  string              `json:"tournament_name"`
 		Year            int                 `json:"year"`
+		Squad           *string             `json:"squad,omitempty"`
+		ShirtNumber     *int                `json:"shirt_number,omitempty"`
+		Status          string              `json:"status"`
 		TotalMatches    int                 `json:"total_matches"`
 		TotalGoals      int                 `json:"total_goals"`
 		TotalAssists    int                 `json:"total_assists"`
@@ -858,8 +1627,23 @@ func (m *Module) GetPlayerTournamentAppearances(c *gin.Context) {
 	}
 
 	tournamentMap := make(map[uuid.UUID]*tournamentAppearance)
+	for _, e := range entries {
+		if e.Tournament == nil {
+			continue
+		}
+		tournamentMap[e.TournamentID] = &tournamentAppearance{
+			TournamentID:   e.TournamentID,
+			TournamentName: e.Tournament.Name,
+			Year:           e.Tournament.Year,
+			Squad:          e.Squad,
+			ShirtNumber:    e.ShirtNumber,
+			Status:         e.Status,
+			Matches:        []matchInTournament{},
+		}
+	}
+
 	for _, match := range matches {
-		if match.TournamentID == nil {
+		if match.TournamentID == nil || match.Tournament == nil {
 			continue
 		}
 
@@ -869,6 +1653,7 @@ func (m *Module) GetPlayerTournamentAppearances(c *gin.Context) {
 				TournamentID:   tid,
 				TournamentName: match.Tournament.Name,
 				Year:           match.Tournament.Year,
+				Status:         registrations.StatusParticipated,
 				Matches:        []matchInTournament{},
 			}
 		}
@@ -894,11 +1679,17 @@ func (m *Module) GetPlayerTournamentAppearances(c *gin.Context) {
 		tournamentMap[tid].TotalHighlights += highlightMap[match.ID]
 	}
 
-	// Convert to slice
+	// Convert to slice, latest tournament first
 	response := make([]*tournamentAppearance, 0, len(tournamentMap))
 	for _, ta := range tournamentMap {
 		response = append(response, ta)
 	}
+	sort.Slice(response, func(i, j int) bool {
+		if response[i].Year != response[j].Year {
+			return response[i].Year > response[j].Year
+		}
+		return response[i].TournamentName < response[j].TournamentName
+	})
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -938,20 +1729,18 @@ func (m *Module) GetHighlightTypes(c *gin.Context) {
 
 // ==================== HELPERS ====================
 
+// getStreamURL returns a playable URL for a highlight. Highlights are free, so the
+// CDN URL is left unsigned.
 func (m *Module) getStreamURL(s3Key string) string {
-	if m.CDNHost != "" {
-		return fmt.Sprintf("%s/%s", m.CDNHost, s3Key)
-	}
-	// Generate presigned URL for direct S3 access
-	presigner := s3.NewPresignClient(m.S3Client)
-	result, err := presigner.PresignGetObject(context.Background(), &s3.GetObjectInput{
-		Bucket: aws.String(m.S3Bucket),
-		Key:    aws.String(s3Key),
-	}, s3.WithPresignExpires(1*time.Hour))
+	if m.CDN.Enabled() {
+		return m.CDN.URL(s3Key)
+	}
+	// Generate presigned URL for direct storage access
+	url, err := m.Store.PresignGet(context.Background(), s3Key, 1*time.Hour)
 	if err != nil {
 		return ""
 	}
-	return result.URL
+	return url
 }
 
 // getThumbnailURL converts s3://bucket/key format to a presigned URL
@@ -961,27 +1750,22 @@ func (m *Module) getThumbnailURL(thumbnailURL *string) *string {
 	}
 
 	// Extract S3 key from s3://bucket/key format
-	s3Prefix := fmt.Sprintf("s3://%s/", m.S3Bucket)
 	s3Key := *thumbnailURL
-	if len(s3Key) > len(s3Prefix) && s3Key[:len(s3Prefix)] == s3Prefix {
-		s3Key = s3Key[len(s3Prefix):]
+	if key, ok := storage.KeyFromURL(s3Key); ok {
+		s3Key = key
 	}
 
-	if m.CDNHost != "" {
-		url := fmt.Sprintf("%s/%s", m.CDNHost, s3Key)
+	if m.CDN.Enabled() {
+		url := m.CDN.URL(s3Key)
 		return &url
 	}
 
 	// Generate presigned URL
-	presigner := s3.NewPresignClient(m.S3Client)
-	result, err := presigner.PresignGetObject(context.Background(), &s3.GetObjectInput{
-		Bucket: aws.String(m.S3Bucket),
-		Key:    aws.String(s3Key),
-	}, s3.WithPresignExpires(1*time.Hour))
+	url, err := m.Store.PresignGet(context.Background(), s3Key, 1*time.Hour)
 	if err != nil {
 		return nil
 	}
-	return &result.URL
+	return &url
 }
 
 func stringPtr(s string) *string {
@@ -2,17 +2,31 @@ package matches
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/unicorn-sport/backend/internal/access"
+	"github.com/unicorn-sport/backend/internal/cdn"
 	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/fixtures"
+	"github.com/unicorn-sport/backend/internal/hls"
+	"github.com/unicorn-sport/backend/internal/privacy"
+	"github.com/unicorn-sport/backend/internal/registrations"
+	"github.com/unicorn-sport/backend/internal/standings"
+	"github.com/unicorn-sport/backend/internal/storage"
+	"github.com/unicorn-sport/backend/internal/uploads"
+	"github.com/unicorn-sport/backend/internal/views"
+	"github.com/unicorn-sport/backend/internal/watermark"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -20,49 +34,26 @@ import (
 
 // Module holds dependencies for the matches module
 type Module struct {
-	DB       *gorm.DB
-	S3Client *s3.Client
-	S3Bucket string
-	CDNHost  string // CloudFront or S3 URL for serving
+	DB         *gorm.DB
+	Store      storage.Store
+	Uploads    *uploads.Service
+	Watermarks *watermark.Service
+	CDN        *cdn.CloudFront // nil when no CDN is configured
+	Views      *views.Tracker
 }
 
 // NewModule creates a new matches module
-func NewModule(db *gorm.DB, s3Client *s3.Client, bucket, cdnHost string) *Module {
+func NewModule(db *gorm.DB, store storage.Store, uploadService *uploads.Service, watermarkService *watermark.Service, cloudFront *cdn.CloudFront, viewTracker *views.Tracker) *Module {
 	return &Module{
-		DB:       db,
-		S3Client: s3Client,
-		S3Bucket: bucket,
-		CDNHost:  cdnHost,
+		DB:         db,
+		Store:      store,
+		Uploads:    uploadService,
+		Watermarks: watermarkService,
+		CDN:        cloudFront,
+		Views:      viewTracker,
 	}
 }
 
-// getPresignedURL converts an s3:// URL to a presigned URL
-func (m *Module) getPresignedURL(s3URL string) string {
-	if s3URL == "" || !strings.HasPrefix(s3URL, "s3://") {
-		return s3URL
-	}
-
-	// Parse s3://bucket/key format
-	withoutPrefix := strings.TrimPrefix(s3URL, "s3://")
-	parts := strings.SplitN(withoutPrefix, "/", 2)
-	if len(parts) != 2 {
-		return s3URL
-	}
-
-	key := parts[1]
-
-	presignClient := s3.NewPresignClient(m.S3Client)
-	presignedReq, err := presignClient.PresignGetObject(context.Background(), &s3.GetObjectInput{
-		Bucket: aws.String(m.S3Bucket),
-		Key:    aws.String(key),
-	}, s3.WithPresignExpires(time.Hour))
-	if err != nil {
-		return s3URL
-	}
-
-	return presignedReq.URL
-}
-
 // ==================== MATCH CRUD ====================
 
 // CreateMatchRequest is the request body for creating a match
@@ -73,12 +64,15 @@ type CreateMatchRequest struct {
 	MatchDate    string `json:"match_date" binding:"required"` // ISO date
 	Location     string `json:"location"`
 	Stage        string `json:"stage"` // group, quarterfinal, semifinal, final
-	HomeTeam     string `json:"home_team"`
+	HomeTeamID   string `json:"home_team_id"`
+	AwayTeamID   string `json:"away_team_id"`
+	HomeTeam     string `json:"home_team"` // a team name, used when no ID is given
 	AwayTeam     string `json:"away_team"`
 	MatchNumber  int    `json:"match_number"`
 }
 
-// CreateMatch creates a new match in a tournament
+// CreateMatch creates a new match in a tournament. Sides given by name are found
+// among the tournament's teams, or entered as new teams.
 func (m *Module) CreateMatch(c *gin.Context) {
 	var req CreateMatchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -105,6 +99,19 @@ func (m *Module) CreateMatch(c *gin.Context) {
 		return
 	}
 
+	home, ok := m.matchTeam(c, tournamentID, "home", req.HomeTeamID, req.HomeTeam)
+	if !ok {
+		return
+	}
+	away, ok := m.matchTeam(c, tournamentID, "away", req.AwayTeamID, req.AwayTeam)
+	if !ok {
+		return
+	}
+	if home != nil && away != nil && home.ID == away.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "A team can't play itself"})
+		return
+	}
+
 	userID, _ := c.Get("user_id")
 
 	match := domain.Match{
@@ -114,12 +121,16 @@ func (m *Module) CreateMatch(c *gin.Context) {
 		MatchDate:    matchDate,
 		Location:     stringPtr(req.Location),
 		Stage:        stringPtr(req.Stage),
-		HomeTeam:     stringPtr(req.HomeTeam),
-		AwayTeam:     stringPtr(req.AwayTeam),
 		MatchNumber:  intPtr(req.MatchNumber),
 		Status:       "scheduled",
 		CreatedBy:    userID.(uuid.UUID),
 	}
+	if home != nil {
+		match.HomeTeamID, match.HomeTeam = &home.ID, &home.Name
+	}
+	if away != nil {
+		match.AwayTeamID, match.AwayTeam = &away.ID, &away.Name
+	}
 
 	if err := m.DB.Create(&match).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to create match"})
@@ -133,7 +144,7 @@ func (m *Module) CreateMatch(c *gin.Context) {
 	})
 }
 
-// ListMatches lists matches in a tournament
+// ListMatches lists matches in a tournament, optionally only a team's (team_id)
 func (m *Module) ListMatches(c *gin.Context) {
 	tournamentID := c.Param("tournamentId")
 	if tournamentID == "" {
@@ -151,6 +162,14 @@ func (m *Module) ListMatches(c *gin.Context) {
 	query := m.DB.Where("tournament_id = ?", tid).
 		Preload("Video").
 		Order("match_date ASC, match_number ASC")
+	if teamID := c.Query("team_id"); teamID != "" {
+		teamUUID, err := uuid.Parse(teamID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid team ID"})
+			return
+		}
+		query = query.Where("home_team_id = ? OR away_team_id = ?", teamUUID, teamUUID)
+	}
 
 	if err := query.Find(&matches).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch matches"})
@@ -236,7 +255,7 @@ func (m *Module) GetMatch(c *gin.Context) {
 		Player domain.Player `gorm:"embedded;embeddedPrefix:player_"`
 	}
 	m.DB.Model(&domain.MatchPlayer{}).
-		Select("match_players.*, players.id as player_id, players.first_name as player_first_name, players.last_name as player_last_name, players.position as player_position, players.profile_photo_url as player_profile_photo_url").
+		Select("match_players.*, players.id as player_id, players.first_name as player_first_name, players.last_name as player_last_name, players.date_of_birth as player_date_of_birth, players.user_id as player_user_id, players.position as player_position, players.profile_photo_url as player_profile_photo_url").
 		Joins("JOIN players ON players.id = match_players.player_id").
 		Where("match_players.match_id = ?", mid).
 		Scan(&matchPlayers)
@@ -263,7 +282,8 @@ func (m *Module) GetMatch(c *gin.Context) {
 		ID              uuid.UUID  `json:"id"`
 		PlayerID        uuid.UUID  `json:"player_id"`
 		FirstName       string     `json:"first_name"`
-		LastName        string     `json:"last_name"`
+		LastName        *string    `json:"last_name,omitempty"`
+		LastNameInit    string     `json:"last_name_init"`
 		Position        string     `json:"position"`
 		ProfilePhotoURL *string    `json:"profile_photo_url"`
 		PositionPlayed  *string    `json:"position_played"`
@@ -280,20 +300,23 @@ func (m *Module) GetMatch(c *gin.Context) {
 		SubbedOutAt     *int       `json:"subbed_out_at"`
 	}
 
+	policy := privacy.For(c, m.DB)
 	players := make([]playerInMatch, len(matchPlayers))
 	for i, mp := range matchPlayers {
 		// Convert profile photo URL to presigned URL
 		var profilePhotoURL *string
 		if mp.Player.ProfilePhotoURL != nil && *mp.Player.ProfilePhotoURL != "" {
-			url := m.getPresignedURL(*mp.Player.ProfilePhotoURL)
+			url := storage.SignedURL(m.Store, *mp.Player.ProfilePhotoURL)
 			profilePhotoURL = &url
 		}
 
+		masked := policy.Mask(&mp.Player)
 		players[i] = playerInMatch{
 			ID:              mp.ID,
 			PlayerID:        mp.PlayerID,
 			FirstName:       mp.Player.FirstName,
-			LastName:        mp.Player.LastName,
+			LastName:        masked.LastName,
+			LastNameInit:    masked.LastNameInit,
 			Position:        mp.Player.Position,
 			ProfilePhotoURL: profilePhotoURL,
 			PositionPlayed:  mp.PositionPlayed,
@@ -331,31 +354,19 @@ func (m *Module) GetMatch(c *gin.Context) {
 
 	// Add video with playable URL if exists
 	if match.Video != nil {
-		var videoURL string
-		// Generate presigned URL for S3 (valid for 1 hour)
-		if m.CDNHost != "" {
-			// Use CloudFront if configured
-			videoURL = fmt.Sprintf("%s/%s", m.CDNHost, match.Video.VideoURL)
-		} else {
-			// Generate presigned S3 URL
-			presigner := s3.NewPresignClient(m.S3Client)
-			presignedReq, err := presigner.PresignGetObject(c.Request.Context(), &s3.GetObjectInput{
-				Bucket: aws.String(m.S3Bucket),
-				Key:    aws.String(match.Video.VideoURL),
-			}, s3.WithPresignExpires(1*time.Hour))
-			if err != nil {
-				fmt.Printf("Warning: Failed to generate presigned URL: %v\n", err)
-				videoURL = "" // Will show error in frontend
-			} else {
-				videoURL = presignedReq.URL
-			}
+		videoURL, _ := m.paidVideoURL(c, match.Video.VideoURL, 1*time.Hour)
+
+		var thumbnailURL *string
+		if match.Video.ThumbnailURL != nil {
+			url := storage.SignedURL(m.Store, *match.Video.ThumbnailURL)
+			thumbnailURL = &url
 		}
 
 		matchResponse["video"] = gin.H{
 			"id":               match.Video.ID,
 			"match_id":         match.Video.MatchID,
 			"video_url":        videoURL,
-			"thumbnail_url":    match.Video.ThumbnailURL,
+			"thumbnail_url":    thumbnailURL,
 			"duration_seconds": match.Video.DurationSeconds,
 			"file_size_bytes":  match.Video.FileSizeBytes,
 			"status":           match.Video.Status,
@@ -392,16 +403,20 @@ func (m *Module) UpdateMatch(c *gin.Context) {
 	}
 
 	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		MatchDate   string `json:"match_date"`
-		Location    string `json:"location"`
-		Stage       string `json:"stage"`
-		HomeTeam    string `json:"home_team"`
-		AwayTeam    string `json:"away_team"`
-		HomeScore   *int   `json:"home_score"`
-		AwayScore   *int   `json:"away_score"`
-		Status      string `json:"status"`
+		Title         string `json:"title"`
+		Description   string `json:"description"`
+		MatchDate     string `json:"match_date"`
+		Location      string `json:"location"`
+		Stage         string `json:"stage"`
+		HomeTeamID    string `json:"home_team_id"`
+		AwayTeamID    string `json:"away_team_id"`
+		HomeTeam      string `json:"home_team"` // a team name, used when no ID is given
+		AwayTeam      string `json:"away_team"`
+		HomeScore     *int   `json:"home_score"`
+		AwayScore     *int   `json:"away_score"`
+		HomePenalties *int   `json:"home_penalties"`
+		AwayPenalties *int   `json:"away_penalties"`
+		Status        string `json:"status"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -427,11 +442,35 @@ func (m *Module) UpdateMatch(c *gin.Context) {
 	if req.Stage != "" {
 		updates["stage"] = req.Stage
 	}
-	if req.HomeTeam != "" {
-		updates["home_team"] = req.HomeTeam
-	}
-	if req.AwayTeam != "" {
-		updates["away_team"] = req.AwayTeam
+	if match.TournamentID == nil {
+		if req.HomeTeam != "" {
+			updates["home_team"] = req.HomeTeam
+		}
+		if req.AwayTeam != "" {
+			updates["away_team"] = req.AwayTeam
+		}
+	} else {
+		homeID, awayID := match.HomeTeamID, match.AwayTeamID
+		if req.HomeTeamID != "" || req.HomeTeam != "" {
+			home, ok := m.matchTeam(c, *match.TournamentID, "home", req.HomeTeamID, req.HomeTeam)
+			if !ok {
+				return
+			}
+			homeID = &home.ID
+			updates["home_team_id"], updates["home_team"] = home.ID, home.Name
+		}
+		if req.AwayTeamID != "" || req.AwayTeam != "" {
+			away, ok := m.matchTeam(c, *match.TournamentID, "away", req.AwayTeamID, req.AwayTeam)
+			if !ok {
+				return
+			}
+			awayID = &away.ID
+			updates["away_team_id"], updates["away_team"] = away.ID, away.Name
+		}
+		if homeID != nil && awayID != nil && *homeID == *awayID {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "A team can't play itself"})
+			return
+		}
 	}
 	if req.HomeScore != nil {
 		updates["home_score"] = *req.HomeScore
@@ -439,14 +478,48 @@ func (m *Module) UpdateMatch(c *gin.Context) {
 	if req.AwayScore != nil {
 		updates["away_score"] = *req.AwayScore
 	}
+	if req.HomePenalties != nil {
+		updates["home_penalties"] = *req.HomePenalties
+	}
+	if req.AwayPenalties != nil {
+		updates["away_penalties"] = *req.AwayPenalties
+	}
 	if req.Status != "" {
 		updates["status"] = req.Status
 	}
 
-	if err := m.DB.Model(&match).Updates(updates).Error; err != nil {
+	// Players move with their side when a team is replaced, and a knockout result
+	// moves its winner on, in the same transaction
+	oldSides := map[string]*uuid.UUID{"home_team_id": match.HomeTeamID, "away_team_id": match.AwayTeamID}
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&match).Updates(updates).Error; err != nil {
+			return err
+		}
+		for column, old := range oldSides {
+			if newID, ok := updates[column]; ok && old != nil && newID != *old {
+				if err := tx.Model(&domain.MatchPlayer{}).Where("match_id = ? AND team_id = ?", mid, *old).
+					Update("team_id", newID).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.First(&match, "id = ?", mid).Error; err != nil {
+			return err
+		}
+		return fixtures.Advance(tx, &match)
+	})
+	switch {
+	case errors.Is(err, fixtures.ErrNoWinner):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	case errors.Is(err, fixtures.ErrNextMatchStarted):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": err.Error()})
+		return
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update match"})
 		return
 	}
+	standings.Refresh(m.DB, match.TournamentID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -464,10 +537,14 @@ func (m *Module) DeleteMatch(c *gin.Context) {
 		return
 	}
 
+	var match domain.Match
+	m.DB.Select("tournament_id").First(&match, "id = ?", mid)
+
 	if err := m.DB.Delete(&domain.Match{}, "id = ?", mid).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to delete match"})
 		return
 	}
+	standings.Refresh(m.DB, match.TournamentID)
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Match deleted successfully"})
 }
@@ -485,6 +562,7 @@ func (m *Module) AddPlayerToMatch(c *gin.Context) {
 
 	var req struct {
 		PlayerID       string   `json:"player_id" binding:"required"`
+		TeamID         string   `json:"team_id"` // defaults to the side from the player's academy
 		PositionPlayed string   `json:"position_played"`
 		MinutesPlayed  *int     `json:"minutes_played"`
 		Goals          int      `json:"goals"`
@@ -521,6 +599,14 @@ func (m *Module) AddPlayerToMatch(c *gin.Context) {
 		return
 	}
 
+	teamID, ok := m.matchSide(c, &match, req.TeamID)
+	if !ok {
+		return
+	}
+	if teamID == nil {
+		teamID = m.academySide(&match, player.AcademyID)
+	}
+
 	// Default isStarter to true if not provided
 	isStarter := true
 	if req.IsStarter != nil {
@@ -536,6 +622,7 @@ func (m *Module) AddPlayerToMatch(c *gin.Context) {
 	matchPlayer := domain.MatchPlayer{
 		MatchID:        mid,
 		PlayerID:       playerID,
+		TeamID:         teamID,
 		PositionPlayed: stringPtr(positionPlayed),
 		MinutesPlayed:  req.MinutesPlayed,
 		Goals:          req.Goals,
@@ -552,6 +639,13 @@ func (m *Module) AddPlayerToMatch(c *gin.Context) {
 		return
 	}
 
+	// Playing in a tournament match registers the player for the tournament
+	if match.TournamentID != nil {
+		adminID := c.MustGet("user_id").(uuid.UUID)
+		registrations.RecordAppearance(m.DB, *match.TournamentID, playerID, req.JerseyNumber, &adminID)
+	}
+	standings.Refresh(m.DB, match.TournamentID)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"message": "Player added to match",
@@ -583,6 +677,7 @@ func (m *Module) UpdateMatchPlayer(c *gin.Context) {
 	}
 
 	var req struct {
+		TeamID         *string  `json:"team_id"` // empty clears it
 		PositionPlayed string   `json:"position_played"`
 		MinutesPlayed  *int     `json:"minutes_played"`
 		Goals          *int     `json:"goals"`
@@ -602,6 +697,18 @@ func (m *Module) UpdateMatchPlayer(c *gin.Context) {
 	}
 
 	updates := make(map[string]interface{})
+	if req.TeamID != nil {
+		var match domain.Match
+		if err := m.DB.First(&match, "id = ?", mid).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Match not found"})
+			return
+		}
+		teamID, ok := m.matchSide(c, &match, *req.TeamID)
+		if !ok {
+			return
+		}
+		updates["team_id"] = teamID
+	}
 	if req.PositionPlayed != "" {
 		updates["position_played"] = req.PositionPlayed
 	}
@@ -640,6 +747,7 @@ func (m *Module) UpdateMatchPlayer(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update"})
 		return
 	}
+	m.refreshStats(mid)
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Player updated"})
 }
@@ -665,6 +773,7 @@ func (m *Module) RemovePlayerFromMatch(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to remove player"})
 		return
 	}
+	m.refreshStats(mid)
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Player removed from match"})
 }
@@ -695,9 +804,10 @@ func (m *Module) InitMatchVideoUpload(c *gin.Context) {
 	}
 
 	var req struct {
-		FileName    string `json:"file_name" binding:"required"`
-		FileSize    int64  `json:"file_size" binding:"required"`
-		ContentType string `json:"content_type" binding:"required"`
+		FileName       string `json:"file_name" binding:"required"`
+		FileSize       int64  `json:"file_size" binding:"required"`
+		ContentType    string `json:"content_type" binding:"required"`
+		ChecksumSHA256 string `json:"checksum_sha256"` // optional, verified after upload
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -723,11 +833,7 @@ func (m *Module) InitMatchVideoUpload(c *gin.Context) {
 
 	if useMultipart {
 		// Create multipart upload
-		createResp, err := m.S3Client.CreateMultipartUpload(c.Request.Context(), &s3.CreateMultipartUploadInput{
-			Bucket:      aws.String(m.S3Bucket),
-			Key:         aws.String(s3Key),
-			ContentType: aws.String(req.ContentType),
-		})
+		uploadID, err := m.Store.CreateMultipartUpload(c.Request.Context(), s3Key, req.ContentType)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to initiate upload"})
 			return
@@ -739,13 +845,15 @@ func (m *Module) InitMatchVideoUpload(c *gin.Context) {
 			ContentType: req.ContentType,
 			FileName:    req.FileName,
 			FileSize:    req.FileSize,
-			S3UploadID:  createResp.UploadId,
+			S3UploadID:  &uploadID,
 			S3Key:       s3Key,
 			Status:      "pending",
 			EntityType:  stringPtr("match"),
 			EntityID:    &mid,
 			UploadedBy:  userID.(uuid.UUID),
 			ExpiresAt:   time.Now().Add(24 * time.Hour),
+
+			ChecksumSHA256: stringPtr(req.ChecksumSHA256),
 		}
 
 		if err := m.DB.Create(&session).Error; err != nil {
@@ -758,20 +866,14 @@ func (m *Module) InitMatchVideoUpload(c *gin.Context) {
 			"data": gin.H{
 				"session_id":    session.ID,
 				"upload_method": "multipart",
-				"s3_upload_id":  *createResp.UploadId,
+				"s3_upload_id":  uploadID,
 				"s3_key":        s3Key,
 				"part_size":     10 * 1024 * 1024, // 10MB parts
 			},
 		})
 	} else {
 		// Single presigned URL
-		presigner := s3.NewPresignClient(m.S3Client)
-		presignResult, err := presigner.PresignPutObject(c.Request.Context(), &s3.PutObjectInput{
-			Bucket:      aws.String(m.S3Bucket),
-			Key:         aws.String(s3Key),
-			ContentType: aws.String(req.ContentType),
-		}, s3.WithPresignExpires(1*time.Hour))
-
+		uploadURL, err := m.Store.PresignPut(c.Request.Context(), s3Key, req.ContentType, req.FileSize, 1*time.Hour)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to generate upload URL"})
 			return
@@ -789,6 +891,8 @@ func (m *Module) InitMatchVideoUpload(c *gin.Context) {
 			EntityID:    &mid,
 			UploadedBy:  userID.(uuid.UUID),
 			ExpiresAt:   time.Now().Add(1 * time.Hour),
+
+			ChecksumSHA256: stringPtr(req.ChecksumSHA256),
 		}
 
 		if err := m.DB.Create(&session).Error; err != nil {
@@ -801,7 +905,7 @@ func (m *Module) InitMatchVideoUpload(c *gin.Context) {
 			"data": gin.H{
 				"session_id":    session.ID,
 				"upload_method": "direct",
-				"upload_url":    presignResult.URL,
+				"upload_url":    uploadURL,
 				"s3_key":        s3Key,
 				"expires_in":    3600,
 			},
@@ -838,14 +942,12 @@ func (m *Module) GetMultipartPartURL(c *gin.Context) {
 		return
 	}
 
-	presigner := s3.NewPresignClient(m.S3Client)
-	presignResult, err := presigner.PresignUploadPart(c.Request.Context(), &s3.UploadPartInput{
-		Bucket:     aws.String(m.S3Bucket),
-		Key:        aws.String(session.S3Key),
-		UploadId:   session.S3UploadID,
-		PartNumber: aws.Int32(int32(req.PartNumber)),
-	}, s3.WithPresignExpires(1*time.Hour))
+	if session.Status != "pending" && session.Status != "uploading" {
+		c.JSON(http.StatusGone, gin.H{"success": false, "message": "Upload session is no longer active"})
+		return
+	}
 
+	uploadURL, err := m.Store.PresignUploadPart(c.Request.Context(), session.S3Key, *session.S3UploadID, req.PartNumber, 1*time.Hour)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to generate part URL"})
 		return
@@ -854,7 +956,7 @@ func (m *Module) GetMultipartPartURL(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"upload_url":  presignResult.URL,
+			"upload_url":  uploadURL,
 			"part_number": req.PartNumber,
 			"expires_in":  3600,
 		},
@@ -866,8 +968,9 @@ func (m *Module) CompleteMultipartUpload(c *gin.Context) {
 	var req struct {
 		SessionID string `json:"session_id" binding:"required"`
 		Parts     []struct {
-			PartNumber int    `json:"part_number"`
-			ETag       string `json:"etag"`
+			PartNumber     int    `json:"part_number"`
+			ETag           string `json:"etag"`
+			ChecksumSHA256 string `json:"checksum_sha256"` // optional per-part digest
 		} `json:"parts" binding:"required"`
 	}
 
@@ -894,40 +997,53 @@ func (m *Module) CompleteMultipartUpload(c *gin.Context) {
 	}
 
 	// Build completed parts
-	var completedParts []types.CompletedPart
+	var completedParts []storage.CompletedPart
+	digests := map[int]string{}
 	for _, p := range req.Parts {
-		completedParts = append(completedParts, types.CompletedPart{
-			PartNumber: aws.Int32(int32(p.PartNumber)),
-			ETag:       aws.String(p.ETag),
+		completedParts = append(completedParts, storage.CompletedPart{
+			PartNumber: p.PartNumber,
+			ETag:       p.ETag,
 		})
+		if p.ChecksumSHA256 != "" {
+			digests[p.PartNumber] = p.ChecksumSHA256
+		}
 	}
 
-	// Complete multipart upload
-	_, err = m.S3Client.CompleteMultipartUpload(c.Request.Context(), &s3.CompleteMultipartUploadInput{
-		Bucket:   aws.String(m.S3Bucket),
-		Key:      aws.String(session.S3Key),
-		UploadId: session.S3UploadID,
-		MultipartUpload: &types.CompletedMultipartUpload{
-			Parts: completedParts,
-		},
-	})
+	// Part sizes are only listable before completion
+	partChecksums, err := m.Uploads.PartChecksums(c.Request.Context(), &session, completedParts, digests)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to read uploaded parts"})
+		return
+	}
 
+	// Complete multipart upload
+	err = m.Store.CompleteMultipartUpload(c.Request.Context(), session.S3Key, *session.S3UploadID, completedParts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to complete upload"})
 		return
 	}
 
-	// Update session
+	// Record assembly before verifying so a verification retry doesn't need the parts
 	now := time.Now()
-	session.Status = "completed"
 	session.CompletedAt = &now
 	m.DB.Save(&session)
 
+	if err := m.Uploads.Verify(c.Request.Context(), &session, partChecksums); err != nil {
+		var mismatch *storage.MismatchError
+		if errors.As(err, &mismatch) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "message": "Upload verification failed: " + mismatch.Reason})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to verify upload"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Upload completed",
 		"data": gin.H{
-			"s3_key": session.S3Key,
+			"s3_key":                session.S3Key,
+			"detected_content_type": session.DetectedContentType,
 		},
 	})
 }
@@ -968,6 +1084,21 @@ func (m *Module) SaveMatchVideo(c *gin.Context) {
 		return
 	}
 
+	// Make sure the uploaded file passed integrity checks (verifies direct uploads on first use)
+	session, err := m.Uploads.VerifyByKey(c.Request.Context(), req.S3Key)
+	if err != nil {
+		var mismatch *storage.MismatchError
+		if errors.As(err, &mismatch) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "message": "Upload verification failed: " + mismatch.Reason})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to verify upload"})
+		return
+	}
+	if session != nil && req.FileSizeBytes == 0 {
+		req.FileSizeBytes = session.FileSize
+	}
+
 	userID, _ := c.Get("user_id")
 
 	priceCents := req.PriceCents
@@ -1014,12 +1145,8 @@ func (m *Module) DeleteMatchVideo(c *gin.Context) {
 		return
 	}
 
-	// Delete from S3
-	_, err = m.S3Client.DeleteObject(c.Request.Context(), &s3.DeleteObjectInput{
-		Bucket: aws.String(m.S3Bucket),
-		Key:    aws.String(video.VideoURL),
-	})
-	if err != nil {
+	// Delete from storage
+	if err := m.Store.Delete(c.Request.Context(), video.VideoURL); err != nil {
 		// Log but continue - file might already be deleted
 		fmt.Printf("Warning: Failed to delete S3 object: %v\n", err)
 	}
@@ -1063,12 +1190,7 @@ func (m *Module) InitThumbnailUpload(c *gin.Context) {
 	s3Key := fmt.Sprintf("thumbnails/matches/%s/%s", matchID, req.FileName)
 
 	// Generate presigned PUT URL
-	presigner := s3.NewPresignClient(m.S3Client)
-	presignedReq, err := presigner.PresignPutObject(c.Request.Context(), &s3.PutObjectInput{
-		Bucket:      aws.String(m.S3Bucket),
-		Key:         aws.String(s3Key),
-		ContentType: aws.String(req.ContentType),
-	}, s3.WithPresignExpires(15*time.Minute))
+	uploadURL, err := m.Store.PresignPut(c.Request.Context(), s3Key, req.ContentType, 0, 15*time.Minute)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to generate upload URL"})
 		return
@@ -1077,7 +1199,7 @@ func (m *Module) InitThumbnailUpload(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"upload_url": presignedReq.URL,
+			"upload_url": uploadURL,
 			"s3_key":     s3Key,
 		},
 	})
@@ -1110,10 +1232,10 @@ func (m *Module) UpdateThumbnail(c *gin.Context) {
 
 	// Generate thumbnail URL
 	var thumbnailURL string
-	if m.CDNHost != "" {
-		thumbnailURL = fmt.Sprintf("%s/%s", m.CDNHost, req.S3Key)
+	if m.CDN.Enabled() {
+		thumbnailURL = m.CDN.URL(req.S3Key)
 	} else {
-		thumbnailURL = fmt.Sprintf("https://%s.s3.amazonaws.com/%s", m.S3Bucket, req.S3Key)
+		thumbnailURL = storage.ObjectURL(m.Store, req.S3Key)
 	}
 
 	// Update video record
@@ -1127,12 +1249,2157 @@ func (m *Module) UpdateThumbnail(c *gin.Context) {
 		"success": true,
 		"message": "Thumbnail updated",
 		"data": gin.H{
-			"thumbnail_url": thumbnailURL,
+			"thumbnail_url": storage.SignedURL(m.Store, thumbnailURL),
 		},
 	})
 }
 
-// ==================== HELPERS ====================
+// ==================== SCOUT PLAYBACK ====================
+
+// StreamMatch starts a watermarked stream of a full match. Scouts need a plan with
+// full-match access or a completed purchase of this match. Every call opens a new
+// playback session whose watermark code is overlaid by the player; when the video
+// has A/B renditions the stream is also forensically watermarked per session.
+func (m *Module) StreamMatch(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	var video domain.MatchVideo
+	if err := m.DB.First(&video, "match_id = ? AND status = ?", mid, "ready").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uid := userID.(uuid.UUID)
+
+	hasAccess, purchase := m.hasFullAccess(c, uid, video.ID)
+	if !hasAccess {
+		c.JSON(http.StatusPaymentRequired, gin.H{
+			"success":     false,
+			"message":     "Subscribe or purchase this match to watch it",
+			"price_cents": video.PriceCents,
+			"currency":    video.Currency,
+			"preview_url": fmt.Sprintf("/api/v1/matches/%s/preview", mid),
+		})
+		return
+	}
+
+	ttl := 4 * time.Hour
+	if m.CDN.CanSign() {
+		ttl = m.CDN.TTL()
+	}
+
+	forensic := video.WatermarkPrefix != nil
+	session, err := m.Watermarks.StartSession(c.Request.Context(), uid, "match_video", video.ID, forensic, c.ClientIP(), c.Request.UserAgent(), ttl)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to start playback"})
+		return
+	}
+
+	var streamURL string
+	if forensic {
+		// Session-specific playlist mixing the A/B renditions
+		streamURL = fmt.Sprintf("%s/api/v1/matches/%s/stream/%s/%s", requestBaseURL(c), mid, session.ID, watermark.PlaylistName)
+	} else {
+		streamURL, ttl = m.paidVideoURL(c, video.VideoURL, ttl)
+	}
+
+	if purchase != nil {
+		now := time.Now()
+		updates := map[string]interface{}{"last_viewed_at": now, "view_count": gorm.Expr("view_count + 1")}
+		if purchase.FirstViewedAt == nil {
+			updates["first_viewed_at"] = now
+		}
+		m.DB.Model(&purchase).Updates(updates)
+	}
+
+	// Everyone in the lineup is on screen
+	access.RecordQuery(m.DB, c, access.ActionStreamMatch, "match", &mid,
+		m.DB.Model(&domain.MatchPlayer{}).Select("player_id").Where("match_id = ?", mid))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"stream_url": streamURL,
+			"expires_in": int(ttl.Seconds()),
+			"watermark": gin.H{
+				"code":     session.WatermarkCode,
+				"forensic": forensic,
+			},
+		},
+	})
+}
+
+// GetWatermarkedPlaylist serves the HLS media playlist for one playback session.
+// It's public because native HLS players can't send auth headers; the session ID
+// is unguessable and expires. Segment URLs are signed individually so the viewer
+// can't fetch the other rendition and strip the watermark.
+func (m *Module) GetWatermarkedPlaylist(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+	sid, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid session ID"})
+		return
+	}
+
+	session, err := m.Watermarks.ActiveSession(c.Request.Context(), sid)
+	if errors.Is(err, watermark.ErrSessionExpired) {
+		c.JSON(http.StatusGone, gin.H{"success": false, "message": "Playback session expired"})
+		return
+	}
+	if err != nil || session.AssetType != "match_video" || !session.Forensic {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Playback session not found"})
+		return
+	}
+
+	var video domain.MatchVideo
+	if err := m.DB.First(&video, "id = ? AND match_id = ?", session.AssetID, mid).Error; err != nil || video.WatermarkPrefix == nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+
+	playlist, err := watermark.SessionPlaylist(c.Request.Context(), m.Store, m.CDN, *video.WatermarkPrefix, session, c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to build playlist"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "application/vnd.apple.mpegurl", playlist)
+}
+
+// ==================== SCOUT NOTES ====================
+
+// Notes are private to the scout who wrote them. A note can point at a player from
+// the match lineup, which is how it ends up on that scout's saved player entry.
+
+// MatchNoteRequest is the request body for creating or updating a note. The position
+// can be given as seconds or as a "34:10" / "1:02:45" timestamp.
+type MatchNoteRequest struct {
+	TimestampSeconds *int    `json:"timestamp_seconds"`
+	Timestamp        *string `json:"timestamp"`
+	PlayerID         *string `json:"player_id"`
+	Body             *string `json:"body"`
+}
+
+// ListMatchNotes lists the current user's notes on a match, in video order
+func (m *Module) ListMatchNotes(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	query := m.DB.Preload("Player").Where("user_id = ? AND match_id = ?", userID, mid)
+	if playerID := c.Query("player_id"); playerID != "" {
+		pid, err := uuid.Parse(playerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid player ID"})
+			return
+		}
+		query = query.Where("player_id = ?", pid)
+	}
+
+	var notes []domain.MatchVideoNote
+	query.Order("timestamp_seconds ASC, created_at ASC").Find(&notes)
+
+	policy := privacy.For(c, m.DB)
+	response := make([]gin.H, len(notes))
+	for i, n := range notes {
+		response[i] = matchNoteResponse(n, policy)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": response})
+}
+
+// CreateMatchNote adds a timestamped note to a match video
+func (m *Module) CreateMatchNote(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	var req MatchNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+	if req.Body == nil || strings.TrimSpace(*req.Body) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Note body is required"})
+		return
+	}
+
+	var video domain.MatchVideo
+	if err := m.DB.First(&video, "match_id = ?", mid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+
+	seconds, err := noteTimestamp(req, video)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	if seconds == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "timestamp or timestamp_seconds is required"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	note := domain.MatchVideoNote{
+		UserID:           userID.(uuid.UUID),
+		MatchID:          mid,
+		MatchVideoID:     video.ID,
+		TimestampSeconds: *seconds,
+		Body:             strings.TrimSpace(*req.Body),
+	}
+
+	if req.PlayerID != nil && *req.PlayerID != "" {
+		pid, err := m.lineupPlayer(mid, *req.PlayerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+			return
+		}
+		note.PlayerID = &pid
+	}
+
+	if err := m.DB.Create(&note).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to save note"})
+		return
+	}
+	m.DB.Preload("Player").First(&note, "id = ?", note.ID)
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": matchNoteResponse(note, privacy.For(c, m.DB))})
+}
+
+// UpdateMatchNote edits a note's text, timestamp or player. Send an empty
+// player_id to turn it back into a general note.
+func (m *Module) UpdateMatchNote(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	note, ok := m.ownNote(c, mid)
+	if !ok {
+		return
+	}
+
+	var req MatchNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+
+	var video domain.MatchVideo
+	m.DB.First(&video, "id = ?", note.MatchVideoID)
+
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	seconds, err := noteTimestamp(req, video)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	if seconds != nil {
+		updates["timestamp_seconds"] = *seconds
+	}
+	if req.Body != nil {
+		if strings.TrimSpace(*req.Body) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Note body can't be empty"})
+			return
+		}
+		updates["body"] = strings.TrimSpace(*req.Body)
+	}
+	if req.PlayerID != nil {
+		if *req.PlayerID == "" {
+			updates["player_id"] = nil
+		} else {
+			pid, err := m.lineupPlayer(mid, *req.PlayerID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+				return
+			}
+			updates["player_id"] = pid
+		}
+	}
+
+	if err := m.DB.Model(&note).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update note"})
+		return
+	}
+	m.DB.Preload("Player").First(&note, "id = ?", note.ID)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": matchNoteResponse(note, privacy.For(c, m.DB))})
+}
+
+// DeleteMatchNote deletes one of the current user's notes
+func (m *Module) DeleteMatchNote(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	note, ok := m.ownNote(c, mid)
+	if !ok {
+		return
+	}
+
+	m.DB.Delete(&note)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Note deleted"})
+}
+
+// GetPlayerMatchNotes aggregates the current user's notes about a player across
+// every match they were noted in, newest match first
+func (m *Module) GetPlayerMatchNotes(c *gin.Context) {
+	pid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid player ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	var notes []domain.MatchVideoNote
+	m.DB.Preload("Match").Where("user_id = ? AND player_id = ?", userID, pid).
+		Order("timestamp_seconds ASC, created_at ASC").Find(&notes)
+
+	// Group by match, keeping the lineup details (shirt number, position) alongside
+	policy := privacy.For(c, m.DB)
+	byMatch := map[uuid.UUID][]gin.H{}
+	var matchIDs []uuid.UUID
+	matchesByID := map[uuid.UUID]*domain.Match{}
+	for _, n := range notes {
+		if _, seen := byMatch[n.MatchID]; !seen {
+			matchIDs = append(matchIDs, n.MatchID)
+			matchesByID[n.MatchID] = n.Match
+		}
+		byMatch[n.MatchID] = append(byMatch[n.MatchID], matchNoteResponse(n, policy))
+	}
+
+	lineups := map[uuid.UUID]domain.MatchPlayer{}
+	if len(matchIDs) > 0 {
+		var rows []domain.MatchPlayer
+		m.DB.Where("player_id = ? AND match_id IN ?", pid, matchIDs).Find(&rows)
+		for _, mp := range rows {
+			lineups[mp.MatchID] = mp
+		}
+	}
+
+	sort.SliceStable(matchIDs, func(i, j int) bool {
+		mi, mj := matchesByID[matchIDs[i]], matchesByID[matchIDs[j]]
+		return mi != nil && mj != nil && mi.MatchDate.After(mj.MatchDate)
+	})
+
+	groups := make([]gin.H, 0, len(matchIDs))
+	for _, id := range matchIDs {
+		group := gin.H{
+			"match_id":   id,
+			"notes":      byMatch[id],
+			"note_count": len(byMatch[id]),
+		}
+		if match := matchesByID[id]; match != nil {
+			group["match"] = gin.H{
+				"title":      match.Title,
+				"match_date": match.MatchDate,
+				"home_team":  match.HomeTeam,
+				"away_team":  match.AwayTeam,
+				"stage":      match.Stage,
+			}
+		}
+		if mp, ok := lineups[id]; ok {
+			group["lineup"] = gin.H{
+				"jersey_number":   mp.JerseyNumber,
+				"position_played": mp.PositionPlayed,
+				"is_starter":      mp.IsStarter,
+				"minutes_played":  mp.MinutesPlayed,
+			}
+		}
+		groups = append(groups, group)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"player_id":   pid,
+			"total_notes": len(notes),
+			"matches":     groups,
+		},
+	})
+}
+
+// ownNote loads a note on the match belonging to the current user, writing the
+// error response when it can't
+func (m *Module) ownNote(c *gin.Context, matchID uuid.UUID) (domain.MatchVideoNote, bool) {
+	var note domain.MatchVideoNote
+	nid, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid note ID"})
+		return note, false
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := m.DB.First(&note, "id = ? AND match_id = ? AND user_id = ?", nid, matchID, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Note not found"})
+		return note, false
+	}
+	return note, true
+}
+
+// lineupPlayer checks that a player was in the match lineup
+func (m *Module) lineupPlayer(matchID uuid.UUID, playerID string) (uuid.UUID, error) {
+	pid, err := uuid.Parse(playerID)
+	if err != nil {
+		return uuid.Nil, errors.New("Invalid player ID")
+	}
+	var count int64
+	m.DB.Model(&domain.MatchPlayer{}).Where("match_id = ? AND player_id = ?", matchID, pid).Count(&count)
+	if count == 0 {
+		return uuid.Nil, errors.New("Player is not in this match's lineup")
+	}
+	return pid, nil
+}
+
+// noteTimestamp resolves the note position from the request, nil when none was given
+func noteTimestamp(req MatchNoteRequest, video domain.MatchVideo) (*int, error) {
+	var seconds int
+	switch {
+	case req.TimestampSeconds != nil:
+		seconds = *req.TimestampSeconds
+	case req.Timestamp != nil:
+		parsed, err := parseTimestamp(*req.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		seconds = parsed
+	default:
+		return nil, nil
+	}
+
+	if seconds < 0 {
+		return nil, errors.New("Timestamp can't be negative")
+	}
+	if video.DurationSeconds != nil && *video.DurationSeconds > 0 && seconds > *video.DurationSeconds {
+		return nil, errors.New("Timestamp is past the end of the video")
+	}
+	return &seconds, nil
+}
+
+// parseTimestamp parses "ss", "mm:ss" or "hh:mm:ss"
+func parseTimestamp(ts string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(ts), ":")
+	if len(parts) > 3 {
+		return 0, errors.New("Invalid timestamp, use mm:ss or hh:mm:ss")
+	}
+	total := 0
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil || v < 0 || (i > 0 && v > 59) {
+			return 0, errors.New("Invalid timestamp, use mm:ss or hh:mm:ss")
+		}
+		total = total*60 + v
+	}
+	return total, nil
+}
+
+func matchNoteResponse(n domain.MatchVideoNote, policy *privacy.Policy) gin.H {
+	note := gin.H{
+		"id":                n.ID,
+		"match_id":          n.MatchID,
+		"player_id":         n.PlayerID,
+		"timestamp_seconds": n.TimestampSeconds,
+		"timestamp":         n.Timestamp(),
+		"body":              n.Body,
+		"created_at":        n.CreatedAt,
+		"updated_at":        n.UpdatedAt,
+	}
+	if n.Player != nil {
+		masked := policy.Mask(n.Player)
+		note["player"] = gin.H{
+			"id":             n.Player.ID,
+			"first_name":     n.Player.FirstName,
+			"last_name":      masked.LastName,
+			"last_name_init": masked.LastNameInit,
+			"position":       n.Player.Position,
+		}
+	}
+	return note
+}
+
+// ==================== PLAYER MOMENTS ====================
+
+// Moments are the ranges of a full match in which a player is involved. Media staff
+// tag them while clipping or import them from CSV; viewers get them merged into a
+// "watch only this player" playlist.
+
+// defaultMomentPadding is the context (seconds) added around each moment in the playlist
+const defaultMomentPadding = 3
+
+// MomentInput is one tagged moment. The player is given by ID or by shirt number in
+// the match lineup; times as seconds or "mm:ss" / "hh:mm:ss".
+type MomentInput struct {
+	PlayerID     string  `json:"player_id"`
+	JerseyNumber *int    `json:"jersey_number"`
+	StartSeconds *int    `json:"start_seconds"`
+	EndSeconds   *int    `json:"end_seconds"`
+	Start        *string `json:"start"`
+	End          *string `json:"end"`
+	Label        *string `json:"label"`
+}
+
+// ListMatchMoments lists every tagged moment of a match (admin)
+func (m *Module) ListMatchMoments(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	query := m.DB.Preload("Player").Where("match_id = ?", mid)
+	if playerID := c.Query("player_id"); playerID != "" {
+		pid, err := uuid.Parse(playerID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid player ID"})
+			return
+		}
+		query = query.Where("player_id = ?", pid)
+	}
+
+	var moments []domain.MatchPlayerMoment
+	query.Order("start_seconds ASC").Find(&moments)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": moments})
+}
+
+// CreateMatchMoments tags one or more moments during a clipping session (admin).
+// Either every moment is saved or none are.
+func (m *Module) CreateMatchMoments(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	var req struct {
+		Moments []MomentInput `json:"moments" binding:"required,min=1,max=500"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+
+	var video domain.MatchVideo
+	if err := m.DB.First(&video, "match_id = ?", mid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	lineup := m.matchLineup(mid)
+
+	moments := make([]domain.MatchPlayerMoment, 0, len(req.Moments))
+	var rowErrors []gin.H
+	for i, in := range req.Moments {
+		moment, err := buildMoment(in, lineup, video)
+		if err != nil {
+			rowErrors = append(rowErrors, gin.H{"index": i, "error": err.Error()})
+			continue
+		}
+		moment.Source = "clipping"
+		moment.CreatedBy = userID.(uuid.UUID)
+		moments = append(moments, moment)
+	}
+	if len(rowErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Some moments are invalid", "errors": rowErrors})
+		return
+	}
+
+	if err := m.DB.Create(&moments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to save moments"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": moments})
+}
+
+// UpdateMatchMoment adjusts a moment's range, player or label (admin)
+func (m *Module) UpdateMatchMoment(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+	momentID, err := uuid.Parse(c.Param("momentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid moment ID"})
+		return
+	}
+
+	var moment domain.MatchPlayerMoment
+	if err := m.DB.First(&moment, "id = ? AND match_id = ?", momentID, mid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Moment not found"})
+		return
+	}
+
+	var in MomentInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+
+	// Fill in what wasn't sent from the current moment, then validate as a whole
+	if in.PlayerID == "" && in.JerseyNumber == nil {
+		in.PlayerID = moment.PlayerID.String()
+	}
+	if in.StartSeconds == nil && in.Start == nil {
+		in.StartSeconds = &moment.StartSeconds
+	}
+	if in.EndSeconds == nil && in.End == nil {
+		in.EndSeconds = &moment.EndSeconds
+	}
+	if in.Label == nil {
+		in.Label = moment.Label
+	}
+
+	var video domain.MatchVideo
+	m.DB.First(&video, "id = ?", moment.MatchVideoID)
+
+	updated, err := buildMoment(in, m.matchLineup(mid), video)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	if err := m.DB.Model(&moment).Updates(map[string]interface{}{
+		"player_id":     updated.PlayerID,
+		"start_seconds": updated.StartSeconds,
+		"end_seconds":   updated.EndSeconds,
+		"label":         updated.Label,
+		"updated_at":    time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update moment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Moment updated"})
+}
+
+// DeleteMatchMoment removes a tagged moment (admin)
+func (m *Module) DeleteMatchMoment(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+	momentID, err := uuid.Parse(c.Param("momentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid moment ID"})
+		return
+	}
+
+	result := m.DB.Delete(&domain.MatchPlayerMoment{}, "id = ? AND match_id = ?", momentID, mid)
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Moment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Moment deleted"})
+}
+
+// ImportMatchMoments bulk-imports moments from a CSV upload (form field "file").
+// The header row names the columns: player_id or jersey_number, start, end and an
+// optional label. With replace=true, moments from earlier imports are dropped
+// first; moments tagged while clipping are kept. Nothing is written if any row fails.
+func (m *Module) ImportMatchMoments(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	var video domain.MatchVideo
+	if err := m.DB.First(&video, "match_id = ?", mid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "CSV file is required"})
+		return
+	}
+	if file.Size > 5<<20 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "CSV file too large (max 5MB)"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read CSV file"})
+		return
+	}
+	defer f.Close()
+
+	inputs, err := parseMomentsCSV(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uid := userID.(uuid.UUID)
+	lineup := m.matchLineup(mid)
+
+	moments := make([]domain.MatchPlayerMoment, 0, len(inputs))
+	var rowErrors []gin.H
+	for i, in := range inputs {
+		moment, err := buildMoment(in, lineup, video)
+		if err != nil {
+			// Row 1 is the header
+			rowErrors = append(rowErrors, gin.H{"row": i + 2, "error": err.Error()})
+			continue
+		}
+		moment.Source = "csv_import"
+		moment.CreatedBy = uid
+		moments = append(moments, moment)
+	}
+	if len(rowErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Some rows are invalid, nothing was imported", "errors": rowErrors})
+		return
+	}
+
+	replace := c.Query("replace") == "true"
+	var replaced int64
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		if replace {
+			result := tx.Delete(&domain.MatchPlayerMoment{}, "match_id = ? AND source = ?", mid, "csv_import")
+			if result.Error != nil {
+				return result.Error
+			}
+			replaced = result.RowsAffected
+		}
+		return tx.CreateInBatches(&moments, 200).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to import moments"})
+		return
+	}
+
+	ip := c.ClientIP()
+	details, _ := json.Marshal(gin.H{
+		"file_name": file.Filename,
+		"imported":  len(moments),
+		"replaced":  replaced,
+	})
+	detailStr := string(details)
+	m.DB.Create(&domain.AuditLog{
+		UserID:       &uid,
+		Action:       "match_moments_import",
+		ResourceType: "match",
+		ResourceID:   &mid,
+		Details:      &detailStr,
+		IPAddress:    &ip,
+		CreatedAt:    time.Now(),
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("Imported %d moments", len(moments)),
+		"data": gin.H{
+			"imported": len(moments),
+			"replaced": replaced,
+		},
+	})
+}
+
+// GetPlayerMoments returns a player's moments in a match and the merged ranges to
+// play for "watch only this player". Overlapping or nearly adjacent moments are
+// joined after padding each side by ?padding= seconds (default 3).
+func (m *Module) GetPlayerMoments(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+	pid, err := uuid.Parse(c.Param("playerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid player ID"})
+		return
+	}
+
+	var video domain.MatchVideo
+	if err := m.DB.First(&video, "match_id = ? AND status = ?", mid, "ready").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+
+	var matchPlayer domain.MatchPlayer
+	if err := m.DB.Preload("Player").First(&matchPlayer, "match_id = ? AND player_id = ?", mid, pid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Player not in match"})
+		return
+	}
+
+	padding := ParseInt(c.Query("padding"), defaultMomentPadding)
+	if padding < 0 || padding > 30 {
+		padding = defaultMomentPadding
+	}
+
+	var moments []domain.MatchPlayerMoment
+	m.DB.Where("match_video_id = ? AND player_id = ?", video.ID, pid).Order("start_seconds ASC").Find(&moments)
+
+	items := make([]gin.H, len(moments))
+	for i, mo := range moments {
+		items[i] = gin.H{
+			"id":            mo.ID,
+			"start_seconds": mo.StartSeconds,
+			"end_seconds":   mo.EndSeconds,
+			"start":         domain.FormatTimestamp(mo.StartSeconds),
+			"end":           domain.FormatTimestamp(mo.EndSeconds),
+			"label":         mo.Label,
+		}
+	}
+
+	playlist, totalSeconds := mergeMoments(moments, padding, video.DurationSeconds)
+
+	userID, _ := c.Get("user_id")
+	hasAccess, _ := m.hasFullAccess(c, userID.(uuid.UUID), video.ID)
+
+	data := gin.H{
+		"match_id":      mid,
+		"player_id":     pid,
+		"moments":       items,
+		"playlist":      playlist,
+		"total_seconds": totalSeconds,
+		"has_access":    hasAccess,
+	}
+	if matchPlayer.Player != nil {
+		masked := privacy.For(c, m.DB).Mask(matchPlayer.Player)
+		data["player"] = gin.H{
+			"first_name":      matchPlayer.Player.FirstName,
+			"last_name":       masked.LastName,
+			"last_name_init":  masked.LastNameInit,
+			"jersey_number":   matchPlayer.JerseyNumber,
+			"position_played": matchPlayer.PositionPlayed,
+		}
+	}
+	if hasAccess {
+		if matchPlayer.Player != nil {
+			access.Record(m.DB, c, access.ActionViewMoments, "match", &mid, *matchPlayer.Player)
+		}
+		data["stream_url"] = fmt.Sprintf("/api/v1/matches/%s/stream", mid)
+	} else {
+		data["preview_url"] = fmt.Sprintf("/api/v1/matches/%s/preview", mid)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+}
+
+// matchLineup indexes a match's lineup by player ID and shirt number
+type matchLineup struct {
+	players map[uuid.UUID]bool
+	jerseys map[int]uuid.UUID
+}
+
+func (m *Module) matchLineup(matchID uuid.UUID) matchLineup {
+	var rows []domain.MatchPlayer
+	m.DB.Where("match_id = ?", matchID).Find(&rows)
+
+	lineup := matchLineup{players: map[uuid.UUID]bool{}, jerseys: map[int]uuid.UUID{}}
+	for _, mp := range rows {
+		lineup.players[mp.PlayerID] = true
+		if mp.JerseyNumber != nil {
+			lineup.jerseys[*mp.JerseyNumber] = mp.PlayerID
+		}
+	}
+	return lineup
+}
+
+// buildMoment validates a moment against the lineup and the video length
+func buildMoment(in MomentInput, lineup matchLineup, video domain.MatchVideo) (domain.MatchPlayerMoment, error) {
+	var moment domain.MatchPlayerMoment
+
+	switch {
+	case in.PlayerID != "":
+		pid, err := uuid.Parse(in.PlayerID)
+		if err != nil {
+			return moment, errors.New("invalid player_id")
+		}
+		if !lineup.players[pid] {
+			return moment, errors.New("player is not in this match's lineup")
+		}
+		moment.PlayerID = pid
+	case in.JerseyNumber != nil:
+		pid, ok := lineup.jerseys[*in.JerseyNumber]
+		if !ok {
+			return moment, fmt.Errorf("no player wears #%d in this match", *in.JerseyNumber)
+		}
+		moment.PlayerID = pid
+	default:
+		return moment, errors.New("player_id or jersey_number is required")
+	}
+
+	start, err := momentTime(in.StartSeconds, in.Start, "start")
+	if err != nil {
+		return moment, err
+	}
+	end, err := momentTime(in.EndSeconds, in.End, "end")
+	if err != nil {
+		return moment, err
+	}
+	if start < 0 || end <= start {
+		return moment, errors.New("end must be after start")
+	}
+	if video.DurationSeconds != nil && *video.DurationSeconds > 0 && end > *video.DurationSeconds {
+		return moment, errors.New("moment ends after the video")
+	}
+
+	moment.MatchID = video.MatchID
+	moment.MatchVideoID = video.ID
+	moment.StartSeconds = start
+	moment.EndSeconds = end
+	if in.Label != nil && strings.TrimSpace(*in.Label) != "" {
+		label := strings.TrimSpace(*in.Label)
+		if len(label) > 100 {
+			return moment, errors.New("label is too long (max 100 characters)")
+		}
+		moment.Label = &label
+	}
+	return moment, nil
+}
+
+func momentTime(seconds *int, ts *string, field string) (int, error) {
+	if seconds != nil {
+		return *seconds, nil
+	}
+	if ts == nil || strings.TrimSpace(*ts) == "" {
+		return 0, fmt.Errorf("%s is required", field)
+	}
+	parsed, err := parseTimestamp(*ts)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s, use seconds, mm:ss or hh:mm:ss", field)
+	}
+	return parsed, nil
+}
+
+// parseMomentsCSV reads moment rows keyed by the header row
+func parseMomentsCSV(r io.Reader) ([]MomentInput, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("CSV file is empty or unreadable")
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	_, hasPlayer := cols["player_id"]
+	_, hasJersey := cols["jersey_number"]
+	if !hasPlayer && !hasJersey {
+		return nil, errors.New("CSV needs a player_id or jersey_number column")
+	}
+	for _, required := range []string{"start", "end"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("CSV is missing the %s column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var inputs []MomentInput
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		if len(inputs) >= 5000 {
+			return nil, errors.New("CSV has too many rows (max 5000)")
+		}
+
+		in := MomentInput{PlayerID: field(record, "player_id")}
+		if jersey := field(record, "jersey_number"); jersey != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(jersey, "#"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid jersey_number %q on row %d", jersey, len(inputs)+2)
+			}
+			in.JerseyNumber = &n
+		}
+		start, end, label := field(record, "start"), field(record, "end"), field(record, "label")
+		in.Start, in.End, in.Label = &start, &end, &label
+		inputs = append(inputs, in)
+	}
+	if len(inputs) == 0 {
+		return nil, errors.New("CSV has no rows")
+	}
+	return inputs, nil
+}
+
+// mergeMoments pads moments, clamps them to the video and joins overlapping ranges.
+// Returns the ranges and their total length in seconds.
+func mergeMoments(moments []domain.MatchPlayerMoment, padding int, duration *int) ([]gin.H, int) {
+	type span struct{ start, end int }
+	var spans []span
+	for _, mo := range moments {
+		s := span{start: mo.StartSeconds - padding, end: mo.EndSeconds + padding}
+		if s.start < 0 {
+			s.start = 0
+		}
+		if duration != nil && *duration > 0 && s.end > *duration {
+			s.end = *duration
+		}
+		if n := len(spans); n > 0 && s.start <= spans[n-1].end {
+			if s.end > spans[n-1].end {
+				spans[n-1].end = s.end
+			}
+			continue
+		}
+		spans = append(spans, s)
+	}
+
+	playlist := make([]gin.H, len(spans))
+	total := 0
+	for i, s := range spans {
+		playlist[i] = gin.H{
+			"start_seconds": s.start,
+			"end_seconds":   s.end,
+			"start":         domain.FormatTimestamp(s.start),
+			"end":           domain.FormatTimestamp(s.end),
+		}
+		total += s.end - s.start
+	}
+	return playlist, total
+}
+
+// ==================== PREVIEWS ====================
+
+// previewTTL is how long preview URLs stay valid
+const previewTTL = 1 * time.Hour
+
+// GetMatchPreview returns the free preview of a locked full match: the first N
+// minutes or an admin-picked segment. Public; logged-in users who already have
+// access are flagged so the UI can switch to the full stream. Every call is
+// recorded for preview-to-purchase conversion analytics.
+func (m *Module) GetMatchPreview(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	var match domain.Match
+	if err := m.DB.Preload("Video").First(&match, "id = ?", mid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Match not found"})
+		return
+	}
+	if match.Video == nil || match.Video.Status != "ready" {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+	video := *match.Video
+
+	clipKey, playlistKey := previewSource(video)
+	if clipKey == "" && playlistKey == "" {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "No preview available for this match"})
+		return
+	}
+
+	start, duration := video.PreviewWindow()
+	preview := gin.H{
+		"start_seconds":    start,
+		"duration_seconds": duration,
+		"expires_in":       int(previewTTL.Seconds()),
+	}
+	if clipKey != "" {
+		url, _ := m.paidVideoURL(c, clipKey, previewTTL)
+		preview["type"] = "clip"
+		preview["url"] = url
+		preview["start_seconds"] = 0
+	} else {
+		preview["type"] = "hls"
+		preview["url"] = fmt.Sprintf("%s/api/v1/matches/%s/preview/index.m3u8", requestBaseURL(c), mid)
+	}
+
+	var viewerID *uuid.UUID
+	hasAccess := false
+	if userID, exists := c.Get("user_id"); exists {
+		uid := userID.(uuid.UUID)
+		viewerID = &uid
+		hasAccess, _ = m.hasFullAccess(c, uid, video.ID)
+	}
+
+	m.DB.Create(&domain.MatchPreviewView{
+		MatchVideoID: video.ID,
+		ViewerID:     viewerID,
+		IPHash:       stringPtr(m.Views.HashIP(c.ClientIP())),
+		Source:       stringPtr(c.Query("source")),
+		CreatedAt:    time.Now(),
+	})
+
+	var thumbnailURL *string
+	if video.ThumbnailURL != nil {
+		url := storage.SignedURL(m.Store, *video.ThumbnailURL)
+		thumbnailURL = &url
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"match": gin.H{
+				"id":            match.ID,
+				"title":         match.Title,
+				"match_date":    match.MatchDate,
+				"stage":         match.Stage,
+				"home_team":     match.HomeTeam,
+				"away_team":     match.AwayTeam,
+				"home_score":    match.HomeScore,
+				"away_score":    match.AwayScore,
+				"tournament_id": match.TournamentID,
+			},
+			"preview":          preview,
+			"thumbnail_url":    thumbnailURL,
+			"duration_seconds": video.DurationSeconds,
+			"has_access":       hasAccess,
+			"price_cents":      video.PriceCents,
+			"currency":         video.Currency,
+			"upgrade_url":      "/subscribe",
+		},
+	})
+}
+
+// GetPreviewPlaylist serves the preview window of an HLS match as its own VOD
+// playlist. Only segments inside the window are listed, each with a short-lived
+// signed URL, so the preview can't be stretched into the full match.
+func (m *Module) GetPreviewPlaylist(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	var video domain.MatchVideo
+	if err := m.DB.First(&video, "match_id = ? AND status = ?", mid, "ready").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+
+	_, playlistKey := previewSource(video)
+	if playlistKey == "" {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "No HLS preview available for this match"})
+		return
+	}
+
+	src, err := m.readObject(c.Request.Context(), playlistKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to load playlist"})
+		return
+	}
+
+	dir := path.Dir(playlistKey)
+	start, duration := video.PreviewWindow()
+	playlist, _, _, err := hls.Window(src, float64(start), float64(duration), func(uri string) (string, error) {
+		if strings.Contains(uri, "://") {
+			return "", fmt.Errorf("absolute URI %q in match playlist", uri)
+		}
+		key := path.Join(dir, uri)
+		if m.CDN.CanSign() {
+			signed, _, err := m.CDN.SignedURL(key, c.ClientIP())
+			return signed, err
+		}
+		return m.Store.PresignGet(c.Request.Context(), key, previewTTL)
+	})
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "message": "Failed to build preview: " + err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, hls.ContentType, playlist)
+}
+
+// UpdatePreviewSettings picks the free preview for a match video: a pre-cut clip
+// (preview_key, "" to clear) and/or the window of the HLS stream to expose
+func (m *Module) UpdatePreviewSettings(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	var req struct {
+		PreviewKey      *string `json:"preview_key"`
+		StartSeconds    *int    `json:"start_seconds" binding:"omitempty,min=0"`
+		DurationSeconds *int    `json:"duration_seconds" binding:"omitempty,min=0,max=900"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+
+	var video domain.MatchVideo
+	if err := m.DB.First(&video, "match_id = ?", mid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.PreviewKey != nil {
+		if *req.PreviewKey == "" {
+			updates["preview_key"] = nil
+		} else {
+			if _, err := m.Store.Head(c.Request.Context(), *req.PreviewKey); err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "message": "Preview clip not found in storage"})
+				return
+			}
+			updates["preview_key"] = *req.PreviewKey
+		}
+	}
+	if req.StartSeconds != nil {
+		if video.DurationSeconds != nil && *req.StartSeconds >= *video.DurationSeconds {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Preview start is past the end of the video"})
+			return
+		}
+		updates["preview_start_seconds"] = *req.StartSeconds
+	}
+	if req.DurationSeconds != nil {
+		updates["preview_duration_seconds"] = *req.DurationSeconds
+	}
+
+	if len(updates) > 0 {
+		if err := m.DB.Model(&video).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update preview"})
+			return
+		}
+	}
+	m.DB.First(&video, "id = ?", video.ID)
+
+	start, duration := video.PreviewWindow()
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Preview updated",
+		"data": gin.H{
+			"match_video_id":   video.ID,
+			"has_clip":         video.PreviewKey != nil,
+			"start_seconds":    start,
+			"duration_seconds": duration,
+		},
+	})
+}
+
+// previewSource works out where a video's free preview comes from: a pre-cut clip,
+// or the HLS playlist to cut a window from (the video itself, or rendition "a" of a
+// watermarked video). Both are empty when no preview can be offered.
+func previewSource(video domain.MatchVideo) (clipKey, playlistKey string) {
+	switch {
+	case video.PreviewKey != nil && *video.PreviewKey != "":
+		return *video.PreviewKey, ""
+	case cdn.IsHLS(video.VideoURL):
+		return "", video.VideoURL
+	case video.WatermarkPrefix != nil:
+		return "", path.Join(strings.Trim(*video.WatermarkPrefix, "/"), watermark.VariantA, watermark.PlaylistName)
+	}
+	return "", ""
+}
+
+// ==================== WATERMARKS (ADMIN) ====================
+
+// SetWatermarkRenditions points a match video at its A/B HLS renditions. Both
+// <prefix>/a/index.m3u8 and <prefix>/b/index.m3u8 must exist with the same segment
+// count. An empty prefix turns forensic watermarking off for the video.
+func (m *Module) SetWatermarkRenditions(c *gin.Context) {
+	mid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid match ID"})
+		return
+	}
+
+	var req struct {
+		Prefix string `json:"prefix"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+
+	var video domain.MatchVideo
+	if err := m.DB.First(&video, "match_id = ?", mid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+		return
+	}
+
+	prefix := strings.Trim(req.Prefix, "/")
+	if prefix == "" {
+		video.WatermarkPrefix = nil
+	} else {
+		if err := watermark.CheckRenditions(c.Request.Context(), m.Store, prefix); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "message": "Invalid watermark renditions: " + err.Error()})
+			return
+		}
+		video.WatermarkPrefix = &prefix
+	}
+
+	if err := m.DB.Model(&video).Update("watermark_prefix", video.WatermarkPrefix).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update video"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Watermark renditions updated",
+		"data": gin.H{
+			"match_video_id":   video.ID,
+			"watermark_prefix": video.WatermarkPrefix,
+		},
+	})
+}
+
+// LookupWatermarkRequest describes a watermark recovered from a leaked copy
+type LookupWatermarkRequest struct {
+	Code         string  `json:"code"`          // visible overlay code
+	Sequence     string  `json:"sequence"`      // A/B rendition per segment, e.g. "ABBA?AB..."
+	StartSegment *int    `json:"start_segment"` // index of the first segment in the sequence, if known
+	MatchID      *string `json:"match_id"`      // match the clip was taken from
+	VideoID      *string `json:"video_id"`      // or a full-match video from the media library
+}
+
+// LookupWatermark identifies which account streamed a leaked clip. Every lookup is
+// recorded in the audit log since it reveals who watched what.
+func (m *Module) LookupWatermark(c *gin.Context) {
+	var req LookupWatermarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request"})
+		return
+	}
+
+	query := watermark.LookupQuery{
+		Code:         req.Code,
+		Sequence:     req.Sequence,
+		StartSegment: req.StartSegment,
+	}
+	if req.MatchID != nil && *req.MatchID != "" {
+		var video domain.MatchVideo
+		if err := m.DB.First(&video, "match_id = ?", *req.MatchID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Video not found"})
+			return
+		}
+		query.AssetType = "match_video"
+		query.AssetID = &video.ID
+	} else if req.VideoID != nil && *req.VideoID != "" {
+		vid, err := uuid.Parse(*req.VideoID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid video ID"})
+			return
+		}
+		query.AssetType = "video"
+		query.AssetID = &vid
+	}
+
+	results, err := m.Watermarks.Lookup(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uid := userID.(uuid.UUID)
+	ip := c.ClientIP()
+	details, _ := json.Marshal(gin.H{
+		"code":          req.Code,
+		"sequence":      req.Sequence,
+		"start_segment": req.StartSegment,
+		"asset_type":    query.AssetType,
+		"asset_id":      query.AssetID,
+		"matches":       len(results),
+	})
+	detailStr := string(details)
+	m.DB.Create(&domain.AuditLog{
+		UserID:       &uid,
+		Action:       "watermark_lookup",
+		ResourceType: "playback_session",
+		Details:      &detailStr,
+		IPAddress:    &ip,
+		CreatedAt:    time.Now(),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"matches": results,
+			"count":   len(results),
+		},
+	})
+}
+
+// ==================== TOURNAMENT STRUCTURE ====================
+
+// TeamRequest is the request body for creating or updating a team. An empty
+// academy_id or group_id clears it, and a seed of 0 removes the seed.
+type TeamRequest struct {
+	Name      *string `json:"name"` // defaults to the academy's name
+	ShortName *string `json:"short_name"`
+	AcademyID *string `json:"academy_id"`
+	GroupID   *string `json:"group_id"`
+	Seed      *int    `json:"seed"`
+}
+
+// GroupRequest is the request body for creating or updating a group. team_ids
+// replaces the group's teams, moving them out of any other group.
+type GroupRequest struct {
+	Name     *string  `json:"name"`
+	Position *int     `json:"position"`
+	TeamIDs  []string `json:"team_ids"`
+}
+
+// GenerateFixturesRequest is the request body for generating group fixtures or a
+// knockout bracket. Rounds are played days_between_rounds apart from start_date.
+type GenerateFixturesRequest struct {
+	StartDate         string   `json:"start_date" binding:"required"` // YYYY-MM-DD
+	DaysBetweenRounds *int     `json:"days_between_rounds"`           // default 1
+	Location          string   `json:"location"`
+	DoubleRoundRobin  bool     `json:"double_round_robin"` // groups: play each team home and away
+	TeamIDs           []string `json:"team_ids"`           // bracket: teams in seed order, default all teams by seed
+	Replace           bool     `json:"replace"`            // delete previously generated, unplayed fixtures
+}
+
+// ListTeams lists the teams entered in a tournament
+func (m *Module) ListTeams(c *gin.Context) {
+	tournament, ok := m.tournament(c)
+	if !ok {
+		return
+	}
+
+	var teams []domain.Team
+	if err := m.DB.Preload("Academy").Where("tournament_id = ?", tournament.ID).
+		Order("seed NULLS LAST, name").Find(&teams).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch teams"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": teams})
+}
+
+// CreateTeam enters a team in a tournament
+func (m *Module) CreateTeam(c *gin.Context) {
+	tournament, ok := m.tournament(c)
+	if !ok {
+		return
+	}
+
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+
+	team := domain.Team{TournamentID: tournament.ID}
+	if msg := m.applyTeamRequest(&team, req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": msg})
+		return
+	}
+	if team.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "A name or academy is required"})
+		return
+	}
+	if m.teamNameTaken(team) {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": "A team with this name is already in the tournament"})
+		return
+	}
+
+	if err := m.DB.Create(&team).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to create team"})
+		return
+	}
+	standings.Refresh(m.DB, &team.TournamentID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Team created successfully",
+		"data":    team,
+	})
+}
+
+// UpdateTeam updates a team. A new name is copied onto its matches.
+func (m *Module) UpdateTeam(c *gin.Context) {
+	tid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid team ID"})
+		return
+	}
+
+	var team domain.Team
+	if err := m.DB.First(&team, "id = ?", tid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Team not found"})
+		return
+	}
+
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+
+	oldName := team.Name
+	if msg := m.applyTeamRequest(&team, req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": msg})
+		return
+	}
+	if team.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Name cannot be empty"})
+		return
+	}
+	if team.Name != oldName && m.teamNameTaken(team) {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": "A team with this name is already in the tournament"})
+		return
+	}
+
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&team).Error; err != nil {
+			return err
+		}
+		if team.Name == oldName {
+			return nil
+		}
+		if err := tx.Model(&domain.Match{}).Where("home_team_id = ?", team.ID).Update("home_team", team.Name).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Match{}).Where("away_team_id = ?", team.ID).Update("away_team", team.Name).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update team"})
+		return
+	}
+	standings.Refresh(m.DB, &team.TournamentID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Team updated successfully",
+		"data":    team,
+	})
+}
+
+// DeleteTeam removes a team that hasn't been given any matches
+func (m *Module) DeleteTeam(c *gin.Context) {
+	tid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid team ID"})
+		return
+	}
+
+	var matches int64
+	m.DB.Model(&domain.Match{}).Where("home_team_id = ? OR away_team_id = ?", tid, tid).Count(&matches)
+	if matches > 0 {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": fmt.Sprintf("The team has %d matches; delete or regenerate them first", matches)})
+		return
+	}
+
+	var team domain.Team
+	m.DB.Select("tournament_id").First(&team, "id = ?", tid)
+
+	result := m.DB.Delete(&domain.Team{}, "id = ?", tid)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to delete team"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Team not found"})
+		return
+	}
+	standings.Refresh(m.DB, &team.TournamentID)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Team deleted successfully"})
+}
+
+// ListGroups lists a tournament's groups with their teams
+func (m *Module) ListGroups(c *gin.Context) {
+	tournament, ok := m.tournament(c)
+	if !ok {
+		return
+	}
+
+	var groups []domain.TournamentGroup
+	if err := m.DB.Preload("Teams", func(db *gorm.DB) *gorm.DB { return db.Order("seed NULLS LAST, name") }).
+		Where("tournament_id = ?", tournament.ID).Order("position, name").Find(&groups).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch groups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": groups})
+}
+
+// CreateGroup adds a group to a tournament, optionally with its teams
+func (m *Module) CreateGroup(c *gin.Context) {
+	tournament, ok := m.tournament(c)
+	if !ok {
+		return
+	}
+
+	var req GroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Name is required"})
+		return
+	}
+
+	group := domain.TournamentGroup{TournamentID: tournament.ID, Name: strings.TrimSpace(*req.Name)}
+	if req.Position != nil {
+		group.Position = *req.Position
+	}
+	m.saveGroup(c, &group, req.TeamIDs, http.StatusCreated, "Group created successfully")
+}
+
+// UpdateGroup renames or reorders a group, or replaces its teams
+func (m *Module) UpdateGroup(c *gin.Context) {
+	gid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid group ID"})
+		return
+	}
+
+	var group domain.TournamentGroup
+	if err := m.DB.First(&group, "id = ?", gid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Group not found"})
+		return
+	}
+
+	var req GroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Name cannot be empty"})
+			return
+		}
+		group.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Position != nil {
+		group.Position = *req.Position
+	}
+	m.saveGroup(c, &group, req.TeamIDs, http.StatusOK, "Group updated successfully")
+}
+
+// DeleteGroup removes a group that has no fixtures. Its teams stay in the tournament.
+func (m *Module) DeleteGroup(c *gin.Context) {
+	gid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid group ID"})
+		return
+	}
+
+	var matches int64
+	m.DB.Model(&domain.Match{}).Where("group_id = ?", gid).Count(&matches)
+	if matches > 0 {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": fmt.Sprintf("The group has %d matches; delete them first", matches)})
+		return
+	}
+
+	var group domain.TournamentGroup
+	m.DB.Select("tournament_id").First(&group, "id = ?", gid)
+
+	var deleted int64
+	err = m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Team{}).Where("group_id = ?", gid).Update("group_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&domain.TournamentGroup{}, "id = ?", gid)
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to delete group"})
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Group not found"})
+		return
+	}
+	standings.Refresh(m.DB, &group.TournamentID)
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Group deleted successfully"})
+}
+
+// GenerateGroupFixtures creates the round-robin matches of every group in the
+// tournament
+func (m *Module) GenerateGroupFixtures(c *gin.Context) {
+	tournament, ok := m.tournament(c)
+	if !ok {
+		return
+	}
+
+	var req GenerateFixturesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+	schedule, msg := fixtureSchedule(req)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": msg})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	var matches []domain.Match
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		matches, err = fixtures.GenerateGroupFixtures(tx, tournament.ID, schedule, req.DoubleRoundRobin, req.Replace, userID)
+		return err
+	})
+	if err != nil {
+		fixturesError(c, err)
+		return
+	}
+
+	standings.Refresh(m.DB, &tournament.ID)
+
+	m.audit(c, "group_fixtures_generate", "tournament", &tournament.ID, gin.H{
+		"matches":            len(matches),
+		"double_round_robin": req.DoubleRoundRobin,
+		"replace":            req.Replace,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("%d group matches created", len(matches)),
+		"data":    matches,
+	})
+}
+
+// GenerateBracket creates a seeded single-elimination bracket. Winners are moved
+// on to their next match as results are entered.
+func (m *Module) GenerateBracket(c *gin.Context) {
+	tournament, ok := m.tournament(c)
+	if !ok {
+		return
+	}
+
+	var req GenerateFixturesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+	schedule, msg := fixtureSchedule(req)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": msg})
+		return
+	}
+
+	var teams []domain.Team
+	if len(req.TeamIDs) == 0 {
+		if err := m.DB.Where("tournament_id = ?", tournament.ID).Order("seed NULLS LAST, name").Find(&teams).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch teams"})
+			return
+		}
+	} else {
+		var msg string
+		if teams, msg = m.tournamentTeams(tournament.ID, req.TeamIDs); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": msg})
+			return
+		}
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	var matches []domain.Match
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		matches, err = fixtures.GenerateBracket(tx, tournament.ID, teams, schedule, req.Replace, userID)
+		return err
+	})
+	if err != nil {
+		fixturesError(c, err)
+		return
+	}
+
+	m.audit(c, "bracket_generate", "tournament", &tournament.ID, gin.H{
+		"teams":   len(teams),
+		"matches": len(matches),
+		"replace": req.Replace,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("%d knockout matches created", len(matches)),
+		"data":    matches,
+	})
+}
+
+// tournament loads the tournament in the tournamentId path parameter, writing
+// the error response if it can't
+func (m *Module) tournament(c *gin.Context) (*domain.Tournament, bool) {
+	tid, err := uuid.Parse(c.Param("tournamentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid tournament ID"})
+		return nil, false
+	}
+	var tournament domain.Tournament
+	if err := m.DB.First(&tournament, "id = ?", tid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Tournament not found"})
+		return nil, false
+	}
+	return &tournament, true
+}
+
+// matchTeam finds the tournament team for one side of a match, by ID or else by
+// name. A name that isn't a team yet is entered as one, linked to the academy of
+// the same name if there is one. It returns nil when neither is given, and writes
+// the error response if the team can't be used.
+func (m *Module) matchTeam(c *gin.Context, tournamentID uuid.UUID, side, id, name string) (*domain.Team, bool) {
+	var team domain.Team
+	if id != "" {
+		tid, err := uuid.Parse(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": fmt.Sprintf("Invalid %s team ID", side)})
+			return nil, false
+		}
+		if err := m.DB.First(&team, "id = ? AND tournament_id = ?", tid, tournamentID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": fmt.Sprintf("The %s team is not in this tournament", side)})
+			return nil, false
+		}
+		return &team, true
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, true
+	}
+	err := m.DB.Where("tournament_id = ? AND LOWER(name) = LOWER(?)", tournamentID, name).First(&team).Error
+	if err == nil {
+		return &team, true
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to look up team"})
+		return nil, false
+	}
+	team = domain.Team{TournamentID: tournamentID, Name: name}
+	var academy domain.Academy
+	if m.DB.Where("LOWER(name) = LOWER(?)", name).First(&academy).Error == nil {
+		team.AcademyID = &academy.ID
+	}
+	if err := m.DB.Create(&team).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to create team"})
+		return nil, false
+	}
+	return &team, true
+}
+
+// matchSide checks that a team plays in the match, writing the error response if
+// not. An empty ID gives nil.
+func (m *Module) matchSide(c *gin.Context, match *domain.Match, id string) (*uuid.UUID, bool) {
+	if id == "" {
+		return nil, true
+	}
+	tid, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid team ID"})
+		return nil, false
+	}
+	if (match.HomeTeamID == nil || *match.HomeTeamID != tid) && (match.AwayTeamID == nil || *match.AwayTeamID != tid) {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "The team is not playing in this match"})
+		return nil, false
+	}
+	return &tid, true
+}
+
+// academySide returns the side of the match from the academy, or nil unless
+// exactly one side is
+func (m *Module) academySide(match *domain.Match, academyID *uuid.UUID) *uuid.UUID {
+	if academyID == nil || (match.HomeTeamID == nil && match.AwayTeamID == nil) {
+		return nil
+	}
+	var teams []domain.Team
+	m.DB.Where("id IN ? AND academy_id = ?", []*uuid.UUID{match.HomeTeamID, match.AwayTeamID}, *academyID).Find(&teams)
+	if len(teams) != 1 {
+		return nil
+	}
+	return &teams[0].ID
+}
+
+// applyTeamRequest copies the request onto the team, returning a message if it's
+// invalid
+func (m *Module) applyTeamRequest(team *domain.Team, req TeamRequest) string {
+	if req.AcademyID != nil {
+		team.AcademyID = nil
+		if *req.AcademyID != "" {
+			aid, err := uuid.Parse(*req.AcademyID)
+			if err != nil {
+				return "Invalid academy ID"
+			}
+			var academy domain.Academy
+			if err := m.DB.First(&academy, "id = ?", aid).Error; err != nil {
+				return "Academy not found"
+			}
+			team.AcademyID = &aid
+			if team.Name == "" && req.Name == nil {
+				team.Name = academy.Name
+			}
+		}
+	}
+	if req.GroupID != nil {
+		team.GroupID = nil
+		if *req.GroupID != "" {
+			gid, err := uuid.Parse(*req.GroupID)
+			if err != nil {
+				return "Invalid group ID"
+			}
+			var count int64
+			m.DB.Model(&domain.TournamentGroup{}).Where("id = ? AND tournament_id = ?", gid, team.TournamentID).Count(&count)
+			if count == 0 {
+				return "Group not found in this tournament"
+			}
+			team.GroupID = &gid
+		}
+	}
+	if req.Name != nil {
+		team.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.ShortName != nil {
+		team.ShortName = stringPtr(strings.TrimSpace(*req.ShortName))
+	}
+	if req.Seed != nil {
+		switch {
+		case *req.Seed < 0:
+			return "Seed must be positive"
+		case *req.Seed == 0:
+			team.Seed = nil
+		default:
+			team.Seed = intPtr(*req.Seed)
+		}
+	}
+	return ""
+}
+
+func (m *Module) teamNameTaken(team domain.Team) bool {
+	var count int64
+	m.DB.Model(&domain.Team{}).Where("tournament_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", team.TournamentID, team.Name, team.ID).Count(&count)
+	return count > 0
+}
+
+// tournamentTeams loads the teams by ID, in the order given, returning a message
+// if any isn't in the tournament
+func (m *Module) tournamentTeams(tournamentID uuid.UUID, ids []string) ([]domain.Team, string) {
+	parsed := make([]uuid.UUID, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for i, id := range ids {
+		tid, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Sprintf("Invalid team ID %q", id)
+		}
+		if seen[tid] {
+			return nil, fmt.Sprintf("Team %s is listed twice", id)
+		}
+		seen[tid] = true
+		parsed[i] = tid
+	}
+
+	var found []domain.Team
+	if err := m.DB.Where("tournament_id = ? AND id IN ?", tournamentID, parsed).Find(&found).Error; err != nil {
+		return nil, "Failed to fetch teams"
+	}
+	byID := make(map[uuid.UUID]domain.Team, len(found))
+	for _, t := range found {
+		byID[t.ID] = t
+	}
+	teams := make([]domain.Team, len(parsed))
+	for i, id := range parsed {
+		t, ok := byID[id]
+		if !ok {
+			return nil, fmt.Sprintf("Team %s not found in this tournament", id)
+		}
+		teams[i] = t
+	}
+	return teams, ""
+}
+
+// saveGroup saves the group and, when teamIDs isn't nil, makes them its teams
+func (m *Module) saveGroup(c *gin.Context, group *domain.TournamentGroup, teamIDs []string, status int, message string) {
+	var teams []domain.Team
+	if teamIDs != nil {
+		var msg string
+		if teams, msg = m.tournamentTeams(group.TournamentID, teamIDs); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": msg})
+			return
+		}
+	}
+
+	err := m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(group).Error; err != nil {
+			return err
+		}
+		if teamIDs == nil {
+			return nil
+		}
+		ids := make([]uuid.UUID, len(teams))
+		for i, t := range teams {
+			ids[i] = t.ID
+		}
+		leaving := tx.Model(&domain.Team{}).Where("group_id = ?", group.ID)
+		if len(ids) > 0 {
+			leaving = leaving.Where("id NOT IN ?", ids)
+		}
+		if err := leaving.Update("group_id", nil).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&domain.Team{}).Where("id IN ?", ids).Update("group_id", group.ID).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to save group"})
+		return
+	}
+	standings.Refresh(m.DB, &group.TournamentID)
+
+	m.DB.Preload("Teams", func(db *gorm.DB) *gorm.DB { return db.Order("seed NULLS LAST, name") }).First(group, "id = ?", group.ID)
+	c.JSON(status, gin.H{
+		"success": true,
+		"message": message,
+		"data":    group,
+	})
+}
+
+// fixtureSchedule reads the schedule from the request, returning a message if
+// it's invalid
+func fixtureSchedule(req GenerateFixturesRequest) (fixtures.Schedule, string) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return fixtures.Schedule{}, "Invalid start date format (use YYYY-MM-DD)"
+	}
+	days := 1
+	if req.DaysBetweenRounds != nil {
+		days = *req.DaysBetweenRounds
+	}
+	if days < 0 {
+		return fixtures.Schedule{}, "days_between_rounds cannot be negative"
+	}
+	return fixtures.Schedule{Start: start, DaysBetweenRounds: days, Location: stringPtr(req.Location)}, ""
+}
+
+func fixturesError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fixtures.ErrFixturesExist), errors.Is(err, fixtures.ErrFixturesStarted):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": err.Error()})
+	case errors.Is(err, fixtures.ErrNoGroups), errors.Is(err, fixtures.ErrNotEnoughTeams):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to generate fixtures"})
+	}
+}
+
+func (m *Module) audit(c *gin.Context, action, resourceType string, resourceID *uuid.UUID, details gin.H) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	ip := c.ClientIP()
+	encoded, _ := json.Marshal(details)
+	detailStr := string(encoded)
+	m.DB.Create(&domain.AuditLog{
+		UserID:       &userID,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Details:      &detailStr,
+		IPAddress:    &ip,
+		CreatedAt:    time.Now(),
+	})
+}
+
+// ==================== HELPERS ====================
+
+// paidVideoURL returns a playable URL for a paid video key and how long it's valid.
+// Full matches only go out as signed CloudFront URLs (plus signed cookies for HLS),
+// otherwise as presigned storage URLs; never as plain CDN links.
+func (m *Module) paidVideoURL(c *gin.Context, key string, ttl time.Duration) (string, time.Duration) {
+	if m.CDN.CanSign() {
+		signed, _, err := m.CDN.SignedURL(key, c.ClientIP())
+		if err != nil {
+			fmt.Printf("Warning: Failed to sign CloudFront URL: %v\n", err)
+			return "", 0
+		}
+
+		// HLS segments are authorized with signed cookies for the stream's directory
+		if cdn.IsHLS(key) {
+			if cookies, err := m.CDN.StreamCookies(key, c.ClientIP()); err == nil {
+				for _, cookie := range cookies {
+					http.SetCookie(c.Writer, cookie)
+				}
+			}
+		}
+		return signed, m.CDN.TTL()
+	}
+
+	presignedURL, err := m.Store.PresignGet(c.Request.Context(), key, ttl)
+	if err != nil {
+		fmt.Printf("Warning: Failed to generate presigned URL: %v\n", err)
+		return "", 0 // Will show error in frontend
+	}
+	return presignedURL, ttl
+}
+
+// hasFullAccess reports whether a user may watch a full match: admins, Scout+
+// subscribers, or a completed purchase of this match (returned when present)
+func (m *Module) hasFullAccess(c *gin.Context, userID, matchVideoID uuid.UUID) (bool, *domain.MatchPurchase) {
+	var purchase domain.MatchPurchase
+	if err := m.DB.Where("user_id = ? AND match_video_id = ? AND status = ?", userID, matchVideoID, "completed").First(&purchase).Error; err == nil {
+		return true, &purchase
+	}
+	if role, _ := c.Get("user_role"); role == "admin" {
+		return true, nil
+	}
+	var sub domain.Subscription
+	if err := m.DB.Where("user_id = ?", userID).First(&sub).Error; err == nil && sub.CanAccessFullMatch() {
+		return true, nil
+	}
+	return false, nil
+}
+
+// readObject reads a small object (e.g. a playlist) from storage
+func (m *Module) readObject(ctx context.Context, key string) ([]byte, error) {
+	r, err := m.Store.Open(ctx, key, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(io.LimitReader(r, 8<<20))
+}
+
+// refreshStats recomputes the cached standings of the match's tournament
+func (m *Module) refreshStats(matchID uuid.UUID) {
+	var match domain.Match
+	if m.DB.Select("tournament_id").First(&match, "id = ?", matchID).Error == nil {
+		standings.Refresh(m.DB, match.TournamentID)
+	}
+}
+
+// requestBaseURL is the scheme and host the client used to reach the API
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
 
 func getMatchIDs(matches []domain.Match) []uuid.UUID {
 	ids := make([]uuid.UUID, len(matches))
@@ -2,71 +2,73 @@ package media
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"github.com/unicorn-sport/backend/internal/access"
+	"github.com/unicorn-sport/backend/internal/cdn"
 	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/privacy"
+	"github.com/unicorn-sport/backend/internal/storage"
+	"github.com/unicorn-sport/backend/internal/uploads"
+	"github.com/unicorn-sport/backend/internal/views"
+	"github.com/unicorn-sport/backend/internal/watermark"
 )
 
 // MediaModule handles video and media operations
 type MediaModule struct {
-	db            *gorm.DB
-	s3Client      *s3.Client
-	s3Bucket      string
-	cloudFrontURL string
-	awsRegion     string
+	db         *gorm.DB
+	store      storage.Store
+	uploads    *uploads.Service
+	watermarks *watermark.Service
+	cdn        *cdn.CloudFront // nil when no CDN is configured
+	views      *views.Tracker
 }
 
 // NewMediaModule creates a new media module
-func NewMediaModule(db *gorm.DB, awsRegion, accessKeyID, secretAccessKey, s3Bucket, cloudFrontURL string) *MediaModule {
-	var s3Client *s3.Client
-
-	if accessKeyID != "" && secretAccessKey != "" {
-		cfg, err := config.LoadDefaultConfig(context.Background(),
-			config.WithRegion(awsRegion),
-			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")),
-		)
-		if err == nil {
-			s3Client = s3.NewFromConfig(cfg)
-		}
-	}
-
+func NewMediaModule(db *gorm.DB, store storage.Store, uploadService *uploads.Service, watermarkService *watermark.Service, cloudFront *cdn.CloudFront, viewTracker *views.Tracker) *MediaModule {
 	return &MediaModule{
-		db:            db,
-		s3Client:      s3Client,
-		s3Bucket:      s3Bucket,
-		cloudFrontURL: cloudFrontURL,
-		awsRegion:     awsRegion,
+		db:         db,
+		store:      store,
+		uploads:    uploadService,
+		watermarks: watermarkService,
+		cdn:        cloudFront,
+		views:      viewTracker,
 	}
 }
 
-// GetS3Client returns the S3 client for use by other modules
-func (m *MediaModule) GetS3Client() *s3.Client {
-	return m.s3Client
-}
-
 // =============================================================================
 // UPLOAD ENDPOINTS
 // =============================================================================
 
+const (
+	// Files above this size are uploaded in parts
+	multipartThreshold = 100 * 1024 * 1024
+	// Multipart sessions live as long as full match uploads in the matches module,
+	// counted from the last part URL or resume
+	multipartUploadExpiry = 24 * time.Hour
+)
+
 // InitUploadRequest initiates a new upload session
 type InitUploadRequest struct {
-	UploadType  string `json:"upload_type" binding:"required,oneof=highlight full_match thumbnail profile_photo cover_image"`
+	UploadType  string `json:"upload_type" binding:"required,oneof=highlight full_match thumbnail profile_photo cover_image academy_logo verification_doc consent_evidence"`
 	FileName    string `json:"file_name" binding:"required"`
 	ContentType string `json:"content_type" binding:"required"`
 	FileSize    int64  `json:"file_size" binding:"required"`
+	// Optional SHA-256 of the whole file (hex or base64), verified after upload
+	ChecksumSHA256 *string `json:"checksum_sha256,omitempty"`
+	// Optional entity the file belongs to (player, academy, video), used to flag infected uploads
+	EntityType *string `json:"entity_type,omitempty" binding:"omitempty,oneof=player academy video"`
+	EntityID   *string `json:"entity_id,omitempty"`
 }
 
 // InitUploadResponse returns upload session details
@@ -118,6 +120,20 @@ func (m *MediaModule) InitUpload(c *gin.Context) {
 		} else {
 			maxSize = 10 * 1024 * 1024 // 10MB for thumbnails
 		}
+	case "academy_logo":
+		allowedTypes = map[string]bool{
+			"image/jpeg": true,
+			"image/png":  true,
+			"image/webp": true,
+		}
+		maxSize = 5 * 1024 * 1024 // 5MB for logos
+	case "verification_doc", "consent_evidence":
+		allowedTypes = map[string]bool{
+			"application/pdf": true,
+			"image/jpeg":      true,
+			"image/png":       true,
+		}
+		maxSize = 10 * 1024 * 1024 // 10MB for ID documents and signed consent forms
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UPLOAD_TYPE", "message": "Invalid upload type"}})
 		return
@@ -151,9 +167,33 @@ func (m *MediaModule) InitUpload(c *gin.Context) {
 		s3Key = fmt.Sprintf("players/photos/%s/%s", sessionID.String(), sanitizeFileName(req.FileName))
 	case "cover_image":
 		s3Key = fmt.Sprintf("covers/%s/%s", sessionID.String(), sanitizeFileName(req.FileName))
+	case "academy_logo":
+		s3Key = fmt.Sprintf("academies/logos/%s/%s", sessionID.String(), sanitizeFileName(req.FileName))
+	case "verification_doc":
+		s3Key = fmt.Sprintf("private/verification/%s/%s", sessionID.String(), sanitizeFileName(req.FileName))
+	case "consent_evidence":
+		s3Key = fmt.Sprintf("private/consents/%s/%s", sessionID.String(), sanitizeFileName(req.FileName))
+	}
+
+	// Documents and images stay quarantined until the malware scan passes
+	if uploads.RequiresScan(req.UploadType) {
+		s3Key = uploads.QuarantineKey(s3Key)
+	}
+
+	var entityID *uuid.UUID
+	if req.EntityID != nil && *req.EntityID != "" {
+		eid, err := uuid.Parse(*req.EntityID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_ENTITY_ID", "message": "Invalid entity ID"}})
+			return
+		}
+		entityID = &eid
 	}
 
 	expiresIn := 3600 // 1 hour
+	if req.FileSize > multipartThreshold {
+		expiresIn = int(multipartUploadExpiry / time.Second)
+	}
 	expiresAt := time.Now().Add(time.Duration(expiresIn) * time.Second)
 
 	// Create upload session record
@@ -167,6 +207,10 @@ func (m *MediaModule) InitUpload(c *gin.Context) {
 		Status:      "pending",
 		UploadedBy:  adminID,
 		ExpiresAt:   expiresAt,
+
+		ChecksumSHA256: req.ChecksumSHA256,
+		EntityType:     req.EntityType,
+		EntityID:       entityID,
 	}
 
 	if err := m.db.Create(&session).Error; err != nil {
@@ -178,30 +222,19 @@ func (m *MediaModule) InitUpload(c *gin.Context) {
 	var uploadURL string
 	uploadMethod := "direct"
 
-	if m.s3Client == nil {
-		// Development mode - return mock URL
-		uploadURL = fmt.Sprintf("http://localhost:9000/%s/%s", m.s3Bucket, s3Key)
+	// For large files (>100MB), use multipart upload
+	if req.FileSize > multipartThreshold {
+		uploadMethod = "multipart"
+		// For multipart, the frontend will request part URLs separately
+		uploadURL = "" // Frontend will call GetUploadPartURL for each part
 	} else {
-		// For large files (>100MB), use multipart upload
-		if req.FileSize > 100*1024*1024 {
-			uploadMethod = "multipart"
-			// For multipart, the frontend will request part URLs separately
-			uploadURL = "" // Frontend will call GetUploadPartURL for each part
-		} else {
-			// Direct upload with presigned PUT
-			presignClient := s3.NewPresignClient(m.s3Client)
-			presignedReq, err := presignClient.PresignPutObject(context.Background(), &s3.PutObjectInput{
-				Bucket:        aws.String(m.s3Bucket),
-				Key:           aws.String(s3Key),
-				ContentType:   aws.String(req.ContentType),
-				ContentLength: aws.Int64(req.FileSize),
-			}, s3.WithPresignExpires(time.Duration(expiresIn)*time.Second))
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "PRESIGN_FAILED", "message": "Failed to generate upload URL"}})
-				return
-			}
-			uploadURL = presignedReq.URL
+		// Direct upload with presigned PUT
+		presignedURL, err := m.store.PresignPut(c.Request.Context(), s3Key, req.ContentType, req.FileSize, time.Duration(expiresIn)*time.Second)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "PRESIGN_FAILED", "message": "Failed to generate upload URL"}})
+			return
 		}
+		uploadURL = presignedURL
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -247,17 +280,8 @@ func (m *MediaModule) InitMultipart(c *gin.Context) {
 		return
 	}
 
-	if m.s3Client == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "error": gin.H{"code": "S3_NOT_CONFIGURED", "message": "S3 is not configured"}})
-		return
-	}
-
 	// Create multipart upload
-	createResp, err := m.s3Client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
-		Bucket:      aws.String(m.s3Bucket),
-		Key:         aws.String(session.S3Key),
-		ContentType: aws.String(session.ContentType),
-	})
+	uploadID, err := m.store.CreateMultipartUpload(c.Request.Context(), session.S3Key, session.ContentType)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "MULTIPART_INIT_FAILED", "message": "Failed to initialize multipart upload"}})
 		return
@@ -268,10 +292,10 @@ func (m *MediaModule) InitMultipart(c *gin.Context) {
 	totalParts := int((session.FileSize + partSize - 1) / partSize)
 
 	// Update session
-	uploadID := *createResp.UploadId
 	session.S3UploadID = &uploadID
 	session.PartsTotal = &totalParts
 	session.Status = "uploading"
+	session.ExpiresAt = time.Now().Add(multipartUploadExpiry)
 	m.db.Save(&session)
 
 	c.JSON(http.StatusOK, gin.H{
@@ -310,23 +334,22 @@ func (m *MediaModule) GetUploadPartURL(c *gin.Context) {
 		return
 	}
 
-	presignClient := s3.NewPresignClient(m.s3Client)
-	presignedReq, err := presignClient.PresignUploadPart(context.Background(), &s3.UploadPartInput{
-		Bucket:     aws.String(m.s3Bucket),
-		Key:        aws.String(session.S3Key),
-		UploadId:   session.S3UploadID,
-		PartNumber: aws.Int32(int32(req.PartNumber)),
-	}, s3.WithPresignExpires(15*time.Minute))
+	if session.Status != "pending" && session.Status != "uploading" {
+		c.JSON(http.StatusGone, gin.H{"success": false, "error": gin.H{"code": "SESSION_EXPIRED", "message": "Upload session is no longer active"}})
+		return
+	}
 
+	uploadURL, err := m.store.PresignUploadPart(c.Request.Context(), session.S3Key, *session.S3UploadID, req.PartNumber, 15*time.Minute)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "PRESIGN_FAILED", "message": "Failed to generate part URL"}})
 		return
 	}
+	m.extendUpload(&session)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"upload_url":  presignedReq.URL,
+			"upload_url":  uploadURL,
 			"part_number": req.PartNumber,
 			"expires_in":  900, // 15 minutes
 		},
@@ -340,8 +363,9 @@ type CompleteMultipartRequest struct {
 }
 
 type CompletedPartInfo struct {
-	PartNumber int    `json:"part_number"`
-	ETag       string `json:"etag"`
+	PartNumber     int    `json:"part_number"`
+	ETag           string `json:"etag"`
+	ChecksumSHA256 string `json:"checksum_sha256,omitempty"` // optional per-part digest
 }
 
 // CompleteMultipart completes the multipart upload
@@ -359,45 +383,259 @@ func (m *MediaModule) CompleteMultipart(c *gin.Context) {
 		return
 	}
 
+	if session.S3UploadID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "MULTIPART_NOT_STARTED", "message": "Multipart upload not initialized"}})
+		return
+	}
+
 	// Build completed parts list
-	var completedParts []types.CompletedPart
+	var completedParts []storage.CompletedPart
+	digests := map[int]string{}
 	for _, p := range req.Parts {
-		completedParts = append(completedParts, types.CompletedPart{
-			PartNumber: aws.Int32(int32(p.PartNumber)),
-			ETag:       aws.String(p.ETag),
+		completedParts = append(completedParts, storage.CompletedPart{
+			PartNumber: p.PartNumber,
+			ETag:       p.ETag,
 		})
+		if p.ChecksumSHA256 != "" {
+			digests[p.PartNumber] = p.ChecksumSHA256
+		}
+	}
+
+	// Part sizes are only listable before completion
+	partChecksums, err := m.uploads.PartChecksums(c.Request.Context(), &session, completedParts, digests)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "LIST_PARTS_FAILED", "message": "Failed to read uploaded parts"}})
+		return
 	}
 
 	// Complete multipart upload
-	_, err := m.s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
-		Bucket:   aws.String(m.s3Bucket),
-		Key:      aws.String(session.S3Key),
-		UploadId: session.S3UploadID,
-		MultipartUpload: &types.CompletedMultipartUpload{
-			Parts: completedParts,
-		},
-	})
+	err = m.store.CompleteMultipartUpload(c.Request.Context(), session.S3Key, *session.S3UploadID, completedParts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "COMPLETE_FAILED", "message": "Failed to complete upload"}})
 		return
 	}
 
-	// Update session
+	// Record assembly before verifying so a verification retry doesn't need the parts
 	now := time.Now()
-	session.Status = "completed"
 	session.CompletedAt = &now
 	m.db.Save(&session)
 
+	if !m.verifyUpload(c, &session, partChecksums) {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"session_id": session.ID,
-			"s3_key":     session.S3Key,
-			"s3_url":     fmt.Sprintf("s3://%s/%s", m.s3Bucket, session.S3Key),
+			"session_id":            session.ID,
+			"s3_key":                session.S3Key,
+			"detected_content_type": session.DetectedContentType,
+			"s3_url":                storage.ObjectURL(m.store, session.S3Key),
 		},
 	})
 }
 
+// CompleteUploadRequest confirms a direct (single PUT) upload
+type CompleteUploadRequest struct {
+	SessionID string `json:"session_id" binding:"required"`
+}
+
+// CompleteUpload verifies a direct upload and marks its session completed
+func (m *MediaModule) CompleteUpload(c *gin.Context) {
+	var req CompleteUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	sessionID, err := uuid.Parse(req.SessionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_SESSION_ID", "message": "Invalid session ID"}})
+		return
+	}
+
+	var session domain.UploadSession
+	if err := m.db.First(&session, "id = ?", sessionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "SESSION_NOT_FOUND", "message": "Upload session not found"}})
+		return
+	}
+
+	if session.S3UploadID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "USE_MULTIPART_COMPLETE", "message": "Multipart uploads are completed via /upload/multipart/complete"}})
+		return
+	}
+
+	if session.Status != "pending" && session.Status != "uploading" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_SESSION_STATE", "message": "Upload already completed or no longer active"}})
+		return
+	}
+
+	if !m.verifyUpload(c, &session, nil) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"session_id":            session.ID,
+			"s3_key":                session.S3Key,
+			"detected_content_type": session.DetectedContentType,
+			"s3_url":                storage.ObjectURL(m.store, session.S3Key),
+		},
+	})
+}
+
+// verifyUpload runs integrity checks on a stored upload and writes the error
+// response itself when they fail. Returns true when the session is completed.
+func (m *MediaModule) verifyUpload(c *gin.Context, session *domain.UploadSession, parts []storage.PartChecksum) bool {
+	err := m.uploads.Verify(c.Request.Context(), session, parts)
+	if err == nil {
+		return true
+	}
+
+	var mismatch *storage.MismatchError
+	if errors.As(err, &mismatch) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"code": "UPLOAD_VERIFICATION_FAILED", "message": mismatch.Reason}})
+		return false
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "VERIFICATION_ERROR", "message": "Failed to verify upload"}})
+	return false
+}
+
+// =============================================================================
+// UPLOAD RESUME & JANITOR
+// =============================================================================
+
+// ListUploadParts returns the parts already stored for a multipart upload so the
+// uploader can resume from the last confirmed part instead of starting over
+func (m *MediaModule) ListUploadParts(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_SESSION_ID", "message": "Invalid session ID"}})
+		return
+	}
+
+	var session domain.UploadSession
+	if err := m.db.First(&session, "id = ?", sessionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "SESSION_NOT_FOUND", "message": "Upload session not found"}})
+		return
+	}
+
+	if session.S3UploadID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "MULTIPART_NOT_STARTED", "message": "Multipart upload not initialized"}})
+		return
+	}
+
+	if session.Status == "expired" || session.Status == "failed" {
+		c.JSON(http.StatusGone, gin.H{"success": false, "error": gin.H{"code": "SESSION_EXPIRED", "message": "Upload session is no longer resumable"}})
+		return
+	}
+
+	parts, err := m.store.ListParts(c.Request.Context(), session.S3Key, *session.S3UploadID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusGone, gin.H{"success": false, "error": gin.H{"code": "UPLOAD_NOT_FOUND", "message": "Multipart upload no longer exists"}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "LIST_PARTS_FAILED", "message": "Failed to list uploaded parts"}})
+		return
+	}
+	if parts == nil {
+		parts = []storage.UploadedPart{}
+	}
+	if session.Status == "pending" || session.Status == "uploading" {
+		m.extendUpload(&session)
+	}
+
+	// Next part is the first gap, so out-of-order uploads resume correctly
+	var uploadedBytes int64
+	nextPart := 1
+	for _, p := range parts {
+		uploadedBytes += p.Size
+		if p.PartNumber == nextPart {
+			nextPart++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"session_id":       session.ID,
+			"status":           session.Status,
+			"s3_key":           session.S3Key,
+			"file_size":        session.FileSize,
+			"parts_total":      session.PartsTotal,
+			"parts":            parts,
+			"uploaded_bytes":   uploadedBytes,
+			"next_part_number": nextPart,
+			"expires_at":       session.ExpiresAt,
+		},
+	})
+}
+
+// extendUpload pushes back an active multipart session's expiry, so the janitor
+// only aborts uploads that have stopped making progress
+func (m *MediaModule) extendUpload(session *domain.UploadSession) {
+	session.ExpiresAt = time.Now().Add(multipartUploadExpiry)
+	if err := m.db.Model(&domain.UploadSession{}).Where("id = ?", session.ID).
+		Update("expires_at", session.ExpiresAt).Error; err != nil {
+		log.Printf("upload: failed to extend session %s: %v", session.ID, err)
+	}
+}
+
+// ExpireStaleUploads marks pending/uploading sessions past ExpiresAt as expired and
+// aborts their multipart uploads so orphaned parts stop accruing storage costs
+func (m *MediaModule) ExpireStaleUploads(ctx context.Context) (int, error) {
+	var sessions []domain.UploadSession
+	if err := m.db.WithContext(ctx).
+		Where("status IN ? AND expires_at < ? AND completed_at IS NULL", []string{"pending", "uploading"}, time.Now()).
+		Find(&sessions).Error; err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, session := range sessions {
+		if session.S3UploadID != nil {
+			err := m.store.AbortMultipartUpload(ctx, session.S3Key, *session.S3UploadID)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				// Leave the session as-is so the next run retries the abort
+				log.Printf("upload janitor: failed to abort upload for session %s: %v", session.ID, err)
+				continue
+			}
+		}
+
+		if err := m.db.WithContext(ctx).Model(&domain.UploadSession{}).
+			Where("id = ? AND status IN ?", session.ID, []string{"pending", "uploading"}).
+			Update("status", "expired").Error; err != nil {
+			log.Printf("upload janitor: failed to expire session %s: %v", session.ID, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// RunUploadJanitor expires stale upload sessions every interval until ctx is cancelled
+func (m *MediaModule) RunUploadJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := m.ExpireStaleUploads(ctx); err != nil {
+			log.Printf("upload janitor: %v", err)
+		} else if n > 0 {
+			log.Printf("upload janitor: expired %d stale upload sessions", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // =============================================================================
 // VIDEO CRUD ENDPOINTS
 // =============================================================================
@@ -433,11 +671,21 @@ func (m *MediaModule) CreateVideo(c *gin.Context) {
 	if req.SessionID != nil && *req.SessionID != "" {
 		sessionID, _ := uuid.Parse(*req.SessionID)
 		var session domain.UploadSession
-		if err := m.db.First(&session, "id = ? AND status = 'completed'", sessionID).Error; err != nil {
+		if err := m.db.First(&session, "id = ?", sessionID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_SESSION", "message": "Upload session not found or not completed"}})
+			return
+		}
+		// Direct uploads may skip the complete call, so verify them here
+		if session.Status == "pending" && session.S3UploadID == nil {
+			if !m.verifyUpload(c, &session, nil) {
+				return
+			}
+		}
+		if session.Status != "completed" {
 			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_SESSION", "message": "Upload session not found or not completed"}})
 			return
 		}
-		blobURL = fmt.Sprintf("s3://%s/%s", m.s3Bucket, session.S3Key)
+		blobURL = storage.ObjectURL(m.store, session.S3Key)
 		fileSize = &session.FileSize
 	} else if req.BlobURL != nil && *req.BlobURL != "" {
 		blobURL = *req.BlobURL
@@ -497,7 +745,7 @@ func (m *MediaModule) CreateVideo(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
-		"data":    m.videoToResponse(video),
+		"data":    m.videoToResponse(c, video, privacy.For(c, m.db)),
 	})
 }
 
@@ -518,16 +766,27 @@ type VideoResponse struct {
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
 	Players         []PlayerSummary `json:"players,omitempty"`
+
+	// Set for users without full-match access: no video URL, only a free preview
+	Locked     bool    `json:"locked,omitempty"`
+	PreviewURL *string `json:"preview_url,omitempty"`
+
+	// Admin-only: uploads for this video that failed the malware scan
+	HasInfectedUpload bool                   `json:"has_infected_upload,omitempty"`
+	InfectedUploads   []domain.UploadSession `json:"infected_uploads,omitempty"`
 }
 
 type PlayerSummary struct {
-	ID        uuid.UUID `json:"id"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Position  string    `json:"position"`
+	ID           uuid.UUID `json:"id"`
+	FirstName    string    `json:"first_name"`
+	LastName     *string   `json:"last_name,omitempty"` // masked by the privacy policy
+	LastNameInit string    `json:"last_name_init"`
+	Position     string    `json:"position"`
 }
 
-func (m *MediaModule) videoToResponse(v domain.Video) VideoResponse {
+// videoToResponse serializes a video for the requester; signed full-match URLs are
+// bound to their IP like those from GetVideoStreamURL
+func (m *MediaModule) videoToResponse(c *gin.Context, v domain.Video, policy *privacy.Policy) VideoResponse {
 	resp := VideoResponse{
 		ID:              v.ID,
 		VideoType:       v.VideoType,
@@ -544,29 +803,21 @@ func (m *MediaModule) videoToResponse(v domain.Video) VideoResponse {
 		UpdatedAt:       v.UpdatedAt,
 	}
 
-	// Generate video URL - prefer CloudFront, fallback to presigned S3 URL
-	if m.cloudFrontURL != "" {
-		resp.VideoURL = fmt.Sprintf("%s/%s", m.cloudFrontURL, strings.TrimPrefix(v.BlobURL, fmt.Sprintf("s3://%s/", m.s3Bucket)))
-	} else if v.BlobURL != "" && m.s3Client != nil {
-		// Generate presigned URL for direct S3 access (1 hour expiry)
-		s3Key := strings.TrimPrefix(v.BlobURL, fmt.Sprintf("s3://%s/", m.s3Bucket))
-		presignClient := s3.NewPresignClient(m.s3Client)
-		presignedReq, err := presignClient.PresignGetObject(context.Background(), &s3.GetObjectInput{
-			Bucket: aws.String(m.s3Bucket),
-			Key:    aws.String(s3Key),
-		}, s3.WithPresignExpires(1*time.Hour))
-		if err == nil {
-			resp.VideoURL = presignedReq.URL
-		}
+	// Generate video URL - CloudFront (signed for full matches), fallback to presigned storage URL
+	if v.BlobURL != "" {
+		resp.VideoURL, _ = m.playbackURL(c.Request.Context(), v, c.ClientIP(), 1*time.Hour)
 	}
 
 	// Map players
+	policy.Load(v.Players)
 	for _, p := range v.Players {
+		masked := policy.Mask(&p)
 		resp.Players = append(resp.Players, PlayerSummary{
-			ID:        p.ID,
-			FirstName: p.FirstName,
-			LastName:  p.LastName,
-			Position:  p.Position,
+			ID:           p.ID,
+			FirstName:    p.FirstName,
+			LastName:     masked.LastName,
+			LastNameInit: masked.LastNameInit,
+			Position:     p.Position,
 		})
 	}
 
@@ -609,10 +860,20 @@ func (m *MediaModule) ListVideos(c *gin.Context) {
 	query.Preload("Players").Preload("Tournament").
 		Offset(offset).Limit(limit).Order("created_at DESC").Find(&videos)
 
+	// Flag videos with uploads that failed the malware scan
+	videoIDs := make([]uuid.UUID, 0, len(videos))
+	for _, v := range videos {
+		videoIDs = append(videoIDs, v.ID)
+	}
+	infected, _ := m.uploads.InfectedEntityIDs(c.Request.Context(), "video", videoIDs)
+
 	// Convert to response format
+	policy := privacy.For(c, m.db)
 	var responses []VideoResponse
 	for _, v := range videos {
-		responses = append(responses, m.videoToResponse(v))
+		resp := m.videoToResponse(c, v, policy)
+		resp.HasInfectedUpload = infected[v.ID]
+		responses = append(responses, resp)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -644,9 +905,13 @@ func (m *MediaModule) GetVideo(c *gin.Context) {
 		return
 	}
 
+	resp := m.videoToResponse(c, video, privacy.For(c, m.db))
+	resp.InfectedUploads, _ = m.uploads.InfectedUploads(c.Request.Context(), "video", video.ID)
+	resp.HasInfectedUpload = len(resp.InfectedUploads) > 0
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    m.videoToResponse(video),
+		"data":    resp,
 	})
 }
 
@@ -732,7 +997,7 @@ func (m *MediaModule) UpdateVideo(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    m.videoToResponse(video),
+		"data":    m.videoToResponse(c, video, privacy.For(c, m.db)),
 	})
 }
 
@@ -840,6 +1105,59 @@ func (m *MediaModule) RejectVideo(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Video rejected"})
 }
 
+// SetWatermarkRenditions points a full-match video at its A/B HLS renditions under
+// a storage prefix; an empty prefix turns forensic watermarking off
+func (m *MediaModule) SetWatermarkRenditions(c *gin.Context) {
+	vid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid video ID"}})
+		return
+	}
+
+	var req struct {
+		Prefix string `json:"prefix"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	var video domain.Video
+	if err := m.db.First(&video, "id = ?", vid).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Video not found"}})
+		return
+	}
+	if video.VideoType != "full_match" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "NOT_FULL_MATCH", "message": "Only full matches are watermarked"}})
+		return
+	}
+
+	prefix := strings.Trim(req.Prefix, "/")
+	if prefix == "" {
+		video.WatermarkPrefix = nil
+	} else {
+		if err := watermark.CheckRenditions(c.Request.Context(), m.store, prefix); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"code": "INVALID_RENDITIONS", "message": "Invalid watermark renditions: " + err.Error()}})
+			return
+		}
+		video.WatermarkPrefix = &prefix
+	}
+
+	if err := m.db.Model(&video).Update("watermark_prefix", video.WatermarkPrefix).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update video"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Watermark renditions updated",
+		"data": gin.H{
+			"video_id":         video.ID,
+			"watermark_prefix": video.WatermarkPrefix,
+		},
+	})
+}
+
 // GetVideoStats returns video statistics
 func (m *MediaModule) GetVideoStats(c *gin.Context) {
 	var total, pending, approved, rejected, highlights, fullMatches int64
@@ -898,39 +1216,142 @@ func (m *MediaModule) GetVideoStreamURL(c *gin.Context) {
 		}
 	}
 
-	// Generate streaming URL
+	// Full matches with A/B renditions are served through a per-session playlist
+	// instead of a URL to the video itself
+	forensic := video.VideoType == "full_match" && video.WatermarkPrefix != nil
+
 	var streamURL string
-	if m.cloudFrontURL != "" {
-		// Use CloudFront
-		key := strings.TrimPrefix(video.BlobURL, fmt.Sprintf("s3://%s/", m.s3Bucket))
-		streamURL = fmt.Sprintf("%s/%s", m.cloudFrontURL, key)
-	} else if m.s3Client != nil {
-		// Generate presigned GET URL
-		presignClient := s3.NewPresignClient(m.s3Client)
-		key := strings.TrimPrefix(video.BlobURL, fmt.Sprintf("s3://%s/", m.s3Bucket))
-		presignedReq, err := presignClient.PresignGetObject(context.Background(), &s3.GetObjectInput{
-			Bucket: aws.String(m.s3Bucket),
-			Key:    aws.String(key),
-		}, s3.WithPresignExpires(4*time.Hour))
-		if err == nil {
-			streamURL = presignedReq.URL
+	var expiresIn time.Duration
+	if forensic {
+		expiresIn = 4 * time.Hour
+		if m.cdn.CanSign() {
+			expiresIn = m.cdn.TTL()
 		}
 	} else {
-		streamURL = video.BlobURL
+		streamURL, expiresIn = m.playbackURL(c.Request.Context(), video, c.ClientIP(), 4*time.Hour)
+
+		// HLS players fetch variant playlists and segments themselves, so paid streams
+		// also get signed cookies covering the stream's directory
+		if key, ok := storage.KeyFromURL(video.BlobURL); ok && video.VideoType == "full_match" && m.cdn.CanSign() && cdn.IsHLS(key) {
+			cookies, err := m.cdn.StreamCookies(key, c.ClientIP())
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "SIGNING_FAILED", "message": "Failed to authorize stream"}})
+				return
+			}
+			for _, cookie := range cookies {
+				http.SetCookie(c.Writer, cookie)
+			}
+		}
+	}
+
+	// Full matches carry a per-session watermark code for the player to overlay,
+	// and with A/B renditions a per-session segment mix, so leaked recordings can
+	// be traced back to the account
+	var watermarkInfo gin.H
+	if video.VideoType == "full_match" {
+		userID, _ := c.Get("user_id")
+		ttl := expiresIn
+		if ttl == 0 {
+			ttl = 4 * time.Hour
+		}
+		session, err := m.watermarks.StartSession(c.Request.Context(), userID.(uuid.UUID), "video", video.ID, forensic, c.ClientIP(), c.Request.UserAgent(), ttl)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "PLAYBACK_FAILED", "message": "Failed to start playback"}})
+			return
+		}
+		watermarkInfo = gin.H{"code": session.WatermarkCode, "forensic": forensic}
+		if forensic {
+			streamURL = fmt.Sprintf("%s/api/v1/videos/%s/stream/%s/%s", requestBaseURL(c), video.ID, session.ID, watermark.PlaylistName)
+		}
+
+		access.RecordQuery(m.db, c, access.ActionStreamMatch, "video", &video.ID,
+			m.db.Model(&domain.PlayerVideo{}).Select("player_id").Where("video_id = ?", video.ID))
 	}
 
 	// Track view
-	go m.trackVideoView(vid, c)
+	m.trackVideoView(c, vid)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
 			"stream_url": streamURL,
-			"expires_in": 14400, // 4 hours
+			"expires_in": int(expiresIn.Seconds()), // 0 = public URL, doesn't expire
+			"watermark":  watermarkInfo,
 		},
 	})
 }
 
+// GetWatermarkedPlaylist serves the HLS media playlist for one forensic playback
+// session of a full match. Like the match equivalent it's public, as native HLS
+// players can't send auth headers; the session ID is unguessable and expires.
+func (m *MediaModule) GetWatermarkedPlaylist(c *gin.Context) {
+	vid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid video ID"}})
+		return
+	}
+	sid, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_SESSION_ID", "message": "Invalid session ID"}})
+		return
+	}
+
+	session, err := m.watermarks.ActiveSession(c.Request.Context(), sid)
+	if errors.Is(err, watermark.ErrSessionExpired) {
+		c.JSON(http.StatusGone, gin.H{"success": false, "error": gin.H{"code": "SESSION_EXPIRED", "message": "Playback session expired"}})
+		return
+	}
+	if err != nil || session.AssetType != "video" || session.AssetID != vid || !session.Forensic {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "SESSION_NOT_FOUND", "message": "Playback session not found"}})
+		return
+	}
+
+	var video domain.Video
+	if err := m.db.First(&video, "id = ?", vid).Error; err != nil || video.WatermarkPrefix == nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Video not found"}})
+		return
+	}
+
+	playlist, err := watermark.SessionPlaylist(c.Request.Context(), m.store, m.cdn, *video.WatermarkPrefix, session, c.ClientIP())
+	if err != nil {
+		log.Printf("media: failed to build watermarked playlist for video %s: %v", vid, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "PLAYLIST_FAILED", "message": "Failed to build playlist"}})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "application/vnd.apple.mpegurl", playlist)
+}
+
+// playbackURL returns a playable URL for a video and how long it stays valid. Full
+// matches are paid content, so they only go out through CloudFront as signed,
+// expiring (optionally IP-bound) URLs; without a signing key they fall back to a
+// presigned storage URL rather than a shareable CDN link. Highlights stay public.
+func (m *MediaModule) playbackURL(ctx context.Context, v domain.Video, clientIP string, ttl time.Duration) (string, time.Duration) {
+	key, ok := storage.KeyFromURL(v.BlobURL)
+	if !ok {
+		return v.BlobURL, 0
+	}
+
+	if v.VideoType == "full_match" {
+		if m.cdn.CanSign() {
+			signed, _, err := m.cdn.SignedURL(key, clientIP)
+			if err == nil {
+				return signed, m.cdn.TTL()
+			}
+			log.Printf("media: failed to sign CloudFront URL for video %s: %v", v.ID, err)
+		}
+	} else if m.cdn.Enabled() {
+		return m.cdn.URL(key), 0
+	}
+
+	presigned, err := m.store.PresignGet(ctx, key, ttl)
+	if err != nil {
+		return v.BlobURL, 0
+	}
+	return presigned, ttl
+}
+
 // =============================================================================
 // PUBLIC ENDPOINTS
 // =============================================================================
@@ -962,9 +1383,10 @@ func (m *MediaModule) ListHighlights(c *gin.Context) {
 	query.Preload("Players").Preload("Tournament").
 		Offset(offset).Limit(limit).Order("created_at DESC").Find(&videos)
 
+	policy := privacy.For(c, m.db)
 	var responses []VideoResponse
 	for _, v := range videos {
-		responses = append(responses, m.videoToResponse(v))
+		responses = append(responses, m.videoToResponse(c, v, policy))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -981,23 +1403,14 @@ func (m *MediaModule) ListHighlights(c *gin.Context) {
 	})
 }
 
-// ListFullMatches lists full match videos (Scout+ tier required)
+// ListFullMatches lists full match videos (playable with Scout+ tier, locked previews otherwise)
 func (m *MediaModule) ListFullMatches(c *gin.Context) {
-	// Check subscription tier
+	// Check subscription tier. Users without full-match access still see the
+	// catalogue, locked, with a link to each match's free preview.
 	userID, _ := c.Get("user_id")
 
 	var sub domain.Subscription
-	if err := m.db.Where("user_id = ?", userID).First(&sub).Error; err != nil || !sub.CanAccessFullMatch() {
-		c.JSON(http.StatusPaymentRequired, gin.H{
-			"success": false,
-			"error": gin.H{
-				"code":        "SUBSCRIPTION_REQUIRED",
-				"message":     "Upgrade to Scout tier to access full matches",
-				"upgrade_url": "/subscribe",
-			},
-		})
-		return
-	}
+	locked := m.db.Where("user_id = ?", userID).First(&sub).Error != nil || !sub.CanAccessFullMatch()
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
@@ -1024,22 +1437,40 @@ func (m *MediaModule) ListFullMatches(c *gin.Context) {
 	query.Preload("Players").Preload("Tournament").
 		Offset(offset).Limit(limit).Order("created_at DESC").Find(&videos)
 
+	policy := privacy.For(c, m.db)
 	var responses []VideoResponse
 	for _, v := range videos {
-		responses = append(responses, m.videoToResponse(v))
+		if locked {
+			v.BlobURL = ""
+		}
+		resp := m.videoToResponse(c, v, policy)
+		if locked {
+			resp.Locked = true
+			if v.MatchID != nil {
+				previewURL := fmt.Sprintf("/api/v1/matches/%s/preview?source=full_matches_list", *v.MatchID)
+				resp.PreviewURL = &previewURL
+			}
+		}
+		responses = append(responses, resp)
+	}
+
+	data := gin.H{
+		"videos": responses,
+		"locked": locked,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	}
+	if locked {
+		data["upgrade_url"] = "/subscribe"
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data": gin.H{
-			"videos": responses,
-			"pagination": gin.H{
-				"page":        page,
-				"limit":       limit,
-				"total":       total,
-				"total_pages": (total + int64(limit) - 1) / int64(limit),
-			},
-		},
+		"data":    data,
 	})
 }
 
@@ -1047,19 +1478,34 @@ func (m *MediaModule) ListFullMatches(c *gin.Context) {
 // HELPER FUNCTIONS
 // =============================================================================
 
-func (m *MediaModule) trackVideoView(videoID uuid.UUID, c *gin.Context) {
-	var viewerID *uuid.UUID
+// trackVideoView queues a view of a video. Everything is read from the request
+// here; the tracker writes it after the handler has returned.
+func (m *MediaModule) trackVideoView(c *gin.Context, videoID uuid.UUID) {
+	if role, _ := c.Get("user_role"); role == "admin" {
+		return
+	}
+
+	event := views.Event{
+		AssetType: views.AssetVideo,
+		AssetID:   videoID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Source:    c.Query("source"),
+	}
 	if uid, exists := c.Get("user_id"); exists {
 		id := uid.(uuid.UUID)
-		viewerID = &id
+		event.ViewerID = &id
 	}
 
-	view := domain.VideoView{
-		VideoID:   videoID,
-		ViewerID:  viewerID,
-		CreatedAt: time.Now(),
+	// Attribute the view to the video's primary player
+	var playerIDs []uuid.UUID
+	m.db.Model(&domain.PlayerVideo{}).Where("video_id = ?", videoID).
+		Order("is_primary DESC").Limit(1).Pluck("player_id", &playerIDs)
+	if len(playerIDs) > 0 {
+		event.PlayerID = &playerIDs[0]
 	}
-	m.db.Create(&view)
+
+	m.views.Record(event)
 }
 
 func sanitizeFileName(name string) string {
@@ -1175,3 +1621,13 @@ func (m *MediaModule) LinkPlayerToVideo(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Player linked"})
 }
+
+// requestBaseURL is the scheme and host the request came in on, for links back to
+// this API
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -1,71 +1,56 @@
 package profiles
 
 import (
-	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"github.com/unicorn-sport/backend/internal/access"
+	"github.com/unicorn-sport/backend/internal/consent"
 	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/privacy"
+	"github.com/unicorn-sport/backend/internal/registrations"
+	"github.com/unicorn-sport/backend/internal/storage"
+	"github.com/unicorn-sport/backend/internal/uploads"
+	"github.com/unicorn-sport/backend/internal/verification"
+	"github.com/unicorn-sport/backend/internal/views"
 )
 
 // ProfilesModule handles player profile viewing
 type ProfilesModule struct {
-	db       *gorm.DB
-	s3Client *s3.Client
-	s3Bucket string
+	db      *gorm.DB
+	store   storage.Store
+	uploads *uploads.Service
+	views   *views.Tracker
 }
 
 // NewProfilesModule creates a new profiles module
-func NewProfilesModule(db *gorm.DB, s3Client *s3.Client, s3Bucket string) *ProfilesModule {
+func NewProfilesModule(db *gorm.DB, store storage.Store, uploadService *uploads.Service, viewTracker *views.Tracker) *ProfilesModule {
 	return &ProfilesModule{
-		db:       db,
-		s3Client: s3Client,
-		s3Bucket: s3Bucket,
+		db:      db,
+		store:   store,
+		uploads: uploadService,
+		views:   viewTracker,
 	}
 }
 
-// getPresignedURL converts an s3:// URL to a presigned URL
-func (m *ProfilesModule) getPresignedURL(s3URL string) string {
-	if s3URL == "" || !strings.HasPrefix(s3URL, "s3://") {
-		return s3URL
-	}
-
-	// Parse s3://bucket/key format
-	withoutPrefix := strings.TrimPrefix(s3URL, "s3://")
-	parts := strings.SplitN(withoutPrefix, "/", 2)
-	if len(parts) != 2 {
-		return s3URL
-	}
-
-	key := parts[1]
-
-	presignClient := s3.NewPresignClient(m.s3Client)
-	presignedReq, err := presignClient.PresignGetObject(context.Background(), &s3.GetObjectInput{
-		Bucket: aws.String(m.s3Bucket),
-		Key:    aws.String(key),
-	}, s3.WithPresignExpires(time.Hour))
-	if err != nil {
-		return s3URL
-	}
-
-	return presignedReq.URL
-}
-
 // PlayerListResponse is the public player list response
 type PlayerListResponse struct {
 	ID                uuid.UUID `json:"id"`
 	FirstName         string    `json:"first_name"`
-	LastName          string    `json:"last_name"`
+	LastName          *string   `json:"last_name,omitempty"` // masked by the privacy policy
 	LastNameInit      string    `json:"last_name_init"`
-	Age               int       `json:"age"`
+	Age               *int      `json:"age,omitempty"`
+	AgeGroup          string    `json:"age_group,omitempty"`
 	Position          string    `json:"position"`
 	Country           string    `json:"country"`
 	HeightCm          *int      `json:"height_cm,omitempty"`
@@ -82,9 +67,11 @@ type PlayerListResponse struct {
 type FeaturedPlayerResponse struct {
 	ID              uuid.UUID `json:"id"`
 	FirstName       string    `json:"first_name"`
-	LastName        string    `json:"last_name"`
-	DateOfBirth     string    `json:"date_of_birth,omitempty"`
-	Age             int       `json:"age"`
+	LastName        *string   `json:"last_name,omitempty"`
+	LastNameInit    string    `json:"last_name_init"`
+	DateOfBirth     *string   `json:"date_of_birth,omitempty"`
+	Age             *int      `json:"age,omitempty"`
+	AgeGroup        string    `json:"age_group,omitempty"`
 	Position        string    `json:"position"`
 	Country         string    `json:"country"`
 	ProfilePhotoURL *string   `json:"profile_photo_url,omitempty"`
@@ -96,9 +83,10 @@ type FeaturedPlayerResponse struct {
 type PlayerDetailResponse struct {
 	ID              uuid.UUID            `json:"id"`
 	FirstName       string               `json:"first_name"`
-	LastName        string               `json:"last_name"`
+	LastName        *string              `json:"last_name,omitempty"`
 	LastNameInit    string               `json:"last_name_init"`
-	Age             int                  `json:"age"`
+	Age             *int                 `json:"age,omitempty"`
+	AgeGroup        string               `json:"age_group,omitempty"`
 	Position        string               `json:"position"`
 	PreferredFoot   *string              `json:"preferred_foot,omitempty"`
 	HeightCm        *int                 `json:"height_cm,omitempty"`
@@ -108,6 +96,7 @@ type PlayerDetailResponse struct {
 	State           *string              `json:"state,omitempty"`
 	SchoolName      *string              `json:"school_name,omitempty"`
 	TournamentName  *string              `json:"tournament_name,omitempty"`
+	Tournaments     []TournamentEntry    `json:"tournaments"` // every public tournament entered, latest first
 	AcademyID       *uuid.UUID           `json:"academy_id,omitempty"`
 	AcademyName     *string              `json:"academy_name,omitempty"`
 	ProfilePhotoURL *string              `json:"profile_photo_url,omitempty"`
@@ -117,6 +106,15 @@ type PlayerDetailResponse struct {
 	FullMatchVideos []VideoResponse      `json:"full_match_videos"`
 }
 
+// TournamentEntry is a tournament a player entered
+type TournamentEntry struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Year   int       `json:"year"`
+	Squad  *string   `json:"squad,omitempty"`
+	Status string    `json:"status"`
+}
+
 // PlayerStatsResponse contains aggregated player statistics
 // All stats are derived from highlight uploads (1 highlight = 1 stat)
 // This means uploading a "goal" highlight automatically counts as 1 goal
@@ -146,8 +144,10 @@ type PlayerStatsResponse struct {
 type SimilarPlayerResponse struct {
 	ID              uuid.UUID `json:"id"`
 	FirstName       string    `json:"first_name"`
+	LastName        *string   `json:"last_name,omitempty"`
 	LastNameInit    string    `json:"last_name_init"`
-	Age             int       `json:"age"`
+	Age             *int      `json:"age,omitempty"`
+	AgeGroup        string    `json:"age_group,omitempty"`
 	Position        string    `json:"position"`
 	Country         string    `json:"country"`
 	ProfilePhotoURL *string   `json:"profile_photo_url,omitempty"`
@@ -193,7 +193,8 @@ func (m *ProfilesModule) ListPlayers(c *gin.Context) {
 		Preload("Academy").
 		Preload("Highlights").
 		Where("deleted_at IS NULL").
-		Where("verification_status = ?", "verified") // Only show verified players publicly
+		Where("verification_status = ?", "verified").         // Only show verified players publicly
+		Scopes(consent.Published(consent.ProfilePublication)) // Minors need guardian consent
 
 	// Apply filters
 	if position := c.Query("position"); position != "" {
@@ -215,7 +216,7 @@ func (m *ProfilesModule) ListPlayers(c *gin.Context) {
 		}
 	}
 	if tournamentID := c.Query("tournament_id"); tournamentID != "" {
-		query = query.Where("tournament_id = ?", tournamentID)
+		query = query.Scopes(registrations.Entered(tournamentID))
 	}
 	if academyID := c.Query("academy_id"); academyID != "" {
 		query = query.Where("academy_id = ?", academyID)
@@ -225,26 +226,28 @@ func (m *ProfilesModule) ListPlayers(c *gin.Context) {
 	query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&players)
 
 	// Convert to public response
+	policy := privacy.For(c, m.db)
+	policy.Load(players)
 	response := make([]PlayerListResponse, len(players))
 	for i, p := range players {
 		// Convert thumbnail URL to presigned URL if it's an S3 URL
 		var thumbnailURL *string
 		if p.ThumbnailURL != nil && *p.ThumbnailURL != "" {
-			url := m.getPresignedURL(*p.ThumbnailURL)
+			url := storage.SignedURL(m.store, *p.ThumbnailURL)
 			thumbnailURL = &url
 		}
 
 		// Convert profile photo URL to presigned URL if it's an S3 URL
 		var profilePhotoURL *string
 		if p.ProfilePhotoURL != nil && *p.ProfilePhotoURL != "" {
-			url := m.getPresignedURL(*p.ProfilePhotoURL)
+			url := storage.SignedURL(m.store, *p.ProfilePhotoURL)
 			profilePhotoURL = &url
 		}
 
 		// Get video thumbnail from first highlight if available
 		var videoThumbnailURL *string
 		if len(p.Highlights) > 0 && p.Highlights[0].ThumbnailURL != nil && *p.Highlights[0].ThumbnailURL != "" {
-			url := m.getPresignedURL(*p.Highlights[0].ThumbnailURL)
+			url := storage.SignedURL(m.store, *p.Highlights[0].ThumbnailURL)
 			videoThumbnailURL = &url
 		}
 
@@ -254,12 +257,14 @@ func (m *ProfilesModule) ListPlayers(c *gin.Context) {
 			academyName = &p.Academy.Name
 		}
 
+		masked := policy.Mask(&p)
 		response[i] = PlayerListResponse{
 			ID:                p.ID,
 			FirstName:         p.FirstName,
-			LastName:          p.LastName,
-			LastNameInit:      p.GetLastNameInit(),
-			Age:               p.GetAge(),
+			LastName:          masked.LastName,
+			LastNameInit:      masked.LastNameInit,
+			Age:               masked.Age,
+			AgeGroup:          masked.AgeGroup,
 			Position:          p.Position,
 			Country:           p.Country,
 			HeightCm:          p.HeightCm,
@@ -312,6 +317,15 @@ func (m *ProfilesModule) GetPlayer(c *gin.Context) {
 		return
 	}
 
+	// Minors without guardian consent aren't published; admins and the player still see the profile
+	if !m.canViewProfile(c, &player) {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Player not found"}})
+		return
+	}
+
+	m.trackProfileView(c, player)
+	access.Record(m.db, c, access.ActionViewProfile, "player", &player.ID, player)
+
 	// Get subscription tier to determine video access
 	subscriptionTier := "free"
 	if tier, exists := c.Get("subscription_tier"); exists {
@@ -338,25 +352,27 @@ func (m *ProfilesModule) GetPlayer(c *gin.Context) {
 	// Convert profile photo URL to presigned URL
 	var profilePhotoURL *string
 	if player.ProfilePhotoURL != nil && *player.ProfilePhotoURL != "" {
-		url := m.getPresignedURL(*player.ProfilePhotoURL)
+		url := storage.SignedURL(m.store, *player.ProfilePhotoURL)
 		profilePhotoURL = &url
 	}
 
 	// Build response
+	masked := privacy.For(c, m.db).Mask(&player)
 	response := PlayerDetailResponse{
 		ID:              player.ID,
 		FirstName:       player.FirstName,
-		LastName:        player.LastName,
-		LastNameInit:    player.GetLastNameInit(),
-		Age:             player.GetAge(),
+		LastName:        masked.LastName,
+		LastNameInit:    masked.LastNameInit,
+		Age:             masked.Age,
+		AgeGroup:        masked.AgeGroup,
 		Position:        player.Position,
 		PreferredFoot:   player.PreferredFoot,
 		HeightCm:        player.HeightCm,
 		WeightKg:        player.WeightKg,
 		Country:         player.Country,
-		City:            player.City,
-		State:           player.State,
-		SchoolName:      player.SchoolName,
+		City:            masked.City,
+		State:           masked.State,
+		SchoolName:      masked.SchoolName,
 		ProfilePhotoURL: profilePhotoURL,
 		IsVerified:      player.IsVerified(),
 		Stats:           stats,
@@ -368,6 +384,26 @@ func (m *ProfilesModule) GetPlayer(c *gin.Context) {
 		response.TournamentName = &player.Tournament.Name
 	}
 
+	var entries []domain.TournamentRegistration
+	m.db.Preload("Tournament").
+		Joins("JOIN tournaments t ON t.id = tournament_registrations.tournament_id").
+		Where("tournament_registrations.player_id = ? AND tournament_registrations.status <> ? AND t.is_public = ?", player.ID, registrations.StatusWithdrawn, true).
+		Order("t.year DESC, t.start_date DESC").
+		Find(&entries)
+	response.Tournaments = make([]TournamentEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.Tournament == nil {
+			continue
+		}
+		response.Tournaments = append(response.Tournaments, TournamentEntry{
+			ID:     e.TournamentID,
+			Name:   e.Tournament.Name,
+			Year:   e.Tournament.Year,
+			Squad:  e.Squad,
+			Status: e.Status,
+		})
+	}
+
 	if player.Academy != nil && player.Academy.Name != "" {
 		response.AcademyID = &player.Academy.ID
 		response.AcademyName = &player.Academy.Name
@@ -394,6 +430,58 @@ func (m *ProfilesModule) GetPlayer(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": response})
 }
 
+// canViewProfile reports whether the requester may see a player's profile
+func (m *ProfilesModule) canViewProfile(c *gin.Context, player *domain.Player) bool {
+	if role, _ := c.Get("user_role"); role == "admin" {
+		return true
+	}
+	if userID, ok := c.Get("user_id"); ok && player.UserID != nil && *player.UserID == userID.(uuid.UUID) {
+		return true
+	}
+	return consent.Allowed(m.db, player, consent.ProfilePublication)
+}
+
+// trackProfileView queues a view of a player profile. Admins and players viewing
+// their own profile aren't counted. For scouts, only organization type and country
+// are kept for the player, plus identity if the scout has opted in.
+func (m *ProfilesModule) trackProfileView(c *gin.Context, player domain.Player) {
+	role, _ := c.Get("user_role")
+	if role == "admin" {
+		return
+	}
+
+	event := views.Event{
+		AssetType: views.AssetProfile,
+		AssetID:   player.ID,
+		PlayerID:  &player.ID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Source:    c.Query("source"),
+	}
+
+	if userID, ok := c.Get("user_id"); ok {
+		uid := userID.(uuid.UUID)
+		if player.UserID != nil && *player.UserID == uid {
+			return
+		}
+		event.ViewerID = &uid
+
+		viewer := &views.ViewerInfo{}
+		viewer.Role, _ = role.(string)
+		if role == "scout" {
+			var scout domain.Scout
+			if err := m.db.Where("user_id = ?", uid).First(&scout).Error; err == nil {
+				viewer.OrganizationType = scout.OrganizationType
+				viewer.Country = scout.Country
+				viewer.IdentityShared = scout.ShareIdentityWithPlayers
+			}
+		}
+		event.Viewer = viewer
+	}
+
+	m.views.Record(event)
+}
+
 // getPlayerStats aggregates stats from multiple sources:
 // - Matches played/started, minutes from match_players table
 // - Goals/assists derived from player_highlights (each "goal" highlight = 1 goal, etc.)
@@ -496,7 +584,7 @@ func (m *ProfilesModule) GetSimilarPlayers(c *gin.Context) {
 
 	// Get the reference player
 	var player domain.Player
-	if err := m.db.Preload("Academy").First(&player, "id = ? AND deleted_at IS NULL", pid).Error; err != nil {
+	if err := m.db.Preload("Academy").Scopes(consent.Published(consent.ProfilePublication)).First(&player, "id = ? AND deleted_at IS NULL", pid).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Player not found"}})
 		return
 	}
@@ -515,7 +603,7 @@ func (m *ProfilesModule) GetSimilarPlayers(c *gin.Context) {
 	// 1. Same academy (highest priority)
 	if player.AcademyID != nil {
 		var academyPlayers []domain.Player
-		m.db.Preload("Academy").
+		m.db.Preload("Academy").Scopes(consent.Published(consent.ProfilePublication)).
 			Where("id != ? AND academy_id = ? AND deleted_at IS NULL AND verification_status = 'verified'", pid, player.AcademyID).
 			Limit(3).
 			Find(&academyPlayers)
@@ -536,7 +624,7 @@ func (m *ProfilesModule) GetSimilarPlayers(c *gin.Context) {
 		}
 
 		var positionCountryPlayers []domain.Player
-		m.db.Preload("Academy").
+		m.db.Preload("Academy").Scopes(consent.Published(consent.ProfilePublication)).
 			Where("id NOT IN ? AND position = ? AND country = ? AND deleted_at IS NULL AND verification_status = 'verified'", existingIDs, player.Position, player.Country).
 			Limit(remaining).
 			Find(&positionCountryPlayers)
@@ -560,7 +648,7 @@ func (m *ProfilesModule) GetSimilarPlayers(c *gin.Context) {
 		maxBirth := player.DateOfBirth.AddDate(2, 0, 0)
 
 		var ageMatchPlayers []domain.Player
-		m.db.Preload("Academy").
+		m.db.Preload("Academy").Scopes(consent.Published(consent.ProfilePublication)).
 			Where("id NOT IN ? AND position = ? AND date_of_birth BETWEEN ? AND ? AND deleted_at IS NULL AND verification_status = 'verified'", existingIDs, player.Position, minBirth, maxBirth).
 			Limit(remaining).
 			Find(&ageMatchPlayers)
@@ -572,11 +660,13 @@ func (m *ProfilesModule) GetSimilarPlayers(c *gin.Context) {
 	}
 
 	// Build response
+	policy := privacy.For(c, m.db)
+	policy.Load(similar)
 	response := make([]SimilarPlayerResponse, len(similar))
 	for i, p := range similar {
 		var profilePhotoURL *string
 		if p.ProfilePhotoURL != nil && *p.ProfilePhotoURL != "" {
-			url := m.getPresignedURL(*p.ProfilePhotoURL)
+			url := storage.SignedURL(m.store, *p.ProfilePhotoURL)
 			profilePhotoURL = &url
 		}
 
@@ -585,11 +675,14 @@ func (m *ProfilesModule) GetSimilarPlayers(c *gin.Context) {
 			academyName = &p.Academy.Name
 		}
 
+		masked := policy.Mask(&p)
 		response[i] = SimilarPlayerResponse{
 			ID:              p.ID,
 			FirstName:       p.FirstName,
-			LastNameInit:    p.GetLastNameInit(),
-			Age:             p.GetAge(),
+			LastName:        masked.LastName,
+			LastNameInit:    masked.LastNameInit,
+			Age:             masked.Age,
+			AgeGroup:        masked.AgeGroup,
 			Position:        p.Position,
 			Country:         p.Country,
 			ProfilePhotoURL: profilePhotoURL,
@@ -751,16 +844,49 @@ func (m *ProfilesModule) GetSavedPlayers(c *gin.Context) {
 	m.db.Preload("Player.Academy").Where("user_id = ?", userID).
 		Offset(offset).Limit(limit).Order("priority DESC, created_at DESC").Find(&saved)
 
+	// The scout's match video notes on these players (latest few + count per player)
+	playerIDs := make([]uuid.UUID, len(saved))
+	for i, s := range saved {
+		playerIDs[i] = s.PlayerID
+	}
+	var notes []domain.MatchVideoNote
+	if len(playerIDs) > 0 {
+		m.db.Preload("Match").Where("user_id = ? AND player_id IN ?", userID, playerIDs).
+			Order("created_at DESC").Find(&notes)
+	}
+	noteCounts := map[uuid.UUID]int{}
+	recentNotes := map[uuid.UUID][]gin.H{}
+	for _, n := range notes {
+		noteCounts[*n.PlayerID]++
+		if len(recentNotes[*n.PlayerID]) >= 3 {
+			continue
+		}
+		note := gin.H{
+			"id":                n.ID,
+			"match_id":          n.MatchID,
+			"timestamp_seconds": n.TimestampSeconds,
+			"timestamp":         n.Timestamp(),
+			"body":              n.Body,
+			"created_at":        n.CreatedAt,
+		}
+		if n.Match != nil {
+			note["match_title"] = n.Match.Title
+		}
+		recentNotes[*n.PlayerID] = append(recentNotes[*n.PlayerID], note)
+	}
+
 	// Convert to response
+	policy := privacy.For(c, m.db)
 	response := make([]gin.H, len(saved))
 	for i, s := range saved {
+		masked := policy.Mask(s.Player)
 		var profilePhotoURL, thumbnailURL *string
 		if s.Player.ProfilePhotoURL != nil && *s.Player.ProfilePhotoURL != "" {
-			url := m.getPresignedURL(*s.Player.ProfilePhotoURL)
+			url := storage.SignedURL(m.store, *s.Player.ProfilePhotoURL)
 			profilePhotoURL = &url
 		}
 		if s.Player.ThumbnailURL != nil && *s.Player.ThumbnailURL != "" {
-			url := m.getPresignedURL(*s.Player.ThumbnailURL)
+			url := storage.SignedURL(m.store, *s.Player.ThumbnailURL)
 			thumbnailURL = &url
 		}
 
@@ -777,11 +903,16 @@ func (m *ProfilesModule) GetSavedPlayers(c *gin.Context) {
 			"priority":   s.Priority,
 			"saved_at":   s.CreatedAt,
 			"updated_at": s.UpdatedAt,
+			"match_notes": gin.H{
+				"count":  noteCounts[s.PlayerID],
+				"recent": recentNotes[s.PlayerID],
+			},
 			"player": gin.H{
 				"first_name":        s.Player.FirstName,
-				"last_name":         s.Player.LastName,
-				"last_name_init":    s.Player.GetLastNameInit(),
-				"age":               s.Player.GetAge(),
+				"last_name":         masked.LastName,
+				"last_name_init":    masked.LastNameInit,
+				"age":               masked.Age,
+				"age_group":         masked.AgeGroup,
 				"position":          s.Player.Position,
 				"country":           s.Player.Country,
 				"academy_name":      academyName,
@@ -878,6 +1009,45 @@ func (m *ProfilesModule) DeleteTag(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Tag deleted"})
 }
 
+// GetViewPrivacy returns whether players can see this scout's identity when viewed
+func (m *ProfilesModule) GetViewPrivacy(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var scout domain.Scout
+	if err := m.db.Where("user_id = ?", userID).First(&scout).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Scout profile not found"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"share_identity_with_players": scout.ShareIdentityWithPlayers}})
+}
+
+// UpdateViewPrivacy lets a scout opt in (or back out) of showing their name and
+// organization to players whose profiles they view. Past views stay anonymous
+// after opting out.
+func (m *ProfilesModule) UpdateViewPrivacy(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req struct {
+		ShareIdentityWithPlayers *bool `json:"share_identity_with_players" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	result := m.db.Model(&domain.Scout{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"share_identity_with_players": *req.ShareIdentityWithPlayers,
+		"updated_at":                  time.Now(),
+	})
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Scout profile not found"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"share_identity_with_players": *req.ShareIdentityWithPlayers}})
+}
+
 // --- Contact Requests (Pro+ feature) ---
 
 // CreateContactRequest creates a contact request for a player
@@ -906,6 +1076,12 @@ func (m *ProfilesModule) CreateContactRequest(c *gin.Context) {
 		return
 	}
 
+	// Minors can only be contacted once a guardian has agreed to it
+	if !consent.Allowed(m.db, &player, consent.ScoutContact) {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"code": "CONTACT_NOT_ALLOWED", "message": "This player can't be contacted yet"}})
+		return
+	}
+
 	// Check for existing pending request
 	var existingRequest domain.ContactRequest
 	if err := m.db.Where("user_id = ? AND player_id = ? AND status IN ('pending', 'sent_to_academy')", userID, pid).First(&existingRequest).Error; err == nil {
@@ -930,6 +1106,7 @@ func (m *ProfilesModule) CreateContactRequest(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to create contact request"}})
 		return
 	}
+	access.Record(m.db, c, access.ActionContactRequest, "contact_request", &contact.ID, player)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
@@ -964,11 +1141,15 @@ func (m *ProfilesModule) GetMyContactRequests(c *gin.Context) {
 	m.db.Preload("Player.Academy").Where("user_id = ?", userID).Order("created_at DESC").
 		Offset(offset).Limit(limit).Find(&requests)
 
+	policy := privacy.For(c, m.db)
 	response := make([]gin.H, len(requests))
+	viewed := make([]domain.Player, 0, len(requests))
 	for i, r := range requests {
+		masked := policy.Mask(r.Player)
+		viewed = append(viewed, *r.Player)
 		var profilePhotoURL *string
 		if r.Player.ProfilePhotoURL != nil && *r.Player.ProfilePhotoURL != "" {
-			url := m.getPresignedURL(*r.Player.ProfilePhotoURL)
+			url := storage.SignedURL(m.store, *r.Player.ProfilePhotoURL)
 			profilePhotoURL = &url
 		}
 
@@ -990,8 +1171,8 @@ func (m *ProfilesModule) GetMyContactRequests(c *gin.Context) {
 			"updated_at":           r.UpdatedAt,
 			"player": gin.H{
 				"first_name":        r.Player.FirstName,
-				"last_name":         r.Player.LastName,
-				"last_name_init":    r.Player.GetLastNameInit(),
+				"last_name":         masked.LastName,
+				"last_name_init":    masked.LastNameInit,
 				"position":          r.Player.Position,
 				"country":           r.Player.Country,
 				"academy_name":      academyName,
@@ -1000,6 +1181,8 @@ func (m *ProfilesModule) GetMyContactRequests(c *gin.Context) {
 		}
 	}
 
+	access.Record(m.db, c, access.ActionViewContactRequests, "contact_request", nil, viewed...)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
@@ -1085,13 +1268,16 @@ func (m *ProfilesModule) GetFeaturedPlayers(c *gin.Context) {
 		Where("deleted_at IS NULL").
 		Where("verification_status IN (?, ?)", "verified", "approved").
 		Where("profile_photo_url IS NOT NULL AND profile_photo_url != ''").
+		Scopes(consent.Published(consent.ProfilePublication)).
 		Order("CASE WHEN verification_status = 'verified' THEN 0 ELSE 1 END, created_at DESC").
 		Limit(limit).
 		Find(&players)
 
+	policy := privacy.For(c, m.db)
+	policy.Load(players)
 	response := make([]FeaturedPlayerResponse, len(players))
 	for i, p := range players {
-		dob := p.DateOfBirth.Format("2006-01-02")
+		masked := policy.Mask(&p)
 		var academyName *string
 		if p.Academy != nil {
 			academyName = &p.Academy.Name
@@ -1100,16 +1286,18 @@ func (m *ProfilesModule) GetFeaturedPlayers(c *gin.Context) {
 		// Convert profile photo URL to presigned URL
 		var profilePhotoURL *string
 		if p.ProfilePhotoURL != nil && *p.ProfilePhotoURL != "" {
-			url := m.getPresignedURL(*p.ProfilePhotoURL)
+			url := storage.SignedURL(m.store, *p.ProfilePhotoURL)
 			profilePhotoURL = &url
 		}
 
 		response[i] = FeaturedPlayerResponse{
 			ID:              p.ID,
 			FirstName:       p.FirstName,
-			LastName:        p.LastName,
-			DateOfBirth:     dob,
-			Age:             p.GetAge(),
+			LastName:        masked.LastName,
+			LastNameInit:    masked.LastNameInit,
+			DateOfBirth:     masked.DateOfBirth,
+			Age:             masked.Age,
+			AgeGroup:        masked.AgeGroup,
 			Position:        p.Position,
 			Country:         p.Country,
 			ProfilePhotoURL: profilePhotoURL,
@@ -1164,3 +1352,648 @@ func (m *ProfilesModule) ListAcademies(c *gin.Context) {
 		},
 	})
 }
+
+// --- Player Dashboard (player role) ---
+
+// currentPlayer loads the player profile linked to the logged-in player account,
+// writing the error response when there isn't one
+func (m *ProfilesModule) currentPlayer(c *gin.Context) (*domain.Player, bool) {
+	userID, _ := c.Get("user_id")
+
+	var player domain.Player
+	if err := m.db.Where("user_id = ? AND deleted_at IS NULL", userID).First(&player).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "No player profile linked to this account"}})
+		return nil, false
+	}
+	return &player, true
+}
+
+// GetPlayerDashboard shows a player who's looking at them: weekly profile views,
+// how many scouts saved them, highlight plays and where scouts are viewing from.
+// Viewers are only named when the scout has opted in.
+func (m *ProfilesModule) GetPlayerDashboard(c *gin.Context) {
+	player, ok := m.currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	weeks, _ := strconv.Atoi(c.DefaultQuery("weeks", "12"))
+	if weeks < 4 || weeks > 52 {
+		weeks = 12
+	}
+
+	ctx := c.Request.Context()
+	now := time.Now()
+	thisWeek := startOfWeek(now)
+	from := thisWeek.AddDate(0, 0, -7*(weeks-1))
+	since := now.AddDate(0, 0, -90) // audience breakdowns cover the last 90 days
+
+	// Weekly series from the daily rollups (refreshed every few minutes)
+	profileWeekly, _ := m.views.Weekly(ctx, from, views.AssetProfile, &player.ID)
+	highlightWeekly, _ := m.views.Weekly(ctx, from, views.AssetHighlight, &player.ID)
+	profileSeries := fillWeeks(profileWeekly, from, weeks)
+	highlightSeries := fillWeeks(highlightWeekly, from, weeks)
+
+	var totalProfileViews int64
+	m.db.Model(&domain.ViewRollup{}).
+		Where("asset_type = ? AND asset_id = ?", views.AssetProfile, player.ID).
+		Select("COALESCE(SUM(views), 0)").Scan(&totalProfileViews)
+
+	// Saves by scouts
+	var savesCount, savesThisWeek int64
+	m.db.Model(&domain.SavedPlayer{}).Where("player_id = ?", player.ID).Count(&savesCount)
+	m.db.Model(&domain.SavedPlayer{}).Where("player_id = ? AND created_at >= ?", player.ID, thisWeek).Count(&savesThisWeek)
+
+	// Highlight plays
+	var highlights []domain.PlayerHighlight
+	m.db.Where("player_id = ? AND status = ?", player.ID, "approved").Order("view_count DESC").Find(&highlights)
+	totalPlays := 0
+	topHighlights := make([]gin.H, 0, 5)
+	for _, h := range highlights {
+		totalPlays += h.ViewCount
+		if len(topHighlights) < 5 {
+			topHighlights = append(topHighlights, gin.H{
+				"id":             h.ID,
+				"title":          h.Title,
+				"highlight_type": h.HighlightType,
+				"plays":          h.ViewCount,
+			})
+		}
+	}
+
+	// Where scouts are looking from, and what kind of organizations
+	type audienceCount struct {
+		Label  string `json:"label"`
+		Scouts int    `json:"scouts"`
+		Views  int    `json:"views"`
+	}
+	var countries []audienceCount
+	m.db.Model(&domain.ProfileView{}).
+		Select("country AS label, COUNT(DISTINCT viewer_id) AS scouts, COUNT(*) AS views").
+		Where("player_id = ? AND viewer_role = ? AND country IS NOT NULL AND country != '' AND created_at >= ?", player.ID, "scout", since).
+		Group("country").Order("scouts DESC, views DESC").Limit(10).
+		Scan(&countries)
+
+	var orgTypes []audienceCount
+	m.db.Model(&domain.ProfileView{}).
+		Select("organization_type AS label, COUNT(DISTINCT viewer_id) AS scouts, COUNT(*) AS views").
+		Where("player_id = ? AND viewer_role = ? AND organization_type IS NOT NULL AND organization_type != '' AND created_at >= ?", player.ID, "scout", since).
+		Group("organization_type").Order("scouts DESC, views DESC").
+		Scan(&orgTypes)
+
+	// Named viewers: scouts who opted in when they viewed and still share their identity
+	type namedViewer struct {
+		FirstName        string    `json:"first_name"`
+		LastName         string    `json:"last_name"`
+		OrganizationName *string   `json:"organization_name,omitempty"`
+		OrganizationType *string   `json:"organization_type,omitempty"`
+		Country          *string   `json:"country,omitempty"`
+		LastViewedAt     time.Time `json:"last_viewed_at"`
+	}
+	var namedViewers []namedViewer
+	m.db.Raw(`
+		SELECT DISTINCT ON (pv.viewer_id)
+			u.first_name, u.last_name, s.organization_name, s.organization_type, s.country,
+			pv.created_at AS last_viewed_at
+		FROM profile_views pv
+		JOIN users u ON u.id = pv.viewer_id
+		JOIN scouts s ON s.user_id = pv.viewer_id
+		WHERE pv.player_id = ? AND pv.identity_shared AND s.share_identity_with_players AND pv.created_at >= ?
+		ORDER BY pv.viewer_id, pv.created_at DESC
+	`, player.ID, since).Scan(&namedViewers)
+	sort.Slice(namedViewers, func(i, j int) bool {
+		return namedViewers[i].LastViewedAt.After(namedViewers[j].LastViewedAt)
+	})
+	if len(namedViewers) > 10 {
+		namedViewers = namedViewers[:10]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"player_id": player.ID,
+			"profile_views": gin.H{
+				"this_week": profileSeries[len(profileSeries)-1].Views,
+				"last_week": profileSeries[len(profileSeries)-2].Views,
+				"total":     totalProfileViews,
+				"weekly":    profileSeries,
+			},
+			"saves": gin.H{
+				"total":     savesCount,
+				"this_week": savesThisWeek,
+			},
+			"highlight_plays": gin.H{
+				"total":          totalPlays,
+				"this_week":      highlightSeries[len(highlightSeries)-1].Views,
+				"weekly":         highlightSeries,
+				"top_highlights": topHighlights,
+			},
+			"scout_countries":          countries,
+			"scout_organization_types": orgTypes,
+			"named_viewers":            namedViewers,
+		},
+	})
+}
+
+// startOfWeek returns Monday 00:00 of t's week, matching Postgres DATE_TRUNC('week')
+func startOfWeek(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	day := time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// fillWeeks returns one entry per week from `from`, with zeros for weeks without views
+func fillWeeks(counts []views.DailyCount, from time.Time, weeks int) []views.DailyCount {
+	byWeek := make(map[string]views.DailyCount, len(counts))
+	for _, wc := range counts {
+		byWeek[wc.Date] = wc
+	}
+	series := make([]views.DailyCount, weeks)
+	for i := range series {
+		week := from.AddDate(0, 0, 7*i).Format("2006-01-02")
+		series[i] = byWeek[week]
+		series[i].Date = week
+	}
+	return series
+}
+
+// --- Player Self-Service (player role) ---
+
+// maxSecondaryPositions caps how many extra positions a player can list
+const maxSecondaryPositions = 3
+
+// photoExtensions maps accepted profile photo types to the stored file extension
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// documentExtensions maps accepted identity document types to the stored file extension
+var documentExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// GetMyProfile returns the logged-in player's own profile, including the fields
+// scouts don't see (verification status) and any change request awaiting review
+func (m *ProfilesModule) GetMyProfile(c *gin.Context) {
+	player, ok := m.currentPlayer(c)
+	if !ok {
+		return
+	}
+	m.db.Preload("Academy").First(player, "id = ?", player.ID)
+
+	var profilePhotoURL *string
+	if player.ProfilePhotoURL != nil && *player.ProfilePhotoURL != "" {
+		url := storage.SignedURL(m.store, *player.ProfilePhotoURL)
+		profilePhotoURL = &url
+	}
+
+	profile := gin.H{
+		"id":                  player.ID,
+		"first_name":          player.FirstName,
+		"last_name":           player.LastName,
+		"date_of_birth":       player.DateOfBirth.Format("2006-01-02"),
+		"age":                 player.GetAge(),
+		"position":            player.Position,
+		"secondary_positions": parsePositions(player.SecondaryPositions),
+		"preferred_foot":      player.PreferredFoot,
+		"height_cm":           player.HeightCm,
+		"weight_kg":           player.WeightKg,
+		"country":             player.Country,
+		"state":               player.State,
+		"city":                player.City,
+		"school_name":         player.SchoolName,
+		"profile_photo_url":   profilePhotoURL,
+		"verification_status": player.VerificationStatus,
+		"is_verified":         player.IsVerified(),
+		"created_at":          player.CreatedAt,
+		"updated_at":          player.UpdatedAt,
+	}
+	if player.Academy != nil {
+		profile["academy"] = gin.H{"id": player.Academy.ID, "name": player.Academy.Name}
+	}
+
+	var pending domain.PlayerChangeRequest
+	if err := m.db.Where("player_id = ? AND status = ?", player.ID, "pending").First(&pending).Error; err == nil {
+		profile["pending_change_request"] = m.changeRequestResponse(pending)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": profile})
+}
+
+// playerUpload describes a file type players upload for review. Uploads are
+// quarantined and scanned like admin uploads.
+type playerUpload struct {
+	uploadType  string
+	label       string
+	keyFormat   string // object key, given the session ID and file extension
+	extensions  map[string]string
+	maxSize     int64
+	typeMessage string
+}
+
+var (
+	photoUpload = playerUpload{
+		uploadType:  "profile_photo",
+		label:       "photo",
+		keyFormat:   "players/photos/%s/photo%s",
+		extensions:  photoExtensions,
+		maxSize:     5 * 1024 * 1024, // same limit as admin profile photo uploads
+		typeMessage: "Photo must be a JPEG, PNG or WebP image",
+	}
+	documentUpload = playerUpload{
+		uploadType:  "verification_doc",
+		label:       "document",
+		keyFormat:   verification.DocPrefix + "%s/document%s",
+		extensions:  documentExtensions,
+		maxSize:     10 * 1024 * 1024, // same limit as admin verification uploads
+		typeMessage: "Document must be a PDF, JPEG or PNG file",
+	}
+)
+
+// InitPhotoUpload returns a presigned URL for a new profile photo. The photo only
+// replaces the current photo once a change request including it is approved.
+func (m *ProfilesModule) InitPhotoUpload(c *gin.Context) {
+	m.initPlayerUpload(c, photoUpload)
+}
+
+// initPlayerUpload creates an upload session for the logged-in player and returns
+// a presigned URL for it
+func (m *ProfilesModule) initPlayerUpload(c *gin.Context, kind playerUpload) {
+	player, ok := m.currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		FileName    string `json:"file_name" binding:"required"`
+		ContentType string `json:"content_type" binding:"required"`
+		FileSize    int64  `json:"file_size" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	ext, allowed := kind.extensions[req.ContentType]
+	if !allowed {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_CONTENT_TYPE", "message": kind.typeMessage}})
+		return
+	}
+	if req.FileSize > kind.maxSize {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "FILE_TOO_LARGE", "message": fmt.Sprintf("Maximum file size is %d MB", kind.maxSize/(1024*1024))}})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	sessionID := uuid.New()
+	s3Key := uploads.QuarantineKey(fmt.Sprintf(kind.keyFormat, sessionID, ext))
+	entityType := "player"
+	expiresIn := 3600 // 1 hour
+
+	session := domain.UploadSession{
+		ID:          sessionID,
+		UploadType:  kind.uploadType,
+		ContentType: req.ContentType,
+		FileName:    req.FileName,
+		FileSize:    req.FileSize,
+		S3Key:       s3Key,
+		Status:      "pending",
+		EntityType:  &entityType,
+		EntityID:    &player.ID,
+		UploadedBy:  userID,
+		ExpiresAt:   time.Now().Add(time.Duration(expiresIn) * time.Second),
+	}
+	if err := m.db.Create(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "SESSION_CREATE_FAILED", "message": "Failed to create upload session"}})
+		return
+	}
+
+	uploadURL, err := m.store.PresignPut(c.Request.Context(), s3Key, req.ContentType, req.FileSize, time.Duration(expiresIn)*time.Second)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "PRESIGN_FAILED", "message": "Failed to generate upload URL"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"session_id":    sessionID,
+			"upload_url":    uploadURL,
+			"expires_in":    expiresIn,
+			"max_file_size": kind.maxSize,
+		},
+	})
+}
+
+// ChangeRequestInput is what a player can ask to change on their profile
+type ChangeRequestInput struct {
+	SchoolName         *string  `json:"school_name" binding:"omitempty,max=255"`
+	City               *string  `json:"city" binding:"omitempty,max=100"`
+	HeightCm           *int     `json:"height_cm" binding:"omitempty,min=100,max=230"`
+	SecondaryPositions []string `json:"secondary_positions"`
+	PhotoSessionID     *string  `json:"photo_session_id"` // from /player/photo/upload, once the file is uploaded
+	Note               *string  `json:"note" binding:"omitempty,max=1000"`
+}
+
+// SubmitChangeRequest queues profile edits for admin approval. A player has at
+// most one pending request; cancel it to submit a different one.
+func (m *ProfilesModule) SubmitChangeRequest(c *gin.Context) {
+	player, ok := m.currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req ChangeRequestInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+
+	var pending int64
+	m.db.Model(&domain.PlayerChangeRequest{}).Where("player_id = ? AND status = ?", player.ID, "pending").Count(&pending)
+	if pending > 0 {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "CHANGE_REQUEST_PENDING", "message": "You already have changes waiting for review. Cancel them to submit new ones."}})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	change := domain.PlayerChangeRequest{
+		PlayerID:    player.ID,
+		SubmittedBy: userID,
+		HeightCm:    req.HeightCm,
+		Status:      "pending",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if req.SchoolName != nil {
+		school := strings.TrimSpace(*req.SchoolName)
+		change.SchoolName = &school
+	}
+	if req.City != nil {
+		city := strings.TrimSpace(*req.City)
+		change.City = &city
+	}
+	if req.Note != nil && strings.TrimSpace(*req.Note) != "" {
+		note := strings.TrimSpace(*req.Note)
+		change.Note = &note
+	}
+
+	if req.SecondaryPositions != nil {
+		positions, err := normalizePositions(req.SecondaryPositions, player.Position)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_POSITIONS", "message": err.Error()}})
+			return
+		}
+		encoded, _ := json.Marshal(positions)
+		value := string(encoded)
+		change.SecondaryPositions = &value
+	}
+
+	if req.PhotoSessionID != nil && *req.PhotoSessionID != "" {
+		photoURL, ok := m.verifiedUpload(c, photoUpload, *req.PhotoSessionID, player.ID, userID)
+		if !ok {
+			return
+		}
+		change.PhotoURL = &photoURL
+	}
+
+	if change.SchoolName == nil && change.City == nil && change.HeightCm == nil && change.SecondaryPositions == nil && change.PhotoURL == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "NO_CHANGES", "message": "Nothing to change"}})
+		return
+	}
+
+	if err := m.db.Create(&change).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to submit changes"}})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": m.changeRequestResponse(change)})
+}
+
+// verifiedUpload checks an upload belongs to this player and has passed the
+// integrity and malware checks, returning the s3:// URL of its final object
+func (m *ProfilesModule) verifiedUpload(c *gin.Context, kind playerUpload, sessionID string, playerID, userID uuid.UUID) (string, bool) {
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": fmt.Sprintf("Invalid %s_session_id", kind.label)}})
+		return "", false
+	}
+
+	var session domain.UploadSession
+	if err := m.db.Where("id = ? AND uploaded_by = ? AND upload_type = ? AND entity_id = ?", sid, userID, kind.uploadType, playerID).
+		First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "UPLOAD_NOT_FOUND", "message": strings.ToUpper(kind.label[:1]) + kind.label[1:] + " upload not found"}})
+		return "", false
+	}
+
+	switch session.Status {
+	case "completed":
+		return storage.ObjectURL(m.store, session.S3Key), true
+	case "pending", "uploading":
+		err = m.uploads.Verify(c.Request.Context(), &session, nil)
+	default:
+		err = &storage.MismatchError{Reason: kind.label + " upload is no longer valid, please upload it again"}
+	}
+	if err != nil {
+		var mismatch *storage.MismatchError
+		if errors.As(err, &mismatch) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"code": "UPLOAD_VERIFICATION_FAILED", "message": mismatch.Reason}})
+			return "", false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "VERIFICATION_ERROR", "message": fmt.Sprintf("Failed to verify %s upload", kind.label)}})
+		return "", false
+	}
+	return storage.ObjectURL(m.store, session.S3Key), true
+}
+
+// GetMyChangeRequests lists the player's change requests, newest first
+func (m *ProfilesModule) GetMyChangeRequests(c *gin.Context) {
+	player, ok := m.currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var changes []domain.PlayerChangeRequest
+	m.db.Where("player_id = ?", player.ID).Order("created_at DESC").Limit(50).Find(&changes)
+
+	response := make([]gin.H, len(changes))
+	for i, change := range changes {
+		response[i] = m.changeRequestResponse(change)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": response})
+}
+
+// CancelChangeRequest withdraws a change request that hasn't been reviewed yet
+func (m *ProfilesModule) CancelChangeRequest(c *gin.Context) {
+	player, ok := m.currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	result := m.db.Model(&domain.PlayerChangeRequest{}).
+		Where("id = ? AND player_id = ? AND status = ?", c.Param("id"), player.ID, "pending").
+		Updates(map[string]interface{}{"status": "cancelled", "updated_at": time.Now()})
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Pending change request not found"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Change request cancelled"})
+}
+
+func (m *ProfilesModule) changeRequestResponse(change domain.PlayerChangeRequest) gin.H {
+	response := gin.H{
+		"id":          change.ID,
+		"status":      change.Status,
+		"school_name": change.SchoolName,
+		"city":        change.City,
+		"height_cm":   change.HeightCm,
+		"note":        change.Note,
+		"review_note": change.ReviewNote,
+		"reviewed_at": change.ReviewedAt,
+		"created_at":  change.CreatedAt,
+	}
+	if change.SecondaryPositions != nil {
+		response["secondary_positions"] = parsePositions(change.SecondaryPositions)
+	}
+	if change.PhotoURL != nil {
+		response["photo_url"] = storage.SignedURL(m.store, *change.PhotoURL)
+	}
+	return response
+}
+
+// normalizePositions trims, de-duplicates and validates secondary positions.
+// The primary position is dropped since it's already on the profile.
+func normalizePositions(positions []string, primary string) ([]string, error) {
+	seen := map[string]bool{strings.ToLower(primary): true}
+	result := make([]string, 0, len(positions))
+	for _, p := range positions {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[strings.ToLower(p)] {
+			continue
+		}
+		if len(p) > 30 {
+			return nil, fmt.Errorf("position %q is too long", p)
+		}
+		seen[strings.ToLower(p)] = true
+		result = append(result, p)
+	}
+	if len(result) > maxSecondaryPositions {
+		return nil, fmt.Errorf("at most %d secondary positions", maxSecondaryPositions)
+	}
+	return result, nil
+}
+
+// parsePositions decodes a stored JSON array of positions
+func parsePositions(raw *string) []string {
+	positions := []string{}
+	if raw != nil && *raw != "" {
+		json.Unmarshal([]byte(*raw), &positions)
+	}
+	return positions
+}
+
+// --- Player Verification (player role) ---
+
+// InitDocumentUpload returns a presigned URL for an identity document. Documents
+// are stored under the private verification prefix and never served publicly.
+func (m *ProfilesModule) InitDocumentUpload(c *gin.Context) {
+	m.initPlayerUpload(c, documentUpload)
+}
+
+// SubmitVerificationInput is an uploaded identity document to review
+type SubmitVerificationInput struct {
+	DocType           string `json:"doc_type" binding:"required"`
+	DocumentSessionID string `json:"document_session_id" binding:"required"` // from /player/verification/upload
+}
+
+// SubmitVerification sends the player's identity document to the review queue.
+// If a reviewer asked for more information, it replaces the previous document.
+func (m *ProfilesModule) SubmitVerification(c *gin.Context) {
+	player, ok := m.currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req SubmitVerificationInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+	if _, ok := verification.DocTypes[req.DocType]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DOC_TYPE", "message": "Unknown document type"}})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	docURL, ok := m.verifiedUpload(c, documentUpload, req.DocumentSessionID, player.ID, userID)
+	if !ok {
+		return
+	}
+
+	v, err := verification.Submit(m.db, player, req.DocType, docURL, userID)
+	switch {
+	case errors.Is(err, verification.ErrAlreadyVerified):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "ALREADY_VERIFIED", "message": "Your profile is already verified"}})
+		return
+	case errors.Is(err, verification.ErrUnderReview):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "UNDER_REVIEW", "message": "Your document is being reviewed. You can send a new one if the reviewer asks for it."}})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to submit document"}})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": verificationResponse(*v)})
+}
+
+// GetMyVerification returns the player's verification status, the latest
+// submission with any reviewer message, and the history of decisions
+func (m *ProfilesModule) GetMyVerification(c *gin.Context) {
+	player, ok := m.currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	data := gin.H{
+		"verification_status": player.VerificationStatus,
+		"is_verified":         player.IsVerified(),
+		"verified_at":         player.VerifiedAt,
+		"verification_source": player.VerificationSource,
+		"doc_types":           verification.DocTypes,
+	}
+
+	var latest domain.PlayerVerification
+	if err := m.db.Where("player_id = ?", player.ID).Order("created_at DESC").First(&latest).Error; err == nil {
+		data["submission"] = verificationResponse(latest)
+	}
+
+	var events []domain.VerificationEvent
+	m.db.Where("player_id = ?", player.ID).Order("created_at ASC").Find(&events)
+	history := make([]gin.H, len(events))
+	for i, e := range events {
+		history[i] = gin.H{"action": e.Action, "note": e.Note, "created_at": e.CreatedAt}
+	}
+	data["history"] = history
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+}
+
+// verificationResponse is a submission as the player sees it, without reviewer details
+func verificationResponse(v domain.PlayerVerification) gin.H {
+	return gin.H{
+		"id":         v.ID,
+		"doc_type":   v.DocType,
+		"status":     v.Status,
+		"reason":     v.Reason,
+		"decided_at": v.DecidedAt,
+		"created_at": v.CreatedAt,
+		"updated_at": v.UpdatedAt,
+	}
+}
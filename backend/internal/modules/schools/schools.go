@@ -0,0 +1,491 @@
+package schools
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/agegroup"
+	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/verification"
+)
+
+// SchoolsModule is the partner school portal: school staff confirm their students'
+// enrollment and date of birth, and register groups of students from a roster
+type SchoolsModule struct {
+	db *gorm.DB
+}
+
+// NewSchoolsModule creates a new schools module
+func NewSchoolsModule(db *gorm.DB) *SchoolsModule {
+	return &SchoolsModule{db: db}
+}
+
+// maxRosterRows caps one roster upload
+const maxRosterRows = 500
+
+// currentSchool loads the school the logged-in staff account acts for, writing the
+// error response when there is none
+func (m *SchoolsModule) currentSchool(c *gin.Context) (*domain.School, bool) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var link domain.SchoolAdmin
+	if err := m.db.Preload("School").Where("user_id = ?", userID).First(&link).Error; err != nil || link.School == nil {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"code": "NO_SCHOOL", "message": "This account isn't linked to a school"}})
+		return nil, false
+	}
+	if !link.School.IsActive {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"code": "SCHOOL_INACTIVE", "message": "This school's partnership isn't active"}})
+		return nil, false
+	}
+	return link.School, true
+}
+
+// GetMySchool returns the school with its student and queue counts
+func (m *SchoolsModule) GetMySchool(c *gin.Context) {
+	school, ok := m.currentSchool(c)
+	if !ok {
+		return
+	}
+
+	var students, verified, pending int64
+	m.db.Model(&domain.Player{}).Where("school_id = ? AND deleted_at IS NULL", school.ID).Count(&students)
+	m.db.Model(&domain.Player{}).Where("school_id = ? AND deleted_at IS NULL AND verification_status = ?", school.ID, "verified").Count(&verified)
+	m.db.Model(&domain.SchoolAttestation{}).Where("school_id = ? AND status = ?", school.ID, verification.AttestationPending).Count(&pending)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"school": school,
+			"stats": gin.H{
+				"students":             students,
+				"verified_students":    verified,
+				"pending_attestations": pending,
+			},
+		},
+	})
+}
+
+// ListStudents returns the players linked to the school
+func (m *SchoolsModule) ListStudents(c *gin.Context) {
+	school, ok := m.currentSchool(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 200 {
+		perPage = 50
+	}
+
+	query := m.db.Model(&domain.Player{}).Where("school_id = ? AND deleted_at IS NULL", school.ID)
+	if search := c.Query("q"); search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?", pattern, pattern)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var players []domain.Player
+	query.Order("last_name ASC, first_name ASC").Offset((page - 1) * perPage).Limit(perPage).Find(&players)
+
+	students := make([]gin.H, len(players))
+	for i := range players {
+		students[i] = studentResponse(&players[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"students": students,
+			"total":    total,
+			"page":     page,
+			"per_page": perPage,
+		},
+	})
+}
+
+// ListAttestations returns the school's attestation queue, pending by default
+func (m *SchoolsModule) ListAttestations(c *gin.Context) {
+	school, ok := m.currentSchool(c)
+	if !ok {
+		return
+	}
+
+	query := m.db.Preload("Player").Where("school_id = ?", school.ID)
+	if status := c.DefaultQuery("status", verification.AttestationPending); status != "all" {
+		query = query.Where("status = ?", status)
+	}
+
+	var attestations []domain.SchoolAttestation
+	if err := query.Order("created_at ASC").Limit(200).Find(&attestations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "FETCH_FAILED", "message": "Failed to fetch attestations"}})
+		return
+	}
+
+	response := make([]gin.H, len(attestations))
+	for i, a := range attestations {
+		response[i] = attestationResponse(a)
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": response, "dispute_reasons": verification.DisputeReasons})
+}
+
+// ConfirmAttestation confirms the student is enrolled and their date of birth
+// matches the school's records. This verifies the player.
+func (m *SchoolsModule) ConfirmAttestation(c *gin.Context) {
+	var req struct {
+		Note *string `json:"note" binding:"omitempty,max=1000"`
+	}
+	c.ShouldBindJSON(&req)
+
+	a, ok := m.findAttestation(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	if err := verification.ConfirmAttestation(m.db, a, userID, req.Note); err != nil {
+		attestationError(c, err)
+		return
+	}
+	agegroup.Recheck(m.db, a.PlayerID)
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": attestationResponse(*a)})
+}
+
+// DisputeAttestationRequest is why the school can't confirm a student
+type DisputeAttestationRequest struct {
+	Reason      string  `json:"reason" binding:"required"`
+	DateOfBirth *string `json:"date_of_birth,omitempty"` // YYYY-MM-DD from school records, required for dob_incorrect
+	Note        *string `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// DisputeAttestation reports that the student isn't enrolled or that their date of
+// birth doesn't match the school's records
+func (m *SchoolsModule) DisputeAttestation(c *gin.Context) {
+	var req DisputeAttestationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": err.Error()}})
+		return
+	}
+	if _, ok := verification.DisputeReasons[req.Reason]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_REASON", "message": "Unknown dispute reason"}})
+		return
+	}
+
+	var reportedDOB *time.Time
+	if req.DateOfBirth != nil && *req.DateOfBirth != "" {
+		dob, err := time.Parse("2006-01-02", *req.DateOfBirth)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_DATE", "message": "Invalid date_of_birth format, use YYYY-MM-DD"}})
+			return
+		}
+		reportedDOB = &dob
+	} else if req.Reason == "dob_incorrect" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "VALIDATION_ERROR", "message": "Give the date of birth from your records"}})
+		return
+	}
+
+	a, ok := m.findAttestation(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	if err := verification.DisputeAttestation(m.db, a, userID, req.Reason, reportedDOB, req.Note); err != nil {
+		attestationError(c, err)
+		return
+	}
+	agegroup.Recheck(m.db, a.PlayerID)
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": attestationResponse(*a)})
+}
+
+// RosterRow is one student from a roster upload
+type RosterRow struct {
+	FirstName     string
+	LastName      string
+	DateOfBirth   time.Time
+	Position      string
+	PreferredFoot *string
+	Country       string
+	State         *string
+	City          *string
+}
+
+// UploadRoster registers a group of students from a CSV file (multipart "file")
+// with the columns first_name, last_name, date_of_birth (YYYY-MM-DD), position and
+// optionally preferred_foot, country, state and city. Uploading a roster attests
+// each student's enrollment and date of birth, so they're created verified. Nothing
+// is created if any row is invalid; students already at the school are skipped.
+func (m *SchoolsModule) UploadRoster(c *gin.Context) {
+	school, ok := m.currentSchool(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "FILE_REQUIRED", "message": "CSV file is required"}})
+		return
+	}
+	if file.Size > 2<<20 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "FILE_TOO_LARGE", "message": "CSV file too large (max 2MB)"}})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_FILE", "message": "Failed to read CSV file"}})
+		return
+	}
+	defer f.Close()
+
+	rows, rowErrors, err := parseRosterCSV(f, school.Country)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_CSV", "message": err.Error()}})
+		return
+	}
+	if len(rowErrors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   gin.H{"code": "INVALID_ROWS", "message": "Fix the rows below and upload the roster again. No students were added."},
+			"data":    gin.H{"errors": rowErrors},
+		})
+		return
+	}
+
+	// Students already linked to the school with the same name and date of birth
+	var existing []domain.Player
+	m.db.Select("first_name, last_name, date_of_birth").
+		Where("school_id = ? AND deleted_at IS NULL", school.ID).Find(&existing)
+	known := map[string]bool{}
+	for i := range existing {
+		known[rosterKey(existing[i].FirstName, existing[i].LastName, existing[i].DateOfBirth)] = true
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	now := time.Now()
+	var players []domain.Player
+	var skipped []gin.H
+	for i, row := range rows {
+		key := rosterKey(row.FirstName, row.LastName, row.DateOfBirth)
+		if known[key] {
+			skipped = append(skipped, gin.H{"row": i + 2, "reason": "already registered at this school"})
+			continue
+		}
+		known[key] = true
+		players = append(players, domain.Player{
+			FirstName:          row.FirstName,
+			LastName:           row.LastName,
+			DateOfBirth:        row.DateOfBirth,
+			Position:           row.Position,
+			PreferredFoot:      row.PreferredFoot,
+			Country:            row.Country,
+			State:              row.State,
+			City:               row.City,
+			SchoolID:           &school.ID,
+			SchoolName:         &school.Name,
+			VerificationStatus: "pending",
+			CreatedBy:          userID,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		})
+	}
+
+	if len(players) > 0 {
+		err = m.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.CreateInBatches(&players, 100).Error; err != nil {
+				return err
+			}
+			return verification.AttestRoster(tx, players, school.ID, userID)
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "CREATE_FAILED", "message": "Failed to register students"}})
+			return
+		}
+		for i := range players {
+			agegroup.Recheck(m.db, players[i].ID)
+		}
+	}
+
+	created := make([]gin.H, len(players))
+	for i := range players {
+		created[i] = gin.H{"id": players[i].ID, "first_name": players[i].FirstName, "last_name": players[i].LastName}
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"created_count": len(players),
+			"skipped_count": len(skipped),
+			"created":       created,
+			"skipped":       skipped,
+		},
+	})
+}
+
+// parseRosterCSV reads roster rows keyed by the header row. Row problems are
+// returned as rowErrors; err is for files that can't be read at all.
+func parseRosterCSV(r io.Reader, defaultCountry string) (rows []RosterRow, rowErrors []gin.H, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.New("CSV file is empty or unreadable")
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"first_name", "last_name", "date_of_birth", "position"} {
+		if _, ok := cols[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV is missing the %s column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	optional := func(record []string, name string) *string {
+		if v := field(record, name); v != "" {
+			return &v
+		}
+		return nil
+	}
+
+	line := 1 // the header
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		if len(rows)+len(rowErrors) >= maxRosterRows {
+			return nil, nil, fmt.Errorf("CSV has too many rows (max %d)", maxRosterRows)
+		}
+
+		row := RosterRow{
+			FirstName:     field(record, "first_name"),
+			LastName:      field(record, "last_name"),
+			Position:      field(record, "position"),
+			PreferredFoot: optional(record, "preferred_foot"),
+			Country:       field(record, "country"),
+			State:         optional(record, "state"),
+			City:          optional(record, "city"),
+		}
+		if row.Country == "" {
+			row.Country = defaultCountry
+		}
+
+		var problems []string
+		if row.FirstName == "" || row.LastName == "" {
+			problems = append(problems, "first_name and last_name are required")
+		}
+		if row.Position == "" {
+			problems = append(problems, "position is required")
+		}
+		dob, err := time.Parse("2006-01-02", field(record, "date_of_birth"))
+		switch {
+		case err != nil:
+			problems = append(problems, "date_of_birth must be YYYY-MM-DD")
+		case dob.After(time.Now()):
+			problems = append(problems, "date_of_birth is in the future")
+		}
+		row.DateOfBirth = dob
+
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, gin.H{"row": line, "error": strings.Join(problems, "; ")})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 && len(rowErrors) == 0 {
+		return nil, nil, errors.New("CSV has no rows")
+	}
+	return rows, rowErrors, nil
+}
+
+// rosterKey matches students by name and date of birth, ignoring case
+func rosterKey(firstName, lastName string, dob time.Time) string {
+	return strings.ToLower(firstName) + "|" + strings.ToLower(lastName) + "|" + dob.Format("2006-01-02")
+}
+
+// findAttestation loads one of the school's attestations from the URL, writing the
+// error response when missing
+func (m *SchoolsModule) findAttestation(c *gin.Context) (*domain.SchoolAttestation, bool) {
+	school, ok := m.currentSchool(c)
+	if !ok {
+		return nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_UUID", "message": "Invalid attestation ID"}})
+		return nil, false
+	}
+
+	var a domain.SchoolAttestation
+	if err := m.db.Preload("Player").Where("id = ? AND school_id = ?", id, school.ID).First(&a).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Attestation not found"}})
+		return nil, false
+	}
+	return &a, true
+}
+
+// attestationError maps attestation workflow errors to responses
+func attestationError(c *gin.Context, err error) {
+	if errors.Is(err, verification.ErrAttestationClosed) {
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"code": "ALREADY_ANSWERED", "message": err.Error()}})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "UPDATE_FAILED", "message": "Failed to update attestation"}})
+}
+
+// studentResponse is a student as their school sees them. Schools hold these
+// details already, so nothing is masked.
+func studentResponse(p *domain.Player) gin.H {
+	return gin.H{
+		"id":                  p.ID,
+		"first_name":          p.FirstName,
+		"last_name":           p.LastName,
+		"date_of_birth":       p.DateOfBirth.Format("2006-01-02"),
+		"age":                 p.GetAge(),
+		"position":            p.Position,
+		"verification_status": p.VerificationStatus,
+		"verification_source": p.VerificationSource,
+	}
+}
+
+func attestationResponse(a domain.SchoolAttestation) gin.H {
+	item := gin.H{
+		"id":                     a.ID,
+		"status":                 a.Status,
+		"dispute_reason":         a.DisputeReason,
+		"reported_date_of_birth": a.ReportedDateOfBirth,
+		"note":                   a.Note,
+		"attested_at":            a.AttestedAt,
+		"created_at":             a.CreatedAt,
+	}
+	if a.Player != nil {
+		item["student"] = studentResponse(a.Player)
+	}
+	return item
+}
@@ -1,70 +1,45 @@
 package search
 
 import (
-	"context"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"github.com/unicorn-sport/backend/internal/agegroup"
+	"github.com/unicorn-sport/backend/internal/consent"
 	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/privacy"
+	"github.com/unicorn-sport/backend/internal/registrations"
+	"github.com/unicorn-sport/backend/internal/standings"
+	"github.com/unicorn-sport/backend/internal/storage"
 )
 
 // SearchModule handles search operations
 type SearchModule struct {
-	db       *gorm.DB
-	s3Client *s3.Client
-	s3Bucket string
+	db    *gorm.DB
+	store storage.Store
 }
 
 // NewSearchModule creates a new search module
-func NewSearchModule(db *gorm.DB, s3Client *s3.Client, s3Bucket string) *SearchModule {
+func NewSearchModule(db *gorm.DB, store storage.Store) *SearchModule {
 	return &SearchModule{
-		db:       db,
-		s3Client: s3Client,
-		s3Bucket: s3Bucket,
+		db:    db,
+		store: store,
 	}
 }
 
-// getPresignedURL converts an s3:// URL to a presigned URL
-func (m *SearchModule) getPresignedURL(s3URL string) string {
-	if s3URL == "" || !strings.HasPrefix(s3URL, "s3://") {
-		return s3URL
-	}
-
-	// Parse s3://bucket/key format
-	withoutPrefix := strings.TrimPrefix(s3URL, "s3://")
-	parts := strings.SplitN(withoutPrefix, "/", 2)
-	if len(parts) != 2 {
-		return s3URL
-	}
-
-	key := parts[1]
-
-	presignClient := s3.NewPresignClient(m.s3Client)
-	presignedReq, err := presignClient.PresignGetObject(context.Background(), &s3.GetObjectInput{
-		Bucket: aws.String(m.s3Bucket),
-		Key:    aws.String(key),
-	}, s3.WithPresignExpires(time.Hour))
-	if err != nil {
-		return s3URL
-	}
-
-	return presignedReq.URL
-}
-
 // PlayerSearchResult represents a search result
 type PlayerSearchResult struct {
 	ID                string  `json:"id"`
 	FirstName         string  `json:"first_name"`
-	LastName          string  `json:"last_name"`
+	LastName          *string `json:"last_name,omitempty"` // masked by the privacy policy
 	LastNameInit      string  `json:"last_name_init"`
-	Age               int     `json:"age"`
+	Age               *int    `json:"age,omitempty"`
+	AgeGroup          string  `json:"age_group,omitempty"` // youngest eligible group on the cut-off date
 	Position          string  `json:"position"`
 	Country           string  `json:"country"`
 	State             *string `json:"state,omitempty"`
@@ -76,6 +51,8 @@ type PlayerSearchResult struct {
 	IsVerified        bool    `json:"is_verified"`
 	TournamentName    *string `json:"tournament_name,omitempty"`
 	AcademyName       *string `json:"academy_name,omitempty"`
+	Squad             *string `json:"squad,omitempty"`        // on tournament rosters
+	ShirtNumber       *int    `json:"shirt_number,omitempty"` // on tournament rosters
 	VideoCount        int     `json:"video_count"`
 }
 
@@ -97,7 +74,8 @@ func (m *SearchModule) SearchPlayers(c *gin.Context) {
 		Preload("Academy").
 		Preload("Highlights").
 		Where("deleted_at IS NULL").
-		Where("verification_status = ?", "verified")
+		Where("verification_status = ?", "verified").
+		Scopes(consent.Published(consent.ProfilePublication)) // Minors need guardian consent
 
 	// Full-text search if query provided
 	if query != "" {
@@ -135,7 +113,30 @@ func (m *SearchModule) SearchPlayers(c *gin.Context) {
 		}
 	}
 	if tournamentID := c.Query("tournament_id"); tournamentID != "" {
-		dbQuery = dbQuery.Where("tournament_id = ?", tournamentID)
+		dbQuery = dbQuery.Scopes(registrations.Entered(tournamentID))
+	}
+	// Age groups are taken on the cut-off: the given date, else the tournament's, else 1 January
+	cutoff := agegroup.Cutoff(time.Now().Year(), nil)
+	if v := c.Query("cutoff"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_CUTOFF", "message": "cutoff must be YYYY-MM-DD"}})
+			return
+		}
+		cutoff = d
+	} else if tid, err := uuid.Parse(c.Query("tournament_id")); err == nil {
+		var t domain.Tournament
+		if m.db.Select("id, year, age_cutoff_date").First(&t, "id = ?", tid).Error == nil {
+			cutoff = agegroup.TournamentCutoff(&t)
+		}
+	}
+	if name := c.Query("age_group"); name != "" {
+		group, ok := agegroup.Parse(name)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_AGE_GROUP", "message": "age_group must be one of U13, U15, U17, U20"}})
+			return
+		}
+		dbQuery = dbQuery.Where("date_of_birth > ?", group.BornAfter(cutoff))
 	}
 	if heightMin := c.Query("height_min"); heightMin != "" {
 		if h, err := strconv.Atoi(heightMin); err == nil {
@@ -173,38 +174,42 @@ func (m *SearchModule) SearchPlayers(c *gin.Context) {
 	dbQuery.Offset(offset).Limit(limit).Find(&players)
 
 	// Convert to search results
+	policy := privacy.For(c, m.db)
+	policy.Load(players)
 	results := make([]PlayerSearchResult, len(players))
 	for i, p := range players {
 		// Convert thumbnail URL to presigned URL if it's an S3 URL
 		var thumbnailURL *string
 		if p.ThumbnailURL != nil && *p.ThumbnailURL != "" {
-			url := m.getPresignedURL(*p.ThumbnailURL)
+			url := storage.SignedURL(m.store, *p.ThumbnailURL)
 			thumbnailURL = &url
 		}
 
 		// Convert profile photo URL to presigned URL if it's an S3 URL
 		var profilePhotoURL *string
 		if p.ProfilePhotoURL != nil && *p.ProfilePhotoURL != "" {
-			url := m.getPresignedURL(*p.ProfilePhotoURL)
+			url := storage.SignedURL(m.store, *p.ProfilePhotoURL)
 			profilePhotoURL = &url
 		}
 
 		// Get video thumbnail from first highlight if available (action shot > static photo)
 		var videoThumbnailURL *string
 		if len(p.Highlights) > 0 && p.Highlights[0].ThumbnailURL != nil && *p.Highlights[0].ThumbnailURL != "" {
-			url := m.getPresignedURL(*p.Highlights[0].ThumbnailURL)
+			url := storage.SignedURL(m.store, *p.Highlights[0].ThumbnailURL)
 			videoThumbnailURL = &url
 		}
 
+		masked := policy.Mask(&p)
 		results[i] = PlayerSearchResult{
 			ID:                p.ID.String(),
 			FirstName:         p.FirstName,
-			LastName:          p.LastName,
-			LastNameInit:      p.GetLastNameInit(),
-			Age:               p.GetAge(),
+			LastName:          masked.LastName,
+			LastNameInit:      masked.LastNameInit,
+			Age:               masked.Age,
+			AgeGroup:          policy.AgeGroup(&p, cutoff),
 			Position:          p.Position,
 			Country:           p.Country,
-			State:             p.State,
+			State:             masked.State,
 			HeightCm:          p.HeightCm,
 			PreferredFoot:     p.PreferredFoot,
 			ThumbnailURL:      thumbnailURL,
@@ -226,11 +231,13 @@ func (m *SearchModule) SearchPlayers(c *gin.Context) {
 		"data": gin.H{
 			"players": results,
 			"filters_applied": gin.H{
-				"query":    query,
-				"position": c.Query("position"),
-				"country":  c.Query("country"),
-				"age_min":  c.Query("age_min"),
-				"age_max":  c.Query("age_max"),
+				"query":     query,
+				"position":  c.Query("position"),
+				"country":   c.Query("country"),
+				"age_min":   c.Query("age_min"),
+				"age_max":   c.Query("age_max"),
+				"age_group": c.Query("age_group"),
+				"cutoff":    cutoff.Format("2006-01-02"),
 			},
 			"pagination": gin.H{
 				"page":        page,
@@ -265,9 +272,10 @@ func (m *SearchModule) GetFilterOptions(c *gin.Context) {
 	tournamentOptions := make([]gin.H, len(tournaments))
 	for i, t := range tournaments {
 		tournamentOptions[i] = gin.H{
-			"id":   t.ID,
-			"name": t.Name,
-			"year": t.Year,
+			"id":        t.ID,
+			"name":      t.Name,
+			"year":      t.Year,
+			"age_group": t.AgeGroup,
 		}
 	}
 
@@ -277,6 +285,7 @@ func (m *SearchModule) GetFilterOptions(c *gin.Context) {
 			"countries":      countries,
 			"positions":      positions,
 			"tournaments":    tournamentOptions,
+			"age_groups":     agegroup.Names(),
 			"preferred_foot": []string{"left", "right", "both"},
 		},
 	})
@@ -335,12 +344,13 @@ func (m *SearchModule) GetPublicTournaments(c *gin.Context) {
 	for i, t := range tournaments {
 		// Get player count for this tournament
 		var playerCount int64
-		m.db.Model(&domain.Player{}).Where("tournament_id = ? AND deleted_at IS NULL AND verification_status = ?", t.ID, "verified").Count(&playerCount)
+		m.db.Model(&domain.Player{}).Where("deleted_at IS NULL AND verification_status = ?", "verified").
+			Scopes(registrations.Entered(t.ID)).Count(&playerCount)
 
 		// Get cover image URL if exists
 		var coverImageURL *string
 		if t.CoverImageURL != nil && *t.CoverImageURL != "" {
-			url := m.getPresignedURL(*t.CoverImageURL)
+			url := storage.SignedURL(m.store, *t.CoverImageURL)
 			coverImageURL = &url
 		}
 
@@ -373,8 +383,7 @@ func (m *SearchModule) GetPublicTournaments(c *gin.Context) {
 
 // GetTournamentDetail returns detailed tournament info with players
 func (m *SearchModule) GetTournamentDetail(c *gin.Context) {
-	tournamentID := c.Param("id")
-	tid, err := strconv.Atoi(tournamentID)
+	tid, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_ID", "message": "Invalid tournament ID"}})
 		return
@@ -391,37 +400,62 @@ func (m *SearchModule) GetTournamentDetail(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset := (page - 1) * limit
 	position := c.Query("position")
+	cutoff := agegroup.TournamentCutoff(&tournament)
 
 	var players []domain.Player
 	var total int64
 
 	query := m.db.Model(&domain.Player{}).
-		Where("tournament_id = ? AND deleted_at IS NULL AND verification_status = ?", tid, "verified")
+		Where("deleted_at IS NULL AND verification_status = ?", "verified").
+		Scopes(registrations.Entered(tid), consent.Published(consent.ProfilePublication))
 	if position != "" {
 		query = query.Where("position = ?", position)
 	}
+	if name := c.Query("age_group"); name != "" {
+		group, ok := agegroup.Parse(name)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_AGE_GROUP", "message": "age_group must be one of U13, U15, U17, U20"}})
+			return
+		}
+		query = query.Where("date_of_birth > ?", group.BornAfter(cutoff))
+	}
 	query.Count(&total)
 
-	m.db.Preload("Academy").Preload("Highlights").
-		Where("tournament_id = ? AND deleted_at IS NULL AND verification_status = ?", tid, "verified").
+	query.Preload("Academy").Preload("Highlights").
 		Order("last_name ASC").
 		Offset(offset).Limit(limit).Find(&players)
 
+	// Squad and shirt number the players were entered with
+	entries := map[uuid.UUID]domain.TournamentRegistration{}
+	if len(players) > 0 {
+		ids := make([]uuid.UUID, len(players))
+		for i := range players {
+			ids[i] = players[i].ID
+		}
+		var rows []domain.TournamentRegistration
+		m.db.Where("tournament_id = ? AND player_id IN ?", tid, ids).Find(&rows)
+		for _, r := range rows {
+			entries[r.PlayerID] = r
+		}
+	}
+
+	policy := privacy.For(c, m.db)
+	policy.Load(players)
 	playerResults := make([]PlayerSearchResult, len(players))
 	for i, p := range players {
 		var thumbnailURL, profilePhotoURL, videoThumbnailURL *string
 		if p.ThumbnailURL != nil && *p.ThumbnailURL != "" {
-			url := m.getPresignedURL(*p.ThumbnailURL)
+			url := storage.SignedURL(m.store, *p.ThumbnailURL)
 			thumbnailURL = &url
 		}
 		if p.ProfilePhotoURL != nil && *p.ProfilePhotoURL != "" {
-			url := m.getPresignedURL(*p.ProfilePhotoURL)
+			url := storage.SignedURL(m.store, *p.ProfilePhotoURL)
 			profilePhotoURL = &url
 		}
 
 		for _, h := range p.Highlights {
 			if h.ThumbnailURL != nil && *h.ThumbnailURL != "" {
-				url := m.getPresignedURL(*h.ThumbnailURL)
+				url := storage.SignedURL(m.store, *h.ThumbnailURL)
 				videoThumbnailURL = &url
 				break
 			}
@@ -432,15 +466,17 @@ func (m *SearchModule) GetTournamentDetail(c *gin.Context) {
 			academyName = &p.Academy.Name
 		}
 
+		masked := policy.Mask(&p)
 		playerResults[i] = PlayerSearchResult{
 			ID:                p.ID.String(),
 			FirstName:         p.FirstName,
-			LastName:          p.LastName,
-			LastNameInit:      p.GetLastNameInit(),
-			Age:               p.GetAge(),
+			LastName:          masked.LastName,
+			LastNameInit:      masked.LastNameInit,
+			Age:               masked.Age,
+			AgeGroup:          policy.AgeGroup(&p, cutoff),
 			Position:          p.Position,
 			Country:           p.Country,
-			State:             p.State,
+			State:             masked.State,
 			HeightCm:          p.HeightCm,
 			PreferredFoot:     p.PreferredFoot,
 			ThumbnailURL:      thumbnailURL,
@@ -448,6 +484,8 @@ func (m *SearchModule) GetTournamentDetail(c *gin.Context) {
 			VideoThumbnailURL: videoThumbnailURL,
 			IsVerified:        p.VerificationStatus == "verified",
 			AcademyName:       academyName,
+			Squad:             entries[p.ID].Squad,
+			ShirtNumber:       entries[p.ID].ShirtNumber,
 			VideoCount:        len(p.Highlights),
 		}
 	}
@@ -455,7 +493,7 @@ func (m *SearchModule) GetTournamentDetail(c *gin.Context) {
 	// Get cover image URL if exists
 	var coverImageURL *string
 	if tournament.CoverImageURL != nil && *tournament.CoverImageURL != "" {
-		url := m.getPresignedURL(*tournament.CoverImageURL)
+		url := storage.SignedURL(m.store, *tournament.CoverImageURL)
 		coverImageURL = &url
 	}
 
@@ -471,6 +509,8 @@ func (m *SearchModule) GetTournamentDetail(c *gin.Context) {
 				"end_date":        tournament.EndDate,
 				"featured":        tournament.Featured,
 				"cover_image_url": coverImageURL,
+				"age_group":       tournament.AgeGroup,
+				"age_cutoff_date": cutoff.Format("2006-01-02"),
 			},
 			"players": playerResults,
 			"pagination": gin.H{
@@ -482,3 +522,359 @@ func (m *SearchModule) GetTournamentDetail(c *gin.Context) {
 		},
 	})
 }
+
+// FixtureResult is a match in a public fixture list or bracket
+type FixtureResult struct {
+	ID            uuid.UUID  `json:"id"`
+	Title         string     `json:"title"`
+	MatchDate     time.Time  `json:"match_date"`
+	Location      *string    `json:"location,omitempty"`
+	Stage         *string    `json:"stage,omitempty"`
+	Status        string     `json:"status"`
+	MatchNumber   *int       `json:"match_number,omitempty"`
+	GroupID       *uuid.UUID `json:"group_id,omitempty"`
+	Round         *int       `json:"round,omitempty"`
+	BracketSlot   *int       `json:"bracket_slot,omitempty"`
+	HomeTeamID    *uuid.UUID `json:"home_team_id,omitempty"`
+	HomeTeam      *string    `json:"home_team,omitempty"`
+	AwayTeamID    *uuid.UUID `json:"away_team_id,omitempty"`
+	AwayTeam      *string    `json:"away_team,omitempty"`
+	HomeScore     *int       `json:"home_score,omitempty"`
+	AwayScore     *int       `json:"away_score,omitempty"`
+	HomePenalties *int       `json:"home_penalties,omitempty"`
+	AwayPenalties *int       `json:"away_penalties,omitempty"`
+	WinnerTeamID  *uuid.UUID `json:"winner_team_id,omitempty"`
+	NextMatchID   *uuid.UUID `json:"next_match_id,omitempty"`
+	NextMatchSide *string    `json:"next_match_side,omitempty"`
+}
+
+func fixtureResult(m domain.Match) FixtureResult {
+	return FixtureResult{
+		ID:            m.ID,
+		Title:         m.Title,
+		MatchDate:     m.MatchDate,
+		Location:      m.Location,
+		Stage:         m.Stage,
+		Status:        m.Status,
+		MatchNumber:   m.MatchNumber,
+		GroupID:       m.GroupID,
+		Round:         m.Round,
+		BracketSlot:   m.BracketSlot,
+		HomeTeamID:    m.HomeTeamID,
+		HomeTeam:      m.HomeTeam,
+		AwayTeamID:    m.AwayTeamID,
+		AwayTeam:      m.AwayTeam,
+		HomeScore:     m.HomeScore,
+		AwayScore:     m.AwayScore,
+		HomePenalties: m.HomePenalties,
+		AwayPenalties: m.AwayPenalties,
+		WinnerTeamID:  m.WinnerTeamID,
+		NextMatchID:   m.NextMatchID,
+		NextMatchSide: m.NextMatchSide,
+	}
+}
+
+// GetTournamentFixtures returns a public tournament's groups and matches in the
+// order they're played. Filter with group_id or stage.
+func (m *SearchModule) GetTournamentFixtures(c *gin.Context) {
+	tournament, ok := m.publicTournament(c)
+	if !ok {
+		return
+	}
+
+	var groups []domain.TournamentGroup
+	m.db.Preload("Teams", func(db *gorm.DB) *gorm.DB { return db.Order("seed NULLS LAST, name") }).
+		Where("tournament_id = ?", tournament.ID).Order("position, name").Find(&groups)
+
+	query := m.db.Where("tournament_id = ?", tournament.ID)
+	if groupID := c.Query("group_id"); groupID != "" {
+		gid, err := uuid.Parse(groupID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_ID", "message": "Invalid group ID"}})
+			return
+		}
+		query = query.Where("group_id = ?", gid)
+	}
+	if stage := c.Query("stage"); stage != "" {
+		query = query.Where("stage = ?", stage)
+	}
+	var matches []domain.Match
+	if err := query.Order("match_date ASC, match_number ASC").Find(&matches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "INTERNAL_ERROR", "message": "Failed to fetch fixtures"}})
+		return
+	}
+
+	results := make([]FixtureResult, len(matches))
+	for i, match := range matches {
+		results[i] = fixtureResult(match)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"tournament_id": tournament.ID,
+			"groups":        groups,
+			"matches":       results,
+		},
+	})
+}
+
+// GetTournamentBracket returns a public tournament's knockout bracket by round.
+// The champion is set once the final has been won.
+func (m *SearchModule) GetTournamentBracket(c *gin.Context) {
+	tournament, ok := m.publicTournament(c)
+	if !ok {
+		return
+	}
+
+	var matches []domain.Match
+	if err := m.db.Where("tournament_id = ? AND bracket_slot IS NOT NULL", tournament.ID).
+		Order("round ASC, bracket_slot ASC").Find(&matches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "INTERNAL_ERROR", "message": "Failed to fetch bracket"}})
+		return
+	}
+
+	type bracketRound struct {
+		Round   int             `json:"round"`
+		Stage   *string         `json:"stage,omitempty"`
+		Matches []FixtureResult `json:"matches"`
+	}
+	var rounds []bracketRound
+	var champion *uuid.UUID
+	for _, match := range matches {
+		if len(rounds) == 0 || rounds[len(rounds)-1].Round != *match.Round {
+			rounds = append(rounds, bracketRound{Round: *match.Round, Stage: match.Stage})
+		}
+		last := &rounds[len(rounds)-1]
+		last.Matches = append(last.Matches, fixtureResult(match))
+		if match.NextMatchID == nil {
+			champion = match.WinnerTeamID
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"tournament_id":    tournament.ID,
+			"rounds":           rounds,
+			"champion_team_id": champion,
+		},
+	})
+}
+
+// publicTournament loads the public tournament in the id path parameter, writing
+// the error response if it can't
+func (m *SearchModule) publicTournament(c *gin.Context) (*domain.Tournament, bool) {
+	tid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_ID", "message": "Invalid tournament ID"}})
+		return nil, false
+	}
+	var tournament domain.Tournament
+	if err := m.db.First(&tournament, "id = ? AND is_public = ?", tid, true).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"code": "NOT_FOUND", "message": "Tournament not found"}})
+		return nil, false
+	}
+	return &tournament, true
+}
+
+// GetTournamentStandings returns a public tournament's group tables
+func (m *SearchModule) GetTournamentStandings(c *gin.Context) {
+	tournament, ok := m.publicTournament(c)
+	if !ok {
+		return
+	}
+
+	stats, err := standings.Get(m.db, tournament.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "INTERNAL_ERROR", "message": "Failed to compute standings"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"tournament_id": tournament.ID,
+			"tie_breakers":  stats.TieBreakers,
+			"groups":        stats.Groups,
+			"computed_at":   stats.ComputedAt,
+		},
+	})
+}
+
+// LeaderboardEntry is a player's place on a public leaderboard
+type LeaderboardEntry struct {
+	Rank         int     `json:"rank"`
+	PlayerID     string  `json:"player_id"`
+	FirstName    string  `json:"first_name"`
+	LastName     *string `json:"last_name,omitempty"`
+	LastNameInit string  `json:"last_name_init"`
+	Position     string  `json:"position"`
+	AcademyName  *string `json:"academy_name,omitempty"`
+	Team         *string `json:"team,omitempty"`
+	Value        int     `json:"value"`
+	Matches      int     `json:"matches"`
+}
+
+// GetTournamentLeaderboards returns a public tournament's top scorers, assists,
+// minutes and goalkeeper clean sheets. limit sets the length of each board
+// (default 10), and stat picks a single board.
+func (m *SearchModule) GetTournamentLeaderboards(c *gin.Context) {
+	tournament, ok := m.publicTournament(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > standings.MaxLeaders {
+		limit = 10
+	}
+
+	stats, err := standings.Get(m.db, tournament.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "INTERNAL_ERROR", "message": "Failed to compute leaderboards"}})
+		return
+	}
+	boards := map[string][]standings.Leader{
+		"goals":        stats.Leaderboards.Goals,
+		"assists":      stats.Leaderboards.Assists,
+		"minutes":      stats.Leaderboards.Minutes,
+		"clean_sheets": stats.Leaderboards.CleanSheets,
+	}
+	if stat := c.Query("stat"); stat != "" {
+		board, ok := boards[stat]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"code": "INVALID_STAT", "message": "stat must be one of goals, assists, minutes, clean_sheets"}})
+			return
+		}
+		boards = map[string][]standings.Leader{stat: board}
+	}
+
+	// The cache holds only totals; players are looked up now so the listing
+	// follows their current consent and the viewer's privacy level
+	var ids []uuid.UUID
+	for _, board := range boards {
+		for _, l := range board {
+			ids = append(ids, l.PlayerID)
+		}
+	}
+	var players []domain.Player
+	if len(ids) > 0 {
+		m.db.Preload("Academy").
+			Where("id IN ? AND deleted_at IS NULL AND verification_status = ?", ids, "verified").
+			Scopes(consent.Published(consent.ProfilePublication)).
+			Find(&players)
+	}
+	policy := privacy.For(c, m.db)
+	policy.Load(players)
+	byID := make(map[uuid.UUID]*domain.Player, len(players))
+	for i := range players {
+		byID[players[i].ID] = &players[i]
+	}
+
+	data := gin.H{}
+	for stat, board := range boards {
+		entries := []LeaderboardEntry{}
+		for _, l := range board {
+			p, ok := byID[l.PlayerID]
+			if !ok {
+				continue
+			}
+			var academyName *string
+			if p.Academy != nil {
+				academyName = &p.Academy.Name
+			}
+			masked := policy.Mask(p)
+			entries = append(entries, LeaderboardEntry{
+				Rank:         len(entries) + 1,
+				PlayerID:     p.ID.String(),
+				FirstName:    p.FirstName,
+				LastName:     masked.LastName,
+				LastNameInit: masked.LastNameInit,
+				Position:     p.Position,
+				AcademyName:  academyName,
+				Team:         l.Team,
+				Value:        l.Value,
+				Matches:      l.Matches,
+			})
+			if len(entries) == limit {
+				break
+			}
+		}
+		data[stat] = entries
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"tournament_id": tournament.ID,
+			"leaderboards":  data,
+			"computed_at":   stats.ComputedAt,
+		},
+	})
+}
+
+// TeamResult is a team in a public tournament, with its record so far
+type TeamResult struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	ShortName   *string        `json:"short_name,omitempty"`
+	AcademyID   *uuid.UUID     `json:"academy_id,omitempty"`
+	AcademyName *string        `json:"academy_name,omitempty"`
+	GroupID     *uuid.UUID     `json:"group_id,omitempty"`
+	Seed        *int           `json:"seed,omitempty"`
+	Record      *standings.Row `json:"record,omitempty"`
+}
+
+// GetTournamentTeams returns a public tournament's teams with their records
+// across all their completed matches
+func (m *SearchModule) GetTournamentTeams(c *gin.Context) {
+	tournament, ok := m.publicTournament(c)
+	if !ok {
+		return
+	}
+
+	var teams []domain.Team
+	if err := m.db.Preload("Academy").Where("tournament_id = ?", tournament.ID).
+		Order("name").Find(&teams).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "INTERNAL_ERROR", "message": "Failed to fetch teams"}})
+		return
+	}
+	stats, err := standings.Get(m.db, tournament.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"code": "INTERNAL_ERROR", "message": "Failed to compute team records"}})
+		return
+	}
+	records := make(map[uuid.UUID]*standings.Row, len(stats.Records))
+	for i, r := range stats.Records {
+		if r.TeamID != nil {
+			records[*r.TeamID] = &stats.Records[i]
+		}
+	}
+
+	results := make([]TeamResult, len(teams))
+	for i, t := range teams {
+		var academyName *string
+		if t.Academy != nil {
+			academyName = &t.Academy.Name
+		}
+		results[i] = TeamResult{
+			ID:          t.ID,
+			Name:        t.Name,
+			ShortName:   t.ShortName,
+			AcademyID:   t.AcademyID,
+			AcademyName: academyName,
+			GroupID:     t.GroupID,
+			Seed:        t.Seed,
+			Record:      records[t.ID],
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"tournament_id": tournament.ID,
+			"teams":         results,
+			"computed_at":   stats.ComputedAt,
+		},
+	})
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Message is a notification to one or more users
+type Message struct {
+	Type  string
+	Title string
+	Body  string
+	Link  string // app path the notification opens, optional
+}
+
+// Send stores the message for each user, skipping duplicates. Email delivery isn't
+// wired up yet, so it's logged like the other outgoing emails. Failures are logged
+// and never fail the action that triggered the notification.
+func Send(db *gorm.DB, msg Message, userIDs ...uuid.UUID) {
+	seen := map[uuid.UUID]bool{}
+	var notifications []domain.Notification
+	for _, id := range userIDs {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		n := domain.Notification{
+			UserID:    id,
+			Type:      msg.Type,
+			Title:     msg.Title,
+			Body:      msg.Body,
+			CreatedAt: time.Now(),
+		}
+		if msg.Link != "" {
+			link := msg.Link
+			n.Link = &link
+		}
+		notifications = append(notifications, n)
+	}
+	if len(notifications) == 0 {
+		return
+	}
+	if err := db.Create(&notifications).Error; err != nil {
+		log.Printf("notify: failed to store %s notifications: %v", msg.Type, err)
+		return
+	}
+	for _, n := range notifications {
+		// TODO: Send email as well once an email provider is integrated
+		log.Printf("📧 Notification for user %s: %s", n.UserID, n.Title)
+	}
+}
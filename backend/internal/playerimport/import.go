@@ -0,0 +1,543 @@
+// Package playerimport creates players in bulk from a spreadsheet, e.g. after a
+// talent day. Files are validated in full before anything is written, so an
+// import either creates every row or none.
+package playerimport
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/duplicates"
+	"github.com/unicorn-sport/backend/internal/registrations"
+)
+
+// MaxRows caps one import
+const MaxRows = 1000
+
+// Fields a column can be mapped to
+const (
+	FieldFirstName      = "first_name"
+	FieldLastName       = "last_name"
+	FieldDateOfBirth    = "date_of_birth"
+	FieldPosition       = "position"
+	FieldPreferredFoot  = "preferred_foot"
+	FieldHeightCm       = "height_cm"
+	FieldWeightKg       = "weight_kg"
+	FieldCountry        = "country"
+	FieldState          = "state"
+	FieldCity           = "city"
+	FieldSchoolName     = "school_name"
+	FieldAcademy        = "academy"         // academy ID or name
+	FieldTournament     = "tournament"      // tournament ID or name
+	FieldTournamentYear = "tournament_year" // tells apart tournaments with the same name
+	FieldSquad          = "squad"
+	FieldShirtNumber    = "shirt_number"
+)
+
+// Fields lists the importable fields
+var Fields = []string{
+	FieldFirstName, FieldLastName, FieldDateOfBirth, FieldPosition, FieldPreferredFoot,
+	FieldHeightCm, FieldWeightKg, FieldCountry, FieldState, FieldCity, FieldSchoolName,
+	FieldAcademy, FieldTournament, FieldTournamentYear, FieldSquad, FieldShirtNumber,
+}
+
+// Positions are the positions a player can be imported with, as offered in the
+// admin player form
+var Positions = []string{
+	"Goalkeeper", "Defender", "Center Back", "Full Back", "Wing Back",
+	"Midfielder", "Defensive Midfielder", "Central Midfielder", "Attacking Midfielder",
+	"Forward", "Winger", "Striker",
+}
+
+// DateFormats maps the accepted date_format options to layouts. Dates may use /,
+// - or . as separators, and XLSX date cells are always understood.
+var DateFormats = map[string]string{
+	"YYYY-MM-DD": "2006/1/2",
+	"DD/MM/YYYY": "2/1/2006",
+	"MM/DD/YYYY": "1/2/2006",
+}
+
+// DefaultDateFormat is used when no date_format is given
+const DefaultDateFormat = "YYYY-MM-DD"
+
+// Options control how a file's rows become players. Academy and tournament apply
+// to rows that don't name their own.
+type Options struct {
+	Mapping      map[string]string // field -> column header; unmapped fields use a column named like the field
+	DateFormat   string
+	Country      string
+	AcademyID    *uuid.UUID
+	TournamentID *uuid.UUID
+}
+
+// Columns is the position in the file of each mapped field
+type Columns map[string]int
+
+// RowError is a problem with one row. Field is empty for problems with the row as
+// a whole.
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Row is a validated row, ready to be created
+type Row struct {
+	Line        int
+	Player      domain.Player
+	Academy     *domain.Academy
+	Tournament  *domain.Tournament
+	Squad       *string
+	ShirtNumber *int
+}
+
+// MapColumns finds the column of each field. Errors are problems with the file
+// or the mapping as a whole, e.g. a required column that's missing.
+func MapColumns(header []string, opts Options) (Columns, error) {
+	index := map[string]int{}
+	for i, name := range header {
+		key := headerKey(name)
+		if _, ok := index[key]; !ok && key != "" {
+			index[key] = i
+		}
+	}
+
+	known := map[string]bool{}
+	for _, f := range Fields {
+		known[f] = true
+	}
+	for field := range opts.Mapping {
+		if !known[field] {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+	}
+
+	cols := Columns{}
+	for _, field := range Fields {
+		header, mapped := opts.Mapping[field]
+		if !mapped {
+			header = field
+		}
+		if header == "" {
+			continue
+		}
+		i, ok := index[headerKey(header)]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("column %q mapped to %s is not in the file", header, field)
+			}
+			continue
+		}
+		cols[field] = i
+	}
+
+	required := []string{FieldFirstName, FieldLastName, FieldDateOfBirth, FieldPosition}
+	if opts.Country == "" {
+		required = append(required, FieldCountry)
+	}
+	for _, field := range required {
+		if _, ok := cols[field]; !ok {
+			return nil, fmt.Errorf("no column for %s; add one or map it", field)
+		}
+	}
+	return cols, nil
+}
+
+// headerKey matches headers case-insensitively and treats spaces and hyphens as
+// underscores, so "Date of Birth" finds date_of_birth
+func headerKey(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}), "_")
+}
+
+// Validate checks every row, resolving academy and tournament references and
+// looking for players that already exist. Rows with problems are left out of
+// rows and reported in rowErrors; err is only for database failures.
+func Validate(db *gorm.DB, table *Table, cols Columns, opts Options) (rows []Row, rowErrors []RowError, err error) {
+	layout, ok := DateFormats[opts.DateFormat]
+	if !ok {
+		layout = DateFormats[DefaultDateFormat]
+	}
+
+	refs, err := loadReferences(db, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := map[string]int{}
+	for i, record := range table.Records {
+		line := table.Lines[i]
+		field := func(name string) string {
+			if c, ok := cols[name]; ok && c < len(record) {
+				return strings.TrimSpace(record[c])
+			}
+			return ""
+		}
+		optional := func(name string) *string {
+			if v := field(name); v != "" {
+				return &v
+			}
+			return nil
+		}
+		var problems []RowError
+		problem := func(name, format string, args ...interface{}) {
+			problems = append(problems, RowError{Row: line, Field: name, Message: fmt.Sprintf(format, args...)})
+		}
+
+		p := domain.Player{
+			FirstName:          field(FieldFirstName),
+			LastName:           field(FieldLastName),
+			Country:            field(FieldCountry),
+			State:              optional(FieldState),
+			City:               optional(FieldCity),
+			SchoolName:         optional(FieldSchoolName),
+			VerificationStatus: "pending",
+		}
+		if p.FirstName == "" {
+			problem(FieldFirstName, "first name is required")
+		}
+		if p.LastName == "" {
+			problem(FieldLastName, "last name is required")
+		}
+		if p.Country == "" {
+			p.Country = opts.Country
+		}
+		if p.Country == "" {
+			problem(FieldCountry, "country is required")
+		}
+
+		if v := field(FieldDateOfBirth); v == "" {
+			problem(FieldDateOfBirth, "date of birth is required")
+		} else if dob, err := ParseDate(v, layout); err != nil {
+			problem(FieldDateOfBirth, "%q is not a date in %s format", v, formatName(opts.DateFormat))
+		} else if dob.After(time.Now()) {
+			problem(FieldDateOfBirth, "date of birth is in the future")
+		} else {
+			p.DateOfBirth = dob
+		}
+
+		if v := field(FieldPosition); v == "" {
+			problem(FieldPosition, "position is required")
+		} else if pos, ok := position(v); !ok {
+			problem(FieldPosition, "unknown position %q", v)
+		} else {
+			p.Position = pos
+		}
+
+		if v := field(FieldPreferredFoot); v != "" {
+			foot := strings.ToLower(v)
+			if foot != "left" && foot != "right" && foot != "both" {
+				problem(FieldPreferredFoot, "preferred foot must be left, right or both")
+			} else {
+				p.PreferredFoot = &foot
+			}
+		}
+		p.HeightCm = number(field(FieldHeightCm), 100, 250, func(format string, args ...interface{}) {
+			problem(FieldHeightCm, "height "+format, args...)
+		})
+		p.WeightKg = number(field(FieldWeightKg), 30, 150, func(format string, args ...interface{}) {
+			problem(FieldWeightKg, "weight "+format, args...)
+		})
+
+		row := Row{Line: line, Squad: optional(FieldSquad)}
+		row.ShirtNumber = number(field(FieldShirtNumber), 0, 999, func(format string, args ...interface{}) {
+			problem(FieldShirtNumber, "shirt number "+format, args...)
+		})
+
+		if v := field(FieldAcademy); v != "" {
+			if a, msg := refs.academy(v); msg != "" {
+				problem(FieldAcademy, "%s", msg)
+			} else {
+				row.Academy = a
+			}
+		} else {
+			row.Academy = refs.defaultAcademy
+		}
+		if v := field(FieldTournament); v != "" {
+			if t, msg := refs.tournament(v, field(FieldTournamentYear)); msg != "" {
+				problem(FieldTournament, "%s", msg)
+			} else {
+				row.Tournament = t
+			}
+		} else {
+			row.Tournament = refs.defaultTournament
+		}
+		if row.Tournament == nil && (row.Squad != nil || row.ShirtNumber != nil) {
+			problem(FieldTournament, "squad and shirt number need a tournament")
+		}
+
+		if len(problems) == 0 {
+			key := playerKey(p.FirstName, p.LastName, p.DateOfBirth)
+			if first, ok := seen[key]; ok {
+				problem("", "same player as row %d", first)
+			} else {
+				seen[key] = line
+			}
+		}
+		if len(problems) > 0 {
+			rowErrors = append(rowErrors, problems...)
+			continue
+		}
+
+		if row.Academy != nil {
+			p.AcademyID = &row.Academy.ID
+		}
+		if row.Tournament != nil {
+			p.TournamentID = &row.Tournament.ID
+			p.TournamentYear = &row.Tournament.Year
+		}
+		row.Player = p
+		rows = append(rows, row)
+	}
+
+	existing, err := existingPlayers(db, rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	kept := rows[:0]
+	for _, row := range rows {
+		if existing[playerKey(row.Player.FirstName, row.Player.LastName, row.Player.DateOfBirth)] {
+			rowErrors = append(rowErrors, RowError{Row: row.Line, Message: "a player with this name and date of birth already exists"})
+			continue
+		}
+		kept = append(kept, row)
+	}
+	sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Row < rowErrors[j].Row })
+	return kept, rowErrors, nil
+}
+
+// Create inserts the players with their academy memberships and tournament
+// registrations. Run it in a transaction: it stops at the first failure.
+func Create(tx *gorm.DB, rows []Row, createdBy uuid.UUID) ([]domain.Player, error) {
+	now := time.Now()
+	players := make([]domain.Player, len(rows))
+	for i := range rows {
+		players[i] = rows[i].Player
+		players[i].CreatedBy = createdBy
+		players[i].CreatedAt = now
+		players[i].UpdatedAt = now
+	}
+	if len(players) == 0 {
+		return players, nil
+	}
+	if err := tx.CreateInBatches(&players, 100).Error; err != nil {
+		return nil, err
+	}
+
+	var memberships []domain.AcademyPlayer
+	var entries []domain.TournamentRegistration
+	for i, row := range rows {
+		if row.Academy != nil {
+			role := players[i].Position
+			memberships = append(memberships, domain.AcademyPlayer{
+				AcademyID:   row.Academy.ID,
+				PlayerID:    players[i].ID,
+				SquadRole:   &role,
+				SquadStatus: "active",
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			})
+		}
+		if row.Tournament != nil {
+			entries = append(entries, domain.TournamentRegistration{
+				TournamentID: row.Tournament.ID,
+				PlayerID:     players[i].ID,
+				Squad:        row.Squad,
+				ShirtNumber:  row.ShirtNumber,
+				Status:       registrations.StatusRegistered,
+				CreatedBy:    &createdBy,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			})
+		}
+	}
+	if len(memberships) > 0 {
+		if err := tx.CreateInBatches(&memberships, 100).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(entries) > 0 {
+		if err := tx.CreateInBatches(&entries, 100).Error; err != nil {
+			return nil, err
+		}
+	}
+	return players, nil
+}
+
+// ParseDate reads a date in the given layout, with any of / - . as separators. A
+// plain number is taken as an Excel date serial, which is how XLSX stores dates.
+func ParseDate(v, layout string) (time.Time, error) {
+	if serial, err := strconv.ParseFloat(v, 64); err == nil {
+		if serial < 1 || serial > 2958465 {
+			return time.Time{}, fmt.Errorf("date serial %v out of range", serial)
+		}
+		// Serials count days from 30 December 1899, which absorbs Excel's 1900 leap year bug
+		return time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(math.Floor(serial))), nil
+	}
+	v = strings.NewReplacer("-", "/", ".", "/").Replace(v)
+	// Drop a time part, e.g. from dates exported as "2009-03-14 00:00:00"
+	if i := strings.IndexAny(v, " T"); i > 0 {
+		v = v[:i]
+	}
+	return time.Parse(layout, v)
+}
+
+func formatName(option string) string {
+	if _, ok := DateFormats[option]; ok {
+		return option
+	}
+	return DefaultDateFormat
+}
+
+func position(v string) (string, bool) {
+	v = strings.Join(strings.Fields(v), " ")
+	for _, p := range Positions {
+		if strings.EqualFold(p, v) || strings.EqualFold(strings.ReplaceAll(p, "Center", "Centre"), v) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// number parses an optional whole number within [min, max], reporting problems
+// through problem
+func number(v string, min, max int, problem func(format string, args ...interface{})) *int {
+	if v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f != math.Trunc(f) {
+		problem("must be a whole number")
+		return nil
+	}
+	n := int(f)
+	if n < min || n > max {
+		problem("must be between %d and %d", min, max)
+		return nil
+	}
+	return &n
+}
+
+func playerKey(first, last string, dob time.Time) string {
+	return duplicates.NormalizeName(first) + "|" + duplicates.NormalizeName(last) + "|" + dob.Format("2006-01-02")
+}
+
+// existingPlayers returns the keys of players already in the database with the
+// same name and date of birth as one of the rows
+func existingPlayers(db *gorm.DB, rows []Row) (map[string]bool, error) {
+	existing := map[string]bool{}
+	if len(rows) == 0 {
+		return existing, nil
+	}
+	dobs := map[time.Time]bool{}
+	var dates []time.Time
+	for _, row := range rows {
+		if !dobs[row.Player.DateOfBirth] {
+			dobs[row.Player.DateOfBirth] = true
+			dates = append(dates, row.Player.DateOfBirth)
+		}
+	}
+	var players []domain.Player
+	if err := db.Select("first_name, last_name, date_of_birth").
+		Where("deleted_at IS NULL AND date_of_birth IN ?", dates).
+		Find(&players).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range players {
+		existing[playerKey(p.FirstName, p.LastName, p.DateOfBirth)] = true
+	}
+	return existing, nil
+}
+
+// references resolves academy and tournament columns, which may hold an ID or a
+// name
+type references struct {
+	academies         []domain.Academy
+	tournaments       []domain.Tournament
+	defaultAcademy    *domain.Academy
+	defaultTournament *domain.Tournament
+}
+
+func loadReferences(db *gorm.DB, opts Options) (*references, error) {
+	refs := &references{}
+	if err := db.Select("id, name").Find(&refs.academies).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Select("id, name, year").Find(&refs.tournaments).Error; err != nil {
+		return nil, err
+	}
+	if opts.AcademyID != nil {
+		refs.defaultAcademy, _ = refs.academy(opts.AcademyID.String())
+	}
+	if opts.TournamentID != nil {
+		refs.defaultTournament, _ = refs.tournament(opts.TournamentID.String(), "")
+	}
+	return refs, nil
+}
+
+func (r *references) academy(v string) (*domain.Academy, string) {
+	if id, err := uuid.Parse(v); err == nil {
+		for i := range r.academies {
+			if r.academies[i].ID == id {
+				return &r.academies[i], ""
+			}
+		}
+		return nil, "no academy with ID " + v
+	}
+	var found []*domain.Academy
+	for i := range r.academies {
+		if strings.EqualFold(strings.TrimSpace(r.academies[i].Name), v) {
+			found = append(found, &r.academies[i])
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Sprintf("no academy named %q", v)
+	case 1:
+		return found[0], ""
+	default:
+		return nil, fmt.Sprintf("%d academies are named %q; use the academy ID", len(found), v)
+	}
+}
+
+func (r *references) tournament(v, year string) (*domain.Tournament, string) {
+	if id, err := uuid.Parse(v); err == nil {
+		for i := range r.tournaments {
+			if r.tournaments[i].ID == id {
+				return &r.tournaments[i], ""
+			}
+		}
+		return nil, "no tournament with ID " + v
+	}
+	var y int
+	if year != "" {
+		var err error
+		if y, err = strconv.Atoi(year); err != nil {
+			return nil, fmt.Sprintf("tournament year %q is not a year", year)
+		}
+	}
+	var found []*domain.Tournament
+	for i := range r.tournaments {
+		t := &r.tournaments[i]
+		if strings.EqualFold(strings.TrimSpace(t.Name), v) && (y == 0 || t.Year == y) {
+			found = append(found, t)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Sprintf("no tournament named %q", v)
+	case 1:
+		return found[0], ""
+	default:
+		return nil, fmt.Sprintf("%d tournaments are named %q; add a tournament year or use the tournament ID", len(found), v)
+	}
+}
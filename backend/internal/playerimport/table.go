@@ -0,0 +1,255 @@
+package playerimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Table is a spreadsheet's header row and the non-blank rows below it. Lines
+// holds each record's line number in the file, for error reports.
+type Table struct {
+	Header  []string
+	Records [][]string
+	Lines   []int
+}
+
+// ReadTable reads a CSV or XLSX file, telling them apart by content rather than
+// by the file name. Only the first sheet of a workbook is read, and blank rows
+// are skipped.
+func ReadTable(data []byte) (*Table, error) {
+	var rows [][]string
+	var err error
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		rows, err = readXLSX(data)
+	} else {
+		rows, err = readCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var table Table
+	for i, row := range rows {
+		switch {
+		case blank(row):
+		case table.Header == nil:
+			table.Header = row
+		default:
+			table.Records = append(table.Records, row)
+			table.Lines = append(table.Lines, i+1)
+		}
+	}
+	if table.Header == nil {
+		return nil, errors.New("file is empty")
+	}
+	return &table, nil
+}
+
+func blank(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	return rows, nil
+}
+
+// The parts of SpreadsheetML needed to read cell values
+type (
+	xlsxWorkbook struct {
+		Sheets []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	xlsxRelationships struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	xlsxText struct {
+		T    string `xml:"t"`
+		Runs []struct {
+			T string `xml:"t"`
+		} `xml:"r"`
+	}
+	xlsxSharedStrings struct {
+		Items []xlsxText `xml:"si"`
+	}
+	xlsxSheet struct {
+		Rows []struct {
+			Num   int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string    `xml:"r,attr"`
+				Type   string    `xml:"t,attr"`
+				Value  string    `xml:"v"`
+				Inline *xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+)
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+// Limits on a sheet's size, so a crafted file can't make the reader allocate
+// without bound. maxColumns and maxRowNumber are Excel's own limits (XFD1048576).
+const (
+	maxColumns   = 16384
+	maxRowNumber = 1048576
+	maxCells     = 1 << 20
+)
+
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("invalid XLSX file")
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	decode := func(name string, v interface{}) error {
+		f, ok := files[name]
+		if !ok {
+			return fmt.Errorf("invalid XLSX file: %s is missing", name)
+		}
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		if err := xml.NewDecoder(io.LimitReader(r, 64<<20)).Decode(v); err != nil {
+			return fmt.Errorf("invalid XLSX file: %v", err)
+		}
+		return nil
+	}
+
+	sheetPath := firstSheet(files, decode)
+
+	var shared xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decode("xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	var sheet xlsxSheet
+	if err := decode(sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+	rows := make([][]string, 0, len(sheet.Rows))
+	cells := 0
+	for _, r := range sheet.Rows {
+		// Empty rows are left out of the sheet, so pad up to the row's number
+		if r.Num > maxRowNumber {
+			return nil, fmt.Errorf("invalid XLSX file: row %d is past the last row", r.Num)
+		}
+		for r.Num > 0 && len(rows) < r.Num-1 {
+			rows = append(rows, nil)
+		}
+		var row []string
+		for i, c := range r.Cells {
+			col := i
+			if c.Ref != "" {
+				if col, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			value := c.Value
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(c.Value)
+				if err != nil || n < 0 || n >= len(shared.Items) {
+					return nil, fmt.Errorf("invalid XLSX file: cell %s has a bad shared string", c.Ref)
+				}
+				value = shared.Items[n].String()
+			case "inlineStr":
+				if c.Inline != nil {
+					value = c.Inline.String()
+				}
+			case "b":
+				value = map[string]string{"1": "TRUE", "0": "FALSE"}[c.Value]
+			}
+			if col >= len(row) {
+				if cells += col + 1 - len(row); cells > maxCells {
+					return nil, errors.New("the sheet has too many cells")
+				}
+				row = append(row, make([]string, col+1-len(row))...)
+			}
+			row[col] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// firstSheet finds the path of the workbook's first sheet
+func firstSheet(files map[string]*zip.File, decode func(string, interface{}) error) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+	var wb xlsxWorkbook
+	var rels xlsxRelationships
+	if decode("xl/workbook.xml", &wb) != nil || len(wb.Sheets) == 0 ||
+		decode("xl/_rels/workbook.xml.rels", &rels) != nil {
+		return fallback
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[0].RelID {
+			continue
+		}
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+		if _, ok := files[target]; ok {
+			return target
+		}
+	}
+	return fallback
+}
+
+// columnIndex turns a cell reference such as "C12" into a zero-based column
+func columnIndex(ref string) (int, error) {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		if col = col*26 + int(r-'A') + 1; col > maxColumns {
+			return 0, fmt.Errorf("invalid XLSX file: cell %q is past the last column", ref)
+		}
+	}
+	if col == 0 {
+		return 0, fmt.Errorf("invalid XLSX file: bad cell reference %q", ref)
+	}
+	return col - 1, nil
+}
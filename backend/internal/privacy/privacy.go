@@ -0,0 +1,244 @@
+package privacy
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/agegroup"
+	"github.com/unicorn-sport/backend/internal/consent"
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Every serializer that puts a player's personal details in a response goes through
+// a Policy, so what each viewer sees is decided here and nowhere else. First name,
+// position and country are always shown; the fields below depend on who is asking
+// and on whether the player is a minor.
+
+// Level is how much personal data a viewer may see
+type Level int
+
+const (
+	Public Level = iota // anonymous visitors and free accounts
+	Scout               // active scout subscription
+	Pro                 // active pro or club subscription
+	Full                // admins and the player themselves
+)
+
+// tierLevels maps active subscription tiers to viewer levels
+var tierLevels = map[string]Level{
+	"scout": Scout,
+	"pro":   Pro,
+	"club":  Pro,
+}
+
+// Field is a masked player field
+type Field string
+
+// Masked fields
+const (
+	LastName    Field = "last_name"
+	Age         Field = "age"
+	DateOfBirth Field = "date_of_birth"
+	City        Field = "city"
+	State       Field = "state"
+	SchoolName  Field = "school_name"
+)
+
+// rule is the lowest viewer level that sees a field, for adults and minors
+type rule struct {
+	Adult Level
+	Minor Level
+}
+
+// policy is the single source of field visibility. Minors without a current
+// profile publication consent show nothing beyond the always-public fields to
+// anyone but admins and themselves; viewers who may not see a consented minor's
+// exact age get their age group instead.
+var policy = map[Field]rule{
+	LastName:    {Adult: Public, Minor: Scout},
+	Age:         {Adult: Public, Minor: Scout},
+	DateOfBirth: {Adult: Scout, Minor: Full},
+	City:        {Adult: Public, Minor: Pro},
+	State:       {Adult: Public, Minor: Scout},
+	SchoolName:  {Adult: Scout, Minor: Full},
+}
+
+// Subject is what the policy needs to know about the player being shown
+type Subject struct {
+	Minor     bool
+	Consented bool // current profile publication consent, minors only
+}
+
+// Visible reports whether a viewer at level may see a field of the subject
+func Visible(level Level, s Subject, f Field) bool {
+	r, ok := policy[f]
+	if !ok {
+		return true
+	}
+	required := r.Adult
+	if s.Minor {
+		required = r.Minor
+		if !s.Consented {
+			required = Full
+		}
+	}
+	return level >= required
+}
+
+// levelKey caches the viewer level on the request
+const levelKey = "privacy_level"
+
+// ViewerLevel works out the requester's level from the auth context and their
+// subscription. Public routes need optionalAuth for signed-in viewers to count.
+func ViewerLevel(c *gin.Context, db *gorm.DB) Level {
+	if cached, ok := c.Get(levelKey); ok {
+		return cached.(Level)
+	}
+
+	level := Public
+	if role, _ := c.Get("user_role"); role == "admin" {
+		level = Full
+	} else if userID, ok := c.Get("user_id"); ok {
+		var sub domain.Subscription
+		if err := db.Where("user_id = ? AND status = ?", userID, "active").First(&sub).Error; err == nil {
+			level = tierLevels[sub.Tier]
+		}
+	}
+	c.Set(levelKey, level)
+	return level
+}
+
+// Policy masks players for one request
+type Policy struct {
+	db        *gorm.DB
+	level     Level
+	viewerID  *uuid.UUID
+	consented map[uuid.UUID]bool
+}
+
+// For builds the policy for the current requester
+func For(c *gin.Context, db *gorm.DB) *Policy {
+	p := &Policy{db: db, level: ViewerLevel(c, db), consented: map[uuid.UUID]bool{}}
+	if userID, ok := c.Get("user_id"); ok {
+		uid := userID.(uuid.UUID)
+		p.viewerID = &uid
+	}
+	return p
+}
+
+// Load looks up consent for the minors among players in one query. Players that
+// weren't loaded are looked up one at a time when masked.
+func (p *Policy) Load(players []domain.Player) {
+	if p.level == Full {
+		return
+	}
+	var minors []uuid.UUID
+	for i := range players {
+		if _, known := p.consented[players[i].ID]; !known && consent.IsMinor(&players[i]) {
+			minors = append(minors, players[i].ID)
+		}
+	}
+	allowed, err := consent.AllowedIDs(p.db, minors, consent.ProfilePublication)
+	if err != nil {
+		return
+	}
+	for _, id := range minors {
+		p.consented[id] = allowed[id]
+	}
+}
+
+// levelFor returns the viewer's level for one player: Full for their own profile
+func (p *Policy) levelFor(player *domain.Player) Level {
+	if p.viewerID != nil && player.UserID != nil && *player.UserID == *p.viewerID {
+		return Full
+	}
+	return p.level
+}
+
+// subject describes a player for the policy
+func (p *Policy) subject(player *domain.Player) Subject {
+	if !consent.IsMinor(player) {
+		return Subject{}
+	}
+	consented, known := p.consented[player.ID]
+	if !known {
+		consented = consent.Allowed(p.db, player, consent.ProfilePublication)
+		p.consented[player.ID] = consented
+	}
+	return Subject{Minor: true, Consented: consented}
+}
+
+// Masked holds a player's maskable fields as the viewer may see them; hidden
+// fields are nil
+type Masked struct {
+	LastName     *string
+	LastNameInit string
+	Age          *int
+	AgeGroup     string  // this season's age group, also shown when the exact age isn't
+	DateOfBirth  *string // YYYY-MM-DD
+	City         *string
+	State        *string
+	SchoolName   *string
+}
+
+// Mask applies the policy to a player
+func (p *Policy) Mask(player *domain.Player) Masked {
+	level := p.levelFor(player)
+	var s Subject
+	if level < Full {
+		s = p.subject(player)
+	}
+	visible := func(f Field) bool { return Visible(level, s, f) }
+
+	m := Masked{LastNameInit: player.GetLastNameInit()}
+	if visible(LastName) {
+		lastName := player.LastName
+		m.LastName = &lastName
+	}
+	if visible(Age) {
+		age := player.GetAge()
+		m.Age = &age
+	}
+	m.AgeGroup = p.AgeGroup(player, agegroup.Cutoff(time.Now().Year(), nil))
+	if visible(DateOfBirth) && !player.DateOfBirth.IsZero() {
+		dob := player.DateOfBirth.Format("2006-01-02")
+		m.DateOfBirth = &dob
+	}
+	if visible(City) {
+		m.City = player.City
+	}
+	if visible(State) {
+		m.State = player.State
+	}
+	if visible(SchoolName) {
+		m.SchoolName = player.SchoolName
+	}
+	return m
+}
+
+// AgeGroup returns the player's age group on a competition's cut-off date, or ""
+// where the viewer may not see even that
+func (p *Policy) AgeGroup(player *domain.Player, cutoff time.Time) string {
+	level := p.levelFor(player)
+	if level < Full {
+		s := p.subject(player)
+		if !Visible(level, s, Age) && !s.Consented {
+			return ""
+		}
+	}
+	return agegroup.Of(player.DateOfBirth, cutoff)
+}
+
+// Name is the player's display name: the full name where the last name is visible,
+// otherwise first name and initial
+func (p *Policy) Name(player *domain.Player) string {
+	m := p.Mask(player)
+	if m.LastName != nil {
+		return player.FirstName + " " + *m.LastName
+	}
+	return strings.TrimSpace(player.FirstName + " " + m.LastNameInit)
+}
@@ -0,0 +1,60 @@
+package privacy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// policyFor builds a policy whose consent for player is already known, so masking
+// never reaches the database
+func policyFor(level Level, player *domain.Player, consented bool) *Policy {
+	return &Policy{level: level, consented: map[uuid.UUID]bool{player.ID: consented}}
+}
+
+func playerAged(years int) *domain.Player {
+	return &domain.Player{
+		ID:          uuid.New(),
+		FirstName:   "Amara",
+		LastName:    "Okafor",
+		DateOfBirth: time.Now().AddDate(-years, 0, -30),
+	}
+}
+
+func TestMaskHidesMinorAgeFromPublicViewers(t *testing.T) {
+	minor := playerAged(15)
+
+	masked := policyFor(Public, minor, true).Mask(minor)
+	if masked.Age != nil {
+		t.Fatalf("anonymous viewer got a consented minor's exact age %d", *masked.Age)
+	}
+	if masked.AgeGroup == "" {
+		t.Fatal("anonymous viewer got no age group for a consented minor")
+	}
+	if masked.DateOfBirth != nil {
+		t.Fatalf("anonymous viewer got a minor's date of birth %s", *masked.DateOfBirth)
+	}
+
+	masked = policyFor(Public, minor, false).Mask(minor)
+	if masked.Age != nil || masked.AgeGroup != "" {
+		t.Fatalf("anonymous viewer got age %v, group %q for a minor without consent", masked.Age, masked.AgeGroup)
+	}
+}
+
+func TestMaskShowsAge(t *testing.T) {
+	minor := playerAged(15)
+	if masked := policyFor(Scout, minor, true).Mask(minor); masked.Age == nil || *masked.Age != 15 {
+		t.Fatalf("scout got age %v for a consented minor, want 15", masked.Age)
+	}
+	if masked := policyFor(Full, minor, false).Mask(minor); masked.Age == nil || *masked.Age != 15 {
+		t.Fatalf("admin got age %v for a minor without consent, want 15", masked.Age)
+	}
+
+	adult := playerAged(22)
+	if masked := policyFor(Public, adult, false).Mask(adult); masked.Age == nil || *masked.Age != 22 {
+		t.Fatalf("anonymous viewer got age %v for an adult, want 22", masked.Age)
+	}
+}
@@ -0,0 +1,72 @@
+package registrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Registration statuses
+const (
+	StatusRegistered   = "registered"
+	StatusParticipated = "participated" // appeared in at least one of the tournament's matches
+	StatusWithdrawn    = "withdrawn"
+)
+
+// Statuses lists the registration statuses
+var Statuses = []string{StatusRegistered, StatusParticipated, StatusWithdrawn}
+
+// Entered is a query scope on players that keeps those registered for the
+// tournament and not withdrawn
+func Entered(tournamentID interface{}) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`EXISTS (
+			SELECT 1 FROM tournament_registrations tr
+			WHERE tr.player_id = players.id AND tr.tournament_id = ? AND tr.status <> ?)`,
+			tournamentID, StatusWithdrawn)
+	}
+}
+
+// Register enters the player in the tournament, leaving an existing registration
+// as it is
+func Register(db *gorm.DB, tournamentID, playerID uuid.UUID, createdBy *uuid.UUID) error {
+	now := time.Now()
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tournament_id"}, {Name: "player_id"}},
+		DoNothing: true,
+	}).Create(&domain.TournamentRegistration{
+		TournamentID: tournamentID,
+		PlayerID:     playerID,
+		Status:       StatusRegistered,
+		CreatedBy:    createdBy,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}).Error
+}
+
+// RecordAppearance marks the player as having played in the tournament,
+// registering them if they weren't, e.g. when added to a match lineup. A shirt
+// number is kept if the registration has none.
+func RecordAppearance(db *gorm.DB, tournamentID, playerID uuid.UUID, shirtNumber *int, createdBy *uuid.UUID) error {
+	now := time.Now()
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tournament_id"}, {Name: "player_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "status"}, Value: StatusParticipated},
+			{Column: clause.Column{Name: "shirt_number"}, Value: gorm.Expr("COALESCE(tournament_registrations.shirt_number, EXCLUDED.shirt_number)")},
+			{Column: clause.Column{Name: "updated_at"}, Value: now},
+		},
+	}).Create(&domain.TournamentRegistration{
+		TournamentID: tournamentID,
+		PlayerID:     playerID,
+		ShirtNumber:  shirtNumber,
+		Status:       StatusParticipated,
+		CreatedBy:    createdBy,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}).Error
+}
@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Result is the outcome of scanning one object
+type Result struct {
+	Clean     bool
+	Signature string // malware signature name when not clean
+	Engine    string
+}
+
+// Scanner checks file contents for malware
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+}
+
+// ==================== NO-OP ====================
+
+// Noop accepts everything. Used when no scanner is configured.
+type Noop struct{}
+
+// NewNoop creates a scanner that marks every file clean
+func NewNoop() *Noop {
+	return &Noop{}
+}
+
+func (Noop) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	return &Result{Clean: true, Engine: "none"}, nil
+}
+
+// ==================== CLAMAV ====================
+
+// clamdChunkSize is the INSTREAM chunk size; clamd rejects chunks above StreamMaxLength
+const clamdChunkSize = 64 * 1024
+
+// ClamAV scans files by streaming them to a clamd daemon (INSTREAM command)
+type ClamAV struct {
+	network string // tcp or unix
+	address string
+	timeout time.Duration
+}
+
+// NewClamAV creates a clamd client. address is host:port for TCP, or an absolute
+// socket path (e.g. /var/run/clamav/clamd.ctl) for a unix socket.
+func NewClamAV(address string, timeout time.Duration) *ClamAV {
+	network := "tcp"
+	if strings.HasPrefix(address, "/") {
+		network = "unix"
+	}
+	return &ClamAV{network: network, address: address, timeout: timeout}
+}
+
+func (s *ClamAV) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("clamd unavailable: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else if s.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.timeout))
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return nil, err
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return nil, err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	// Zero-length chunk ends the stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return nil, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply parses "stream: OK", "stream: <sig> FOUND" or "... ERROR"
+func parseClamdReply(reply string) (*Result, error) {
+	status := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case status == "OK":
+		return &Result{Clean: true, Engine: "clamav"}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &Result{Clean: false, Signature: strings.TrimSuffix(status, " FOUND"), Engine: "clamav"}, nil
+	default:
+		return nil, fmt.Errorf("clamd error: %s", reply)
+	}
+}
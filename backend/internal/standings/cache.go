@@ -0,0 +1,161 @@
+package standings
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// statsVersion is bumped whenever the way stats are computed changes, so cached
+// copies from an older version are recomputed rather than served
+const statsVersion = 2
+
+// Stats are a tournament's tables, team records and leaderboards. Knockout
+// matches count towards the records and leaderboards but not the tables.
+type Stats struct {
+	Version      int          `json:"version"`
+	TieBreakers  []string     `json:"tie_breakers"`
+	Groups       []GroupTable `json:"groups"`
+	Records      []Row        `json:"records"` // every team's record across all its matches
+	Leaderboards Leaderboards `json:"leaderboards"`
+	ComputedAt   time.Time    `json:"computed_at"`
+}
+
+// GroupTable is one group's table. A tournament without groups has a single
+// table of all its matches outside the knockout bracket.
+type GroupTable struct {
+	GroupID *uuid.UUID `json:"group_id,omitempty"`
+	Name    string     `json:"name"`
+	Rows    []Row      `json:"rows"`
+}
+
+// Compute works out the tournament's stats from its results
+func Compute(db *gorm.DB, tournamentID uuid.UUID) (*Stats, error) {
+	var tournament domain.Tournament
+	if err := db.First(&tournament, "id = ?", tournamentID).Error; err != nil {
+		return nil, err
+	}
+	stats := &Stats{Version: statsVersion, TieBreakers: TieBreakers(&tournament), Groups: []GroupTable{}, ComputedAt: time.Now()}
+
+	var completed []domain.Match
+	if err := db.Where("tournament_id = ? AND status = ?", tournamentID, "completed").
+		Order("match_date, match_number").Find(&completed).Error; err != nil {
+		return nil, err
+	}
+	var teams []domain.Team
+	if err := db.Where("tournament_id = ?", tournamentID).Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	var groups []domain.TournamentGroup
+	if err := db.Preload("Teams").Where("tournament_id = ?", tournamentID).
+		Order("position, name").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+
+	stats.Records = Table(teams, completed, []string{TieBreakGoalDifference, TieBreakGoalsFor})
+	var matches []domain.Match
+	for _, m := range completed {
+		if m.BracketSlot == nil {
+			matches = append(matches, m)
+		}
+	}
+	if len(groups) == 0 && (len(teams) > 0 || len(matches) > 0) {
+		stats.Groups = append(stats.Groups, GroupTable{Name: "Table", Rows: Table(teams, matches, stats.TieBreakers)})
+	}
+	for _, g := range groups {
+		var played []domain.Match
+		for _, m := range matches {
+			if m.GroupID != nil && *m.GroupID == g.ID {
+				played = append(played, m)
+			}
+		}
+		id := g.ID
+		stats.Groups = append(stats.Groups, GroupTable{GroupID: &id, Name: g.Name, Rows: Table(g.Teams, played, stats.TieBreakers)})
+	}
+
+	leaders, err := computeLeaders(db, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	stats.Leaderboards = leaders
+	return stats, nil
+}
+
+// Get returns the tournament's cached stats, computing them on first use or when
+// the cached copy is from an older version
+func Get(db *gorm.DB, tournamentID uuid.UUID) (*Stats, error) {
+	// A refresh that runs meanwhile sees newer results, so it wins over a first
+	// computation; an outdated copy is always replaced
+	onConflict := clause.OnConflict{DoNothing: true}
+	var cached domain.TournamentStats
+	err := db.First(&cached, "tournament_id = ?", tournamentID).Error
+	if err == nil {
+		var stats Stats
+		if err := json.Unmarshal([]byte(cached.Data), &stats); err == nil && stats.Version == statsVersion {
+			return &stats, nil
+		}
+		onConflict = clause.OnConflict{UpdateAll: true}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	stats, err := Compute(db, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := save(db, tournamentID, stats, onConflict); err != nil {
+		log.Printf("standings: failed to cache tournament %s: %v", tournamentID, err)
+	}
+	return stats, nil
+}
+
+// Refresh recomputes the cached stats of the tournament after its results change.
+// Failures are logged and never fail the action that changed the results.
+func Refresh(db *gorm.DB, tournamentID *uuid.UUID) {
+	if tournamentID == nil {
+		return
+	}
+	stats, err := Compute(db, *tournamentID)
+	if err == nil {
+		err = save(db, *tournamentID, stats, clause.OnConflict{UpdateAll: true})
+	}
+	if err != nil {
+		log.Printf("standings: failed to refresh tournament %s: %v", *tournamentID, err)
+	}
+}
+
+// RefreshPlayers refreshes every tournament the players appeared in, after a
+// change to the players themselves such as a merge or deletion. Failures are
+// logged and never fail the action.
+func RefreshPlayers(db *gorm.DB, playerIDs ...uuid.UUID) {
+	var tournamentIDs []uuid.UUID
+	if err := db.Model(&domain.MatchPlayer{}).
+		Joins("JOIN matches ON matches.id = match_players.match_id").
+		Where("match_players.player_id IN ? AND matches.tournament_id IS NOT NULL", playerIDs).
+		Distinct().Pluck("matches.tournament_id", &tournamentIDs).Error; err != nil {
+		log.Printf("standings: failed to find tournaments of players %v: %v", playerIDs, err)
+		return
+	}
+	for i := range tournamentIDs {
+		Refresh(db, &tournamentIDs[i])
+	}
+}
+
+func save(db *gorm.DB, tournamentID uuid.UUID, stats *Stats, onConflict clause.OnConflict) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return db.Clauses(onConflict).Create(&domain.TournamentStats{
+		TournamentID: tournamentID,
+		Data:         string(data),
+		ComputedAt:   stats.ComputedAt,
+	}).Error
+}
@@ -0,0 +1,141 @@
+package standings
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxLeaders is how many players each leaderboard keeps
+const MaxLeaders = 50
+
+// Leader is a player's total in one leaderboard. Player details are looked up
+// when the board is shown, so they follow the viewer's privacy rules.
+type Leader struct {
+	PlayerID uuid.UUID `json:"player_id"`
+	Team     *string   `json:"team,omitempty"`
+	Value    int       `json:"value"`
+	Matches  int       `json:"matches"` // appearances in completed matches
+}
+
+// Leaderboards rank the tournament's players. Clean sheets count the completed
+// matches a goalkeeper played in without their side conceding.
+type Leaderboards struct {
+	Goals       []Leader `json:"goals"`
+	Assists     []Leader `json:"assists"`
+	Minutes     []Leader `json:"minutes"`
+	CleanSheets []Leader `json:"clean_sheets"`
+}
+
+// appearance is a player's line in a completed match, with the side they played for
+type appearance struct {
+	PlayerID       uuid.UUID
+	Goals          int
+	Assists        int
+	MinutesPlayed  *int
+	IsStarter      bool
+	PositionPlayed *string
+	Position       string
+	Team           *string
+	Conceded       *int
+}
+
+// The side a player played for is their recorded team, or else the team from
+// their academy
+const appearancesSQL = `
+SELECT player_id, goals, assists, minutes_played, is_starter, position_played, position,
+       CASE side WHEN 'home' THEN home_team WHEN 'away' THEN away_team END AS team,
+       CASE side WHEN 'home' THEN away_score WHEN 'away' THEN home_score END AS conceded
+FROM (
+    SELECT mp.player_id, mp.goals, mp.assists, mp.minutes_played, mp.is_starter, mp.position_played, p.position,
+           m.home_team, m.away_team, m.home_score, m.away_score,
+           CASE WHEN mp.team_id = m.home_team_id THEN 'home'
+                WHEN mp.team_id = m.away_team_id THEN 'away'
+                WHEN mp.team_id IS NULL AND ht.academy_id = p.academy_id THEN 'home'
+                WHEN mp.team_id IS NULL AND at.academy_id = p.academy_id THEN 'away' END AS side
+    FROM match_players mp
+    JOIN matches m ON m.id = mp.match_id
+    JOIN players p ON p.id = mp.player_id
+    LEFT JOIN teams ht ON ht.id = m.home_team_id
+    LEFT JOIN teams at ON at.id = m.away_team_id
+    WHERE m.tournament_id = ? AND m.status = 'completed' AND p.deleted_at IS NULL
+) a`
+
+func computeLeaders(db *gorm.DB, tournamentID uuid.UUID) (Leaderboards, error) {
+	var rows []appearance
+	if err := db.Raw(appearancesSQL, tournamentID).Scan(&rows).Error; err != nil {
+		return Leaderboards{}, err
+	}
+
+	type totals struct {
+		Leader
+		goals, assists, minutes, cleanSheets int
+	}
+	byPlayer := map[uuid.UUID]*totals{}
+	var order []*totals
+	for _, a := range rows {
+		t, ok := byPlayer[a.PlayerID]
+		if !ok {
+			t = &totals{Leader: Leader{PlayerID: a.PlayerID}}
+			byPlayer[a.PlayerID] = t
+			order = append(order, t)
+		}
+		t.Matches++
+		t.goals += a.Goals
+		t.assists += a.Assists
+		if a.MinutesPlayed != nil {
+			t.minutes += *a.MinutesPlayed
+		}
+		if a.Team != nil {
+			t.Team = a.Team
+		}
+		played := a.IsStarter || (a.MinutesPlayed != nil && *a.MinutesPlayed > 0)
+		if played && isGoalkeeper(a) && a.Conceded != nil && *a.Conceded == 0 {
+			t.cleanSheets++
+		}
+	}
+
+	board := func(value func(*totals) int) []Leader {
+		var list []Leader
+		for _, t := range order {
+			if v := value(t); v > 0 {
+				l := t.Leader
+				l.Value = v
+				list = append(list, l)
+			}
+		}
+		// Fewer matches ranks higher on the same total
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].Value != list[j].Value {
+				return list[i].Value > list[j].Value
+			}
+			return list[i].Matches < list[j].Matches
+		})
+		if len(list) > MaxLeaders {
+			list = list[:MaxLeaders]
+		}
+		return list
+	}
+	return Leaderboards{
+		Goals:       board(func(t *totals) int { return t.goals }),
+		Assists:     board(func(t *totals) int { return t.assists }),
+		Minutes:     board(func(t *totals) int { return t.minutes }),
+		CleanSheets: board(func(t *totals) int { return t.cleanSheets }),
+	}, nil
+}
+
+// isGoalkeeper goes by the position played in the match, or the player's own
+// position if none was recorded
+func isGoalkeeper(a appearance) bool {
+	position := a.Position
+	if a.PositionPlayed != nil && *a.PositionPlayed != "" {
+		position = *a.PositionPlayed
+	}
+	switch strings.ToLower(strings.TrimSpace(position)) {
+	case "goalkeeper", "gk":
+		return true
+	}
+	return false
+}
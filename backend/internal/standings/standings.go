@@ -0,0 +1,212 @@
+// Package standings computes a tournament's group tables and player leaderboards
+// from match results, and caches them per tournament.
+package standings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Points for a result
+const (
+	PointsWin  = 3
+	PointsDraw = 1
+)
+
+// Tie-breakers, applied in the tournament's order to teams level on points
+const (
+	TieBreakGoalDifference = "goal_difference"
+	TieBreakGoalsFor       = "goals_for"
+	TieBreakHeadToHead     = "head_to_head" // points, then goal difference, in matches between the tied teams
+	TieBreakWins           = "wins"
+)
+
+// DefaultTieBreakers are used when the tournament doesn't set its own
+var DefaultTieBreakers = []string{TieBreakGoalDifference, TieBreakGoalsFor, TieBreakHeadToHead}
+
+var validTieBreakers = map[string]bool{
+	TieBreakGoalDifference: true,
+	TieBreakGoalsFor:       true,
+	TieBreakHeadToHead:     true,
+	TieBreakWins:           true,
+}
+
+// ParseTieBreakers reads a comma-separated list of tie-breakers. An empty list
+// means the defaults.
+func ParseTieBreakers(s string) ([]string, error) {
+	var list []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ",") {
+		tb := strings.ToLower(strings.TrimSpace(part))
+		if tb == "" {
+			continue
+		}
+		if !validTieBreakers[tb] {
+			return nil, fmt.Errorf("unknown tie-breaker %q: use %s, %s, %s or %s", tb,
+				TieBreakGoalDifference, TieBreakGoalsFor, TieBreakHeadToHead, TieBreakWins)
+		}
+		if !seen[tb] {
+			seen[tb] = true
+			list = append(list, tb)
+		}
+	}
+	if len(list) == 0 {
+		return DefaultTieBreakers, nil
+	}
+	return list, nil
+}
+
+// TieBreakers returns the tournament's tie-breakers, falling back to the defaults
+func TieBreakers(t *domain.Tournament) []string {
+	if t.TieBreakers == nil {
+		return DefaultTieBreakers
+	}
+	list, err := ParseTieBreakers(*t.TieBreakers)
+	if err != nil {
+		return DefaultTieBreakers
+	}
+	return list
+}
+
+// Row is a team's line in a table
+type Row struct {
+	Position       int        `json:"position"`
+	TeamID         *uuid.UUID `json:"team_id,omitempty"`
+	Team           string     `json:"team"`
+	Played         int        `json:"played"`
+	Won            int        `json:"won"`
+	Drawn          int        `json:"drawn"`
+	Lost           int        `json:"lost"`
+	GoalsFor       int        `json:"goals_for"`
+	GoalsAgainst   int        `json:"goals_against"`
+	GoalDifference int        `json:"goal_difference"`
+	Points         int        `json:"points"`
+}
+
+func (r *Row) add(scored, conceded int) {
+	r.Played++
+	r.GoalsFor += scored
+	r.GoalsAgainst += conceded
+	r.GoalDifference = r.GoalsFor - r.GoalsAgainst
+	switch {
+	case scored > conceded:
+		r.Won++
+		r.Points += PointsWin
+	case scored == conceded:
+		r.Drawn++
+		r.Points += PointsDraw
+	default:
+		r.Lost++
+	}
+}
+
+// Counts reports whether a match's result goes into the table
+func Counts(m *domain.Match) bool {
+	return m.Status == "completed" && m.HomeScore != nil && m.AwayScore != nil
+}
+
+// Table ranks the teams by their results in the matches. Every team is listed,
+// including ones that haven't played, as is any other side found in the matches.
+// Sides are matched by team ID, or by name for matches without one.
+func Table(teams []domain.Team, matches []domain.Match, tieBreakers []string) []Row {
+	var rows []*Row
+	byKey := map[string]*Row{}
+	row := func(id *uuid.UUID, name string) *Row {
+		k := key(id, name)
+		if r, ok := byKey[k]; ok {
+			return r
+		}
+		r := &Row{TeamID: id, Team: name}
+		byKey[k] = r
+		rows = append(rows, r)
+		return r
+	}
+	for i := range teams {
+		row(&teams[i].ID, teams[i].Name)
+	}
+
+	type result struct {
+		home, away           *Row
+		homeGoals, awayGoals int
+	}
+	var results []result
+	for i := range matches {
+		m := &matches[i]
+		if !Counts(m) || unknown(m.HomeTeamID, m.HomeTeam) || unknown(m.AwayTeamID, m.AwayTeam) {
+			continue
+		}
+		home := row(m.HomeTeamID, deref(m.HomeTeam))
+		away := row(m.AwayTeamID, deref(m.AwayTeam))
+		home.add(*m.HomeScore, *m.AwayScore)
+		away.add(*m.AwayScore, *m.HomeScore)
+		results = append(results, result{home, away, *m.HomeScore, *m.AwayScore})
+	}
+
+	// Head-to-head records only count matches between teams level on points
+	h2h := map[*Row]*Row{}
+	for _, r := range rows {
+		h2h[r] = &Row{}
+	}
+	for _, res := range results {
+		if res.home.Points == res.away.Points {
+			h2h[res.home].add(res.homeGoals, res.awayGoals)
+			h2h[res.away].add(res.awayGoals, res.homeGoals)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		for _, tb := range tieBreakers {
+			var x, y int
+			switch tb {
+			case TieBreakGoalDifference:
+				x, y = a.GoalDifference, b.GoalDifference
+			case TieBreakGoalsFor:
+				x, y = a.GoalsFor, b.GoalsFor
+			case TieBreakWins:
+				x, y = a.Won, b.Won
+			case TieBreakHeadToHead:
+				if x, y = h2h[a].Points, h2h[b].Points; x == y {
+					x, y = h2h[a].GoalDifference, h2h[b].GoalDifference
+				}
+			}
+			if x != y {
+				return x > y
+			}
+		}
+		return strings.ToLower(a.Team) < strings.ToLower(b.Team)
+	})
+
+	table := make([]Row, len(rows))
+	for i, r := range rows {
+		r.Position = i + 1
+		table[i] = *r
+	}
+	return table
+}
+
+func key(id *uuid.UUID, name string) string {
+	if id != nil {
+		return id.String()
+	}
+	return "name:" + strings.ToLower(strings.TrimSpace(name))
+}
+
+func unknown(id *uuid.UUID, name *string) bool {
+	return id == nil && strings.TrimSpace(deref(name)) == ""
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
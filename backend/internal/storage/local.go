@@ -0,0 +1,569 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocalRoutePrefix is where the API mounts LocalStore.Handler()
+const LocalRoutePrefix = "/storage"
+
+// LocalStore implements Store on the local filesystem. Presigned URLs point back at
+// the API itself (LocalRoutePrefix) and are authorised with an HMAC signature, so the
+// upload/download flow behaves the same as S3 for clients - no AWS account required.
+type LocalStore struct {
+	root    string
+	baseURL string
+	bucket  string
+	secret  []byte
+}
+
+// localMeta is persisted next to each object so Head can report content type
+type localMeta struct {
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag"`
+}
+
+// NewLocalStore creates a filesystem store rooted at dir. baseURL is the public URL
+// of the API (e.g. http://localhost:8080) used to build signed links.
+func NewLocalStore(dir, baseURL, bucket, secret string) (*LocalStore, error) {
+	if secret == "" {
+		return nil, errors.New("local storage requires a signing secret")
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range []string{"objects", "meta", "multipart"} {
+		if err := os.MkdirAll(filepath.Join(root, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create storage dir: %w", err)
+		}
+	}
+	return &LocalStore{
+		root:    root,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		bucket:  bucket,
+		secret:  []byte(secret),
+	}, nil
+}
+
+func (s *LocalStore) Bucket() string {
+	return s.bucket
+}
+
+func (s *LocalStore) PresignPut(ctx context.Context, key, contentType string, contentLength int64, expires time.Duration) (string, error) {
+	params := url.Values{}
+	params.Set("op", "put")
+	if contentType != "" {
+		params.Set("content_type", contentType)
+	}
+	if contentLength > 0 {
+		params.Set("size", strconv.FormatInt(contentLength, 10))
+	}
+	return s.signedURL(key, params, expires)
+}
+
+func (s *LocalStore) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	params := url.Values{}
+	params.Set("op", "get")
+	return s.signedURL(key, params, expires)
+}
+
+func (s *LocalStore) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	if _, err := s.objectPath(key); err != nil {
+		return "", err
+	}
+	uploadID := uuid.New().String()
+	dir := filepath.Join(s.root, "multipart", uploadID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	meta, _ := json.Marshal(map[string]string{"key": key, "content_type": contentType})
+	if err := os.WriteFile(filepath.Join(dir, "upload.json"), meta, 0o644); err != nil {
+		return "", err
+	}
+	return uploadID, nil
+}
+
+func (s *LocalStore) PresignUploadPart(ctx context.Context, key, uploadID string, partNumber int, expires time.Duration) (string, error) {
+	if _, err := s.uploadDir(uploadID); err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("op", "part")
+	params.Set("upload_id", uploadID)
+	params.Set("part_number", strconv.Itoa(partNumber))
+	return s.signedURL(key, params, expires)
+}
+
+func (s *LocalStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error {
+	dir, err := s.uploadDir(uploadID)
+	if err != nil {
+		return err
+	}
+	var upload struct {
+		Key         string `json:"key"`
+		ContentType string `json:"content_type"`
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "upload.json"))
+	if err != nil {
+		return ErrNotFound
+	}
+	if err := json.Unmarshal(raw, &upload); err != nil {
+		return err
+	}
+	if upload.Key != key {
+		return fmt.Errorf("upload %s does not belong to key %s", uploadID, key)
+	}
+
+	sorted := append([]CompletedPart(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+
+	dst, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// Mirror S3's multipart ETag: md5 of the concatenated part md5s, suffixed with part count
+	etags := md5.New()
+	for _, p := range sorted {
+		partFile := filepath.Join(dir, strconv.Itoa(p.PartNumber))
+		f, err := os.Open(partFile)
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("part %d not uploaded", p.PartNumber)
+		}
+		h := md5.New()
+		_, err = io.Copy(io.MultiWriter(tmp, h), f)
+		f.Close()
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		sum := h.Sum(nil)
+		if want := strings.Trim(p.ETag, `"`); want != "" && want != hex.EncodeToString(sum) {
+			tmp.Close()
+			return fmt.Errorf("part %d etag mismatch", p.PartNumber)
+		}
+		etags.Write(sum)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return err
+	}
+
+	etag := fmt.Sprintf("%s-%d", hex.EncodeToString(etags.Sum(nil)), len(sorted))
+	if err := s.writeMeta(key, localMeta{ContentType: upload.ContentType, ETag: etag}); err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (s *LocalStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	dir, err := s.uploadDir(uploadID)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return os.RemoveAll(dir)
+}
+
+func (s *LocalStore) ListParts(ctx context.Context, key, uploadID string) ([]UploadedPart, error) {
+	dir, err := s.uploadDir(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	var parts []UploadedPart
+	for _, e := range entries {
+		partNumber, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue // upload.json
+		}
+		p := filepath.Join(dir, e.Name())
+		f, err := os.Open(p)
+		if err != nil {
+			continue
+		}
+		h := md5.New()
+		size, err := io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		info, _ := e.Info()
+		part := UploadedPart{
+			PartNumber: partNumber,
+			ETag:       hex.EncodeToString(h.Sum(nil)),
+			Size:       size,
+		}
+		if info != nil {
+			part.UploadedAt = info.ModTime()
+		}
+		parts = append(parts, part)
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	return parts, nil
+}
+
+func (s *LocalStore) Head(ctx context.Context, key string) (*ObjectInfo, error) {
+	p, err := s.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	meta := s.readMeta(key)
+	return &ObjectInfo{
+		Key:          key,
+		Size:         fi.Size(),
+		ContentType:  meta.ContentType,
+		ETag:         meta.ETag,
+		LastModified: fi.ModTime(),
+	}, nil
+}
+
+func (s *LocalStore) Open(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	p, err := s.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	if length < 0 {
+		return f, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, length), f}, nil
+}
+
+func (s *LocalStore) Copy(ctx context.Context, srcKey, dstKey string) error {
+	src, err := s.Open(ctx, srcKey, 0, -1)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := s.objectPath(dstKey)
+	if err != nil {
+		return err
+	}
+	etag, err := writeFile(dst, src)
+	if err != nil {
+		return err
+	}
+	meta := s.readMeta(srcKey)
+	meta.ETag = etag
+	return s.writeMeta(dstKey, meta)
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	p, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if mp, err := s.metaPath(key); err == nil {
+		os.Remove(mp)
+	}
+	return nil
+}
+
+// =============================================================================
+// HTTP HANDLER
+// =============================================================================
+
+// Handler serves signed GET/HEAD/PUT requests under LocalRoutePrefix
+func (s *LocalStore) Handler() http.Handler {
+	return http.HandlerFunc(s.serveHTTP)
+}
+
+func (s *LocalStore) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, LocalRoutePrefix+"/")
+	q := r.URL.Query()
+
+	if !s.verify(key, q) {
+		http.Error(w, "invalid or expired signature", http.StatusForbidden)
+		return
+	}
+
+	switch op := q.Get("op"); {
+	case op == "get" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		s.serveGet(w, r, key)
+	case op == "put" && r.Method == http.MethodPut:
+		s.servePut(w, r, key, q)
+	case op == "part" && r.Method == http.MethodPut:
+		s.servePart(w, r, q)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *LocalStore) serveGet(w http.ResponseWriter, r *http.Request, key string) {
+	p, err := s.objectPath(key)
+	if err != nil {
+		http.Error(w, "invalid key", http.StatusBadRequest)
+		return
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	meta := s.readMeta(key)
+	if meta.ContentType != "" {
+		w.Header().Set("Content-Type", meta.ContentType)
+	}
+	if meta.ETag != "" {
+		w.Header().Set("ETag", `"`+meta.ETag+`"`)
+	}
+	// ServeContent handles Range requests so video seeking works
+	http.ServeContent(w, r, path.Base(key), fi.ModTime(), f)
+}
+
+func (s *LocalStore) servePut(w http.ResponseWriter, r *http.Request, key string, q url.Values) {
+	if size := q.Get("size"); size != "" && strconv.FormatInt(r.ContentLength, 10) != size {
+		http.Error(w, "content length does not match signed size", http.StatusBadRequest)
+		return
+	}
+	dst, err := s.objectPath(key)
+	if err != nil {
+		http.Error(w, "invalid key", http.StatusBadRequest)
+		return
+	}
+	etag, err := writeFile(dst, r.Body)
+	if err != nil {
+		http.Error(w, "failed to store object", http.StatusInternalServerError)
+		return
+	}
+
+	contentType := q.Get("content_type")
+	if contentType == "" {
+		contentType = r.Header.Get("Content-Type")
+	}
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	if err := s.writeMeta(key, localMeta{ContentType: contentType, ETag: etag}); err != nil {
+		http.Error(w, "failed to store object", http.StatusInternalServerError)
+		return
+	}
+
+	writeETag(w, etag)
+}
+
+func (s *LocalStore) servePart(w http.ResponseWriter, r *http.Request, q url.Values) {
+	dir, err := s.uploadDir(q.Get("upload_id"))
+	if err != nil {
+		http.Error(w, "invalid upload id", http.StatusBadRequest)
+		return
+	}
+	if _, err := os.Stat(dir); err != nil {
+		http.Error(w, "upload not found", http.StatusNotFound)
+		return
+	}
+	partNumber, err := strconv.Atoi(q.Get("part_number"))
+	if err != nil || partNumber < 1 || partNumber > 10000 {
+		http.Error(w, "invalid part number", http.StatusBadRequest)
+		return
+	}
+
+	etag, err := writeFile(filepath.Join(dir, strconv.Itoa(partNumber)), r.Body)
+	if err != nil {
+		http.Error(w, "failed to store part", http.StatusInternalServerError)
+		return
+	}
+	writeETag(w, etag)
+}
+
+func writeETag(w http.ResponseWriter, etag string) {
+	w.Header().Set("ETag", `"`+etag+`"`)
+	// Browsers need this to read the ETag for multipart completion
+	w.Header().Set("Access-Control-Expose-Headers", "ETag")
+	w.WriteHeader(http.StatusOK)
+}
+
+// =============================================================================
+// HELPERS
+// =============================================================================
+
+func (s *LocalStore) signedURL(key string, params url.Values, expires time.Duration) (string, error) {
+	if _, err := s.objectPath(key); err != nil {
+		return "", err
+	}
+	params.Set("expires", strconv.FormatInt(time.Now().Add(expires).Unix(), 10))
+	params.Set("sig", s.sign(key, params))
+
+	escaped := make([]string, 0)
+	for _, seg := range strings.Split(key, "/") {
+		escaped = append(escaped, url.PathEscape(seg))
+	}
+	return fmt.Sprintf("%s%s/%s?%s", s.baseURL, LocalRoutePrefix, strings.Join(escaped, "/"), params.Encode()), nil
+}
+
+// sign computes the HMAC over the key and every query parameter except sig
+func (s *LocalStore) sign(key string, params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "sig" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key))
+	for _, name := range names {
+		fmt.Fprintf(mac, "\n%s=%s", name, params.Get(name))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalStore) verify(key string, params url.Values) bool {
+	expires, err := strconv.ParseInt(params.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+	expected := s.sign(key, params)
+	return hmac.Equal([]byte(expected), []byte(params.Get("sig")))
+}
+
+// objectPath maps a key to a path under root/objects, rejecting traversal
+func (s *LocalStore) objectPath(key string) (string, error) {
+	return s.safeJoin("objects", key)
+}
+
+func (s *LocalStore) metaPath(key string) (string, error) {
+	return s.safeJoin("meta", key+".json")
+}
+
+func (s *LocalStore) uploadDir(uploadID string) (string, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return "", ErrNotFound
+	}
+	return filepath.Join(s.root, "multipart", uploadID), nil
+}
+
+func (s *LocalStore) safeJoin(area, key string) (string, error) {
+	if key == "" {
+		return "", errors.New("empty object key")
+	}
+	base := filepath.Join(s.root, area)
+	p := filepath.Join(base, filepath.FromSlash(key))
+	if !strings.HasPrefix(p, base+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return p, nil
+}
+
+func (s *LocalStore) writeMeta(key string, meta localMeta) error {
+	p, err := s.metaPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	raw, _ := json.Marshal(meta)
+	return os.WriteFile(p, raw, 0o644)
+}
+
+func (s *LocalStore) readMeta(key string) localMeta {
+	var meta localMeta
+	if p, err := s.metaPath(key); err == nil {
+		if raw, err := os.ReadFile(p); err == nil {
+			json.Unmarshal(raw, &meta)
+		}
+	}
+	return meta
+}
+
+// writeFile streams body to dst atomically and returns the hex md5 ETag
+func writeFile(dst string, body io.Reader) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := md5.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), body); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// S3Store implements Store on top of Amazon S3
+type S3Store struct {
+	client  *s3.Client
+	presign *s3.PresignClient
+	bucket  string
+}
+
+// NewS3Store creates an S3-backed store. When accessKeyID/secretAccessKey are empty
+// the default AWS credential chain (env, shared config, IAM role) is used.
+func NewS3Store(ctx context.Context, region, accessKeyID, secretAccessKey, bucket string) (*S3Store, error) {
+	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if accessKeyID != "" && secretAccessKey != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, ""),
+		))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg)
+	return &S3Store{
+		client:  client,
+		presign: s3.NewPresignClient(client),
+		bucket:  bucket,
+	}, nil
+}
+
+// Client returns the underlying S3 client
+func (s *S3Store) Client() *s3.Client {
+	return s.client
+}
+
+func (s *S3Store) Bucket() string {
+	return s.bucket
+}
+
+func (s *S3Store) PresignPut(ctx context.Context, key, contentType string, contentLength int64, expires time.Duration) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	}
+	if contentLength > 0 {
+		input.ContentLength = aws.Int64(contentLength)
+	}
+	req, err := s.presign.PresignPutObject(ctx, input, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (s *S3Store) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	req, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (s *S3Store) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	resp, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(resp.UploadId), nil
+}
+
+func (s *S3Store) PresignUploadPart(ctx context.Context, key, uploadID string, partNumber int, expires time.Duration) (string, error) {
+	req, err := s.presign.PresignUploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(int32(partNumber)),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (s *S3Store) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error {
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, p := range parts {
+		completed = append(completed, types.CompletedPart{
+			PartNumber: aws.Int32(int32(p.PartNumber)),
+			ETag:       aws.String(p.ETag),
+		})
+	}
+
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	return err
+}
+
+func (s *S3Store) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if isS3NotFound(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *S3Store) ListParts(ctx context.Context, key, uploadID string) ([]UploadedPart, error) {
+	var parts []UploadedPart
+	paginator := s3.NewListPartsPaginator(s.client, &s3.ListPartsInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			if isS3NotFound(err) {
+				return nil, ErrNotFound
+			}
+			return nil, err
+		}
+		for _, p := range page.Parts {
+			part := UploadedPart{
+				PartNumber: int(aws.ToInt32(p.PartNumber)),
+				ETag:       strings.Trim(aws.ToString(p.ETag), `"`),
+				Size:       aws.ToInt64(p.Size),
+			}
+			if p.LastModified != nil {
+				part.UploadedAt = *p.LastModified
+			}
+			parts = append(parts, part)
+		}
+	}
+	return parts, nil
+}
+
+func (s *S3Store) Head(ctx context.Context, key string) (*ObjectInfo, error) {
+	resp, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	info := &ObjectInfo{
+		Key:         key,
+		Size:        aws.ToInt64(resp.ContentLength),
+		ContentType: aws.ToString(resp.ContentType),
+		ETag:        strings.Trim(aws.ToString(resp.ETag), `"`),
+	}
+	if resp.LastModified != nil {
+		info.LastModified = *resp.LastModified
+	}
+	return info, nil
+}
+
+func (s *S3Store) Open(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	if length == 0 {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	if length > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	} else if offset > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := s.client.GetObject(ctx, input)
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) Copy(ctx context.Context, srcKey, dstKey string) error {
+	// CopySource is "bucket/key", URL-encoded per path segment
+	segments := strings.Split(s.bucket+"/"+srcKey, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+
+	_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(strings.Join(segments, "/")),
+	})
+	if isS3NotFound(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// isS3NotFound reports whether err is a missing key / missing upload error
+func isS3NotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey", "NoSuchUpload":
+			return true
+		}
+	}
+	return false
+}
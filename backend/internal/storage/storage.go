@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when an object or multipart upload does not exist
+var ErrNotFound = errors.New("storage: object not found")
+
+// Store abstracts object storage so modules don't depend on a concrete S3 client
+type Store interface {
+	// Bucket returns the bucket name used when building s3:// URLs
+	Bucket() string
+
+	// PresignPut returns a URL the client can PUT the object body to.
+	// contentLength is optional (0 = not enforced).
+	PresignPut(ctx context.Context, key, contentType string, contentLength int64, expires time.Duration) (string, error)
+	// PresignGet returns a time-limited download URL for the object
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+
+	// CreateMultipartUpload starts a multipart upload and returns its upload ID
+	CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error)
+	// PresignUploadPart returns a URL the client can PUT a single part to
+	PresignUploadPart(ctx context.Context, key, uploadID string, partNumber int, expires time.Duration) (string, error)
+	// CompleteMultipartUpload assembles the uploaded parts into the final object
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error
+	// AbortMultipartUpload discards an in-progress multipart upload and its parts
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+	// ListParts returns the parts already uploaded, ordered by part number, or ErrNotFound
+	ListParts(ctx context.Context, key, uploadID string) ([]UploadedPart, error)
+
+	// Head returns object metadata, or ErrNotFound
+	Head(ctx context.Context, key string) (*ObjectInfo, error)
+	// Open streams length bytes of the object starting at offset (length < 0 reads to the end)
+	Open(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// Copy duplicates an object within the bucket (objects up to 5GB)
+	Copy(ctx context.Context, srcKey, dstKey string) error
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// CompletedPart identifies an uploaded part when completing a multipart upload
+type CompletedPart struct {
+	PartNumber int    `json:"part_number"`
+	ETag       string `json:"etag"`
+}
+
+// UploadedPart describes a part stored for an in-progress multipart upload
+type UploadedPart struct {
+	PartNumber int       `json:"part_number"`
+	ETag       string    `json:"etag"`
+	Size       int64     `json:"size"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// ObjectInfo is the metadata returned by Head
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// ObjectURL builds the canonical s3://bucket/key URL stored in the database
+func ObjectURL(s Store, key string) string {
+	return fmt.Sprintf("s3://%s/%s", s.Bucket(), key)
+}
+
+// KeyFromURL extracts the object key from an s3://bucket/key URL.
+// Returns ok=false for anything that isn't an s3:// URL (e.g. external links).
+func KeyFromURL(raw string) (string, bool) {
+	if !strings.HasPrefix(raw, "s3://") {
+		return "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(raw, "s3://"), "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// SignedURL converts a stored s3:// URL into a presigned GET URL valid for 1 hour.
+// Non-s3 URLs are returned unchanged; on presign failure the original URL is returned.
+func SignedURL(s Store, raw string) string {
+	key, ok := KeyFromURL(raw)
+	if !ok || s == nil {
+		return raw
+	}
+	signed, err := s.PresignGet(context.Background(), key, time.Hour)
+	if err != nil {
+		return raw
+	}
+	return signed
+}
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffLen is how many leading bytes are inspected to detect the real file type
+const sniffLen = 512
+
+// Expectation is what the uploader declared for an object
+type Expectation struct {
+	Size        int64  // declared file size in bytes (0 = not checked)
+	ContentType string // declared MIME type
+	SHA256      string // optional whole-file digest (hex or base64)
+	// Parts holds optional per-part digests for multipart uploads. Every part must
+	// be listed (in order, with its size) so part boundaries can be located.
+	Parts []PartChecksum
+}
+
+// PartChecksum is the expected digest for one multipart part
+type PartChecksum struct {
+	PartNumber int
+	Size       int64
+	SHA256     string // optional (hex or base64)
+}
+
+// VerifyResult describes a verified object
+type VerifyResult struct {
+	Size                int64
+	DetectedContentType string
+	SHA256              string // hex digest, only set when checksums were verified
+}
+
+// MismatchError means the stored object doesn't match what the uploader declared
+type MismatchError struct {
+	Reason string
+}
+
+func (e *MismatchError) Error() string {
+	return "upload verification failed: " + e.Reason
+}
+
+// IsMismatch reports whether err is a *MismatchError
+func IsMismatch(err error) bool {
+	var m *MismatchError
+	return errors.As(err, &m)
+}
+
+// VerifyObject HEADs the object, checks its size, sniffs its real content type and,
+// when checksums are supplied, streams it once to verify whole-file and per-part
+// SHA-256 digests. Returns *MismatchError when the object doesn't match.
+func VerifyObject(ctx context.Context, s Store, key string, exp Expectation) (*VerifyResult, error) {
+	info, err := s.Head(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, &MismatchError{Reason: "object not found in storage"}
+		}
+		return nil, err
+	}
+
+	if exp.Size > 0 && info.Size != exp.Size {
+		return nil, &MismatchError{Reason: fmt.Sprintf("size mismatch: declared %d bytes, stored %d bytes", exp.Size, info.Size)}
+	}
+
+	result := &VerifyResult{Size: info.Size}
+
+	needHash := exp.SHA256 != ""
+	for _, p := range exp.Parts {
+		if p.SHA256 != "" {
+			needHash = true
+			break
+		}
+	}
+
+	var head []byte
+	if needHash {
+		head, result.SHA256, err = hashObject(ctx, s, key, info.Size, exp)
+	} else {
+		head, err = readHead(ctx, s, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.DetectedContentType = SniffContentType(head)
+	if exp.ContentType != "" && !ContentTypesCompatible(exp.ContentType, result.DetectedContentType) {
+		return nil, &MismatchError{Reason: fmt.Sprintf("content type mismatch: declared %s, detected %s", exp.ContentType, result.DetectedContentType)}
+	}
+
+	return result, nil
+}
+
+func readHead(ctx context.Context, s Store, key string) ([]byte, error) {
+	r, err := s.Open(ctx, key, 0, sniffLen)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// hashObject streams the object once, feeding the whole-file hash and each part's
+// hash, and returns the sniff header plus the whole-file hex digest
+func hashObject(ctx context.Context, s Store, key string, size int64, exp Expectation) ([]byte, string, error) {
+	if len(exp.Parts) > 0 {
+		var total int64
+		for _, p := range exp.Parts {
+			total += p.Size
+		}
+		if total != size {
+			return nil, "", &MismatchError{Reason: fmt.Sprintf("part sizes add up to %d bytes, stored object is %d bytes", total, size)}
+		}
+	}
+
+	r, err := s.Open(ctx, key, 0, -1)
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+
+	whole := sha256.New()
+	head := &bytes.Buffer{}
+	writers := []io.Writer{whole, &limitedWriter{buf: head, max: sniffLen}}
+
+	if len(exp.Parts) == 0 {
+		if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+			return nil, "", err
+		}
+	} else {
+		for _, p := range exp.Parts {
+			ph := sha256.New()
+			if _, err := io.CopyN(io.MultiWriter(append(writers, ph)...), r, p.Size); err != nil {
+				return nil, "", err
+			}
+			if p.SHA256 != "" && !digestEqual(ph, p.SHA256) {
+				return nil, "", &MismatchError{Reason: fmt.Sprintf("checksum mismatch on part %d", p.PartNumber)}
+			}
+		}
+	}
+
+	if exp.SHA256 != "" && !digestEqual(whole, exp.SHA256) {
+		return nil, "", &MismatchError{Reason: "SHA-256 checksum mismatch"}
+	}
+	return head.Bytes(), hex.EncodeToString(whole.Sum(nil)), nil
+}
+
+// digestEqual compares a hash against an expected digest in hex or base64
+func digestEqual(h hash.Hash, expected string) bool {
+	sum := h.Sum(nil)
+	expected = strings.TrimSpace(expected)
+	if strings.EqualFold(hex.EncodeToString(sum), expected) {
+		return true
+	}
+	return base64.StdEncoding.EncodeToString(sum) == expected
+}
+
+type limitedWriter struct {
+	buf *bytes.Buffer
+	max int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if room := w.max - w.buf.Len(); room > 0 {
+		if len(p) > room {
+			w.buf.Write(p[:room])
+		} else {
+			w.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// SniffContentType detects the MIME type from the leading bytes of a file. It
+// extends http.DetectContentType with the video containers we accept.
+func SniffContentType(head []byte) string {
+	// ISO base media (MP4 / MOV / 3GP): size(4) "ftyp" brand(4)
+	if len(head) >= 12 && string(head[4:8]) == "ftyp" {
+		brand := string(head[8:12])
+		switch {
+		case brand == "qt  ":
+			return "video/quicktime"
+		case strings.HasPrefix(brand, "3g"):
+			return "video/3gpp"
+		case brand == "heic" || brand == "heix" || brand == "mif1":
+			return "image/heic"
+		default:
+			return "video/mp4"
+		}
+	}
+
+	// Matroska / WebM (EBML header)
+	if bytes.HasPrefix(head, []byte{0x1A, 0x45, 0xDF, 0xA3}) {
+		if bytes.Contains(head[:min(len(head), 64)], []byte("webm")) {
+			return "video/webm"
+		}
+		return "video/x-matroska"
+	}
+
+	// MPEG transport stream: sync byte every 188 bytes
+	if len(head) > 188 && head[0] == 0x47 && head[188] == 0x47 {
+		return "video/mp2t"
+	}
+
+	detected := http.DetectContentType(head)
+	if base, _, err := mime.ParseMediaType(detected); err == nil {
+		return base
+	}
+	return detected
+}
+
+// ContentTypesCompatible reports whether a detected MIME type satisfies a declared one.
+// Media types only need the same family (video/quicktime satisfies video/mp4, since
+// browsers often mislabel containers); everything else must match exactly.
+func ContentTypesCompatible(declared, detected string) bool {
+	declaredBase, _, err := mime.ParseMediaType(declared)
+	if err != nil {
+		declaredBase = strings.ToLower(strings.TrimSpace(declared))
+	}
+	if declaredBase == detected {
+		return true
+	}
+
+	declaredFamily, _, _ := strings.Cut(declaredBase, "/")
+	detectedFamily, _, _ := strings.Cut(detected, "/")
+	switch declaredFamily {
+	case "video", "image", "audio":
+		return declaredFamily == detectedFamily
+	}
+	return false
+}
@@ -0,0 +1,269 @@
+package uploads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/scanner"
+	"github.com/unicorn-sport/backend/internal/storage"
+)
+
+// QuarantinePrefix is where scanned upload types land until the scan passes
+const QuarantinePrefix = "quarantine/"
+
+// scannedTypes are the upload types that must pass a malware scan
+var scannedTypes = map[string]bool{
+	"thumbnail":        true,
+	"profile_photo":    true,
+	"cover_image":      true,
+	"academy_logo":     true,
+	"verification_doc": true,
+	"consent_evidence": true,
+}
+
+// RequiresScan reports whether an upload type is quarantined and scanned
+func RequiresScan(uploadType string) bool {
+	return scannedTypes[uploadType]
+}
+
+// QuarantineKey returns the key an upload is stored under until it's scanned
+func QuarantineKey(key string) string {
+	return QuarantinePrefix + key
+}
+
+// Service validates stored uploads against their UploadSession and records the
+// outcome, so media/matches/highlights share one definition of "upload completed"
+type Service struct {
+	db      *gorm.DB
+	store   storage.Store
+	scanner scanner.Scanner
+}
+
+// NewService creates a new upload service
+func NewService(db *gorm.DB, store storage.Store, sc scanner.Scanner) *Service {
+	if sc == nil {
+		sc = scanner.NewNoop()
+	}
+	return &Service{db: db, store: store, scanner: sc}
+}
+
+// Verify checks the session's object (size, sniffed MIME type, optional SHA-256
+// digests), scans quarantined objects for malware, and marks the session completed
+// or failed with a reason. A mismatch or infection is returned as
+// *storage.MismatchError; other errors leave the session untouched.
+func (s *Service) Verify(ctx context.Context, session *domain.UploadSession, parts []storage.PartChecksum) error {
+	exp := storage.Expectation{
+		Size:        session.FileSize,
+		ContentType: session.ContentType,
+		Parts:       parts,
+	}
+	if session.ChecksumSHA256 != nil {
+		exp.SHA256 = *session.ChecksumSHA256
+	}
+
+	result, err := storage.VerifyObject(ctx, s.store, session.S3Key, exp)
+	now := time.Now()
+
+	var mismatch *storage.MismatchError
+	switch {
+	case errors.As(err, &mismatch):
+		session.Status = "failed"
+		session.FailureReason = &mismatch.Reason
+		session.VerifiedAt = &now
+	case err != nil:
+		return err
+	default:
+		session.DetectedContentType = &result.DetectedContentType
+		session.VerifiedAt = &now
+
+		if strings.HasPrefix(session.S3Key, QuarantinePrefix) {
+			if err = s.scan(ctx, session); err != nil && !storage.IsMismatch(err) {
+				return err
+			}
+		}
+		if err == nil {
+			session.Status = "completed"
+			session.FailureReason = nil
+			session.CompletedAt = &now
+		}
+	}
+
+	if saveErr := s.db.WithContext(ctx).Save(session).Error; saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+// scan streams a quarantined object through the scanner. Clean objects are moved
+// to their final key; infected ones stay quarantined, fail the session and are
+// recorded in the audit log.
+func (s *Service) scan(ctx context.Context, session *domain.UploadSession) error {
+	r, err := s.store.Open(ctx, session.S3Key, 0, -1)
+	if err != nil {
+		return err
+	}
+	result, err := s.scanner.Scan(ctx, r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	session.ScannedAt = &now
+
+	if !result.Clean {
+		status := "infected"
+		reason := "malware detected: " + result.Signature
+		session.Status = "failed"
+		session.ScanStatus = &status
+		session.ScanSignature = &result.Signature
+		session.FailureReason = &reason
+		s.auditInfected(ctx, session, result)
+		return &storage.MismatchError{Reason: reason}
+	}
+
+	finalKey := strings.TrimPrefix(session.S3Key, QuarantinePrefix)
+	if err := s.store.Copy(ctx, session.S3Key, finalKey); err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, session.S3Key); err != nil {
+		log.Printf("uploads: failed to delete quarantined object %s: %v", session.S3Key, err)
+	}
+
+	status := "clean"
+	session.S3Key = finalKey
+	session.ScanStatus = &status
+	return nil
+}
+
+func (s *Service) auditInfected(ctx context.Context, session *domain.UploadSession, result *scanner.Result) {
+	resourceType := "upload_session"
+	resourceID := session.ID
+	if session.EntityType != nil && session.EntityID != nil {
+		resourceType = *session.EntityType
+		resourceID = *session.EntityID
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"session_id":  session.ID,
+		"upload_type": session.UploadType,
+		"file_name":   session.FileName,
+		"s3_key":      session.S3Key,
+		"signature":   result.Signature,
+		"engine":      result.Engine,
+	})
+	detailStr := string(details)
+
+	audit := domain.AuditLog{
+		UserID:       &session.UploadedBy,
+		Action:       "upload_infected",
+		ResourceType: resourceType,
+		ResourceID:   &resourceID,
+		Details:      &detailStr,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Create(&audit).Error; err != nil {
+		log.Printf("uploads: failed to write audit entry for infected session %s: %v", session.ID, err)
+	}
+}
+
+// InfectedUploads lists uploads attached to an entity that failed the malware scan
+func (s *Service) InfectedUploads(ctx context.Context, entityType string, entityID uuid.UUID) ([]domain.UploadSession, error) {
+	var sessions []domain.UploadSession
+	err := s.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ? AND scan_status = ?", entityType, entityID, "infected").
+		Order("created_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// InfectedEntityIDs returns which of the given entities have an infected upload
+func (s *Service) InfectedEntityIDs(ctx context.Context, entityType string, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	flagged := map[uuid.UUID]bool{}
+	if len(ids) == 0 {
+		return flagged, nil
+	}
+
+	var found []uuid.UUID
+	err := s.db.WithContext(ctx).Model(&domain.UploadSession{}).
+		Where("entity_type = ? AND entity_id IN ? AND scan_status = ?", entityType, ids, "infected").
+		Distinct().Pluck("entity_id", &found).Error
+	for _, id := range found {
+		flagged[id] = true
+	}
+	return flagged, err
+}
+
+// VerifyByKey finds the latest session for an object key and makes sure it has
+// passed verification, verifying direct (single PUT) uploads on first use. Objects
+// uploaded without a session (legacy flows) return a nil session and no error.
+func (s *Service) VerifyByKey(ctx context.Context, key string) (*domain.UploadSession, error) {
+	var session domain.UploadSession
+	err := s.db.WithContext(ctx).Where("s3_key = ?", key).Order("created_at DESC").First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch session.Status {
+	case "completed":
+		return &session, nil
+	case "failed":
+		reason := "upload failed verification"
+		if session.FailureReason != nil {
+			reason = *session.FailureReason
+		}
+		return &session, &storage.MismatchError{Reason: reason}
+	case "expired":
+		return &session, &storage.MismatchError{Reason: "upload session expired"}
+	}
+
+	// Multipart uploads must be completed through the complete endpoint first
+	if session.S3UploadID != nil && session.CompletedAt == nil {
+		return &session, &storage.MismatchError{Reason: "multipart upload not completed"}
+	}
+	return &session, s.Verify(ctx, &session, nil)
+}
+
+// PartChecksums pairs client-supplied per-part digests with the part sizes reported
+// by storage. It must be called before the multipart upload is completed, while the
+// parts can still be listed. Only parts being completed are included. Returns nil
+// when no digests were supplied.
+func (s *Service) PartChecksums(ctx context.Context, session *domain.UploadSession, completed []storage.CompletedPart, digests map[int]string) ([]storage.PartChecksum, error) {
+	if len(digests) == 0 || session.S3UploadID == nil {
+		return nil, nil
+	}
+
+	uploaded, err := s.store.ListParts(ctx, session.S3Key, *session.S3UploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make(map[int]bool, len(completed))
+	for _, p := range completed {
+		keep[p.PartNumber] = true
+	}
+
+	parts := make([]storage.PartChecksum, 0, len(completed))
+	for _, p := range uploaded {
+		if !keep[p.PartNumber] {
+			continue
+		}
+		parts = append(parts, storage.PartChecksum{
+			PartNumber: p.PartNumber,
+			Size:       p.Size,
+			SHA256:     digests[p.PartNumber],
+		})
+	}
+	return parts, nil
+}
@@ -0,0 +1,226 @@
+package verification
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/notify"
+)
+
+// Attestation statuses
+const (
+	AttestationPending   = "pending"
+	AttestationConfirmed = "confirmed"
+	AttestationDisputed  = "disputed"
+	AttestationCancelled = "cancelled" // the player was moved to another school or unlinked
+)
+
+// DisputeReasons are why a school can't confirm a student, with display names
+var DisputeReasons = map[string]string{
+	"not_enrolled":  "Not enrolled at this school",
+	"dob_incorrect": "Date of birth doesn't match school records",
+	"other":         "Other",
+}
+
+var ErrAttestationClosed = errors.New("attestation has already been answered")
+
+// RequestAttestation asks the school to confirm the player's enrollment and date
+// of birth, and notifies its staff. A pending request to another school is
+// cancelled; one to the same school is returned as is.
+func RequestAttestation(db *gorm.DB, playerID, schoolID, requestedBy uuid.UUID) (*domain.SchoolAttestation, error) {
+	var a *domain.SchoolAttestation
+	created := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var pending domain.SchoolAttestation
+		err := tx.Where("player_id = ? AND status = ?", playerID, AttestationPending).First(&pending).Error
+		switch {
+		case err == nil && pending.SchoolID == schoolID:
+			a = &pending
+			return nil
+		case err == nil:
+			if err := cancel(tx, playerID); err != nil {
+				return err
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		now := time.Now()
+		a = &domain.SchoolAttestation{
+			SchoolID:    schoolID,
+			PlayerID:    playerID,
+			Status:      AttestationPending,
+			RequestedBy: requestedBy,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		created = true
+		return tx.Create(a).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		var staff []uuid.UUID
+		db.Model(&domain.SchoolAdmin{}).Where("school_id = ?", schoolID).Pluck("user_id", &staff)
+		notify.Send(db, notify.Message{
+			Type:  "school_attestation_requested",
+			Title: "A student is waiting for your confirmation",
+			Body:  "Please confirm their enrollment and date of birth against your records.",
+			Link:  "/school/attestations",
+		}, staff...)
+	}
+	return a, nil
+}
+
+// CancelAttestations withdraws the player's pending attestation, e.g. when they
+// are unlinked from their school
+func CancelAttestations(db *gorm.DB, playerID uuid.UUID) error {
+	return cancel(db, playerID)
+}
+
+func cancel(db *gorm.DB, playerID uuid.UUID) error {
+	return db.Model(&domain.SchoolAttestation{}).
+		Where("player_id = ? AND status = ?", playerID, AttestationPending).
+		Updates(map[string]interface{}{"status": AttestationCancelled, "updated_at": time.Now()}).Error
+}
+
+// ConfirmAttestation records the school's confirmation and verifies the player on
+// it. Players already verified another way keep their existing source. The date of
+// birth the school confirmed is kept so a later change to it can be flagged.
+func ConfirmAttestation(db *gorm.DB, a *domain.SchoolAttestation, attesterID uuid.UUID, note *string) error {
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var player domain.Player
+		if err := tx.Select("id, date_of_birth").First(&player, "id = ?", a.PlayerID).Error; err != nil {
+			return err
+		}
+		dob := player.DateOfBirth
+		result := tx.Model(&domain.SchoolAttestation{}).
+			Where("id = ? AND status = ?", a.ID, AttestationPending).
+			Updates(map[string]interface{}{
+				"status":                 AttestationConfirmed,
+				"reported_date_of_birth": dob,
+				"note":                   note,
+				"attested_by":            attesterID,
+				"attested_at":            now,
+				"updated_at":             now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAttestationClosed
+		}
+		a.Status, a.ReportedDateOfBirth, a.Note, a.AttestedBy, a.AttestedAt = AttestationConfirmed, &dob, note, &attesterID, &now
+		return attest(tx, a.PlayerID, attesterID, now, note)
+	})
+	if err != nil {
+		return err
+	}
+
+	var player domain.Player
+	if err := db.Select("id, user_id").First(&player, "id = ?", a.PlayerID).Error; err == nil && player.UserID != nil {
+		notify.Send(db, notify.Message{
+			Type:  "school_attestation_confirmed",
+			Title: "Your school confirmed your enrollment",
+			Body:  "Your profile now shows as verified.",
+			Link:  "/player/verification",
+		}, *player.UserID)
+	}
+	return nil
+}
+
+// AttestRoster records confirmed attestations for players a school registered
+// itself and verifies them. Run it in the transaction that creates the players.
+func AttestRoster(tx *gorm.DB, players []domain.Player, schoolID, attesterID uuid.UUID) error {
+	now := time.Now()
+	note := "Registered by the school on its roster"
+	for i := range players {
+		dob := players[i].DateOfBirth
+		if err := tx.Create(&domain.SchoolAttestation{
+			SchoolID:            schoolID,
+			PlayerID:            players[i].ID,
+			Status:              AttestationConfirmed,
+			ReportedDateOfBirth: &dob,
+			Note:                &note,
+			RequestedBy:         attesterID,
+			AttestedBy:          &attesterID,
+			AttestedAt:          &now,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := attest(tx, players[i].ID, attesterID, now, &note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attest verifies the player on a school attestation and records it in the history
+func attest(tx *gorm.DB, playerID, attesterID uuid.UUID, now time.Time, note *string) error {
+	if err := tx.Model(&domain.Player{}).
+		Where("id = ? AND verification_status <> ?", playerID, "verified").
+		Updates(map[string]interface{}{
+			"verification_status": "verified",
+			"verification_source": SourceSchool,
+			"verified_at":         now,
+			"verified_by":         attesterID,
+			"updated_at":          now,
+		}).Error; err != nil {
+		return err
+	}
+	return recordEvent(tx, nil, playerID, ActionSchoolAttested, attesterID, note)
+}
+
+// DisputeAttestation records that the school couldn't confirm the player and
+// tells the admin who asked. The player's verification status is left for the
+// admin to resolve.
+func DisputeAttestation(db *gorm.DB, a *domain.SchoolAttestation, attesterID uuid.UUID, reason string, reportedDOB *time.Time, note *string) error {
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.SchoolAttestation{}).
+			Where("id = ? AND status = ?", a.ID, AttestationPending).
+			Updates(map[string]interface{}{
+				"status":                 AttestationDisputed,
+				"dispute_reason":         reason,
+				"reported_date_of_birth": reportedDOB,
+				"note":                   note,
+				"attested_by":            attesterID,
+				"attested_at":            now,
+				"updated_at":             now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAttestationClosed
+		}
+		a.Status, a.DisputeReason, a.ReportedDateOfBirth, a.Note = AttestationDisputed, &reason, reportedDOB, note
+		a.AttestedBy, a.AttestedAt = &attesterID, &now
+
+		summary := DisputeReasons[reason]
+		if note != nil && *note != "" {
+			summary += ": " + *note
+		}
+		return recordEvent(tx, nil, a.PlayerID, ActionSchoolDisputed, attesterID, &summary)
+	})
+	if err != nil {
+		return err
+	}
+
+	notify.Send(db, notify.Message{
+		Type:  "school_attestation_disputed",
+		Title: "A school couldn't confirm a student",
+		Body:  DisputeReasons[reason],
+		Link:  "/admin/school-attestations?status=disputed",
+	}, a.RequestedBy)
+	return nil
+}
@@ -0,0 +1,343 @@
+package verification
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/notify"
+)
+
+// DocTypes are the identity documents accepted, with their display names
+var DocTypes = map[string]string{
+	"nin":               "National ID (NIN)",
+	"passport":          "Passport",
+	"birth_certificate": "Birth certificate",
+	"school_id":         "School ID",
+}
+
+// DocPrefix is where verification documents are stored. Nothing under it is public.
+const DocPrefix = "private/verification/"
+
+// Verification sources, stored on the player
+const (
+	SourceDocument = "document" // identity document approved in the review queue
+	SourceSchool   = "school"   // enrollment and date of birth attested by a partner school
+	SourceAdmin    = "admin"    // set directly by an admin
+)
+
+// Submission statuses
+const (
+	StatusSubmitted = "submitted"
+	StatusInReview  = "in_review" // claimed by a reviewer
+	StatusMoreInfo  = "more_info_requested"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+)
+
+// OpenStatuses are the statuses of submissions still in the queue
+var OpenStatuses = []string{StatusSubmitted, StatusInReview, StatusMoreInfo}
+
+// History actions
+const (
+	ActionSubmitted        = "submitted"
+	ActionResubmitted      = "resubmitted"
+	ActionClaimed          = "claimed"
+	ActionReleased         = "released"
+	ActionApproved         = "approved"
+	ActionRejected         = "rejected"
+	ActionMoreInfo         = "more_info_requested"
+	ActionManualVerified   = "manually_verified"
+	ActionManualUnverified = "manually_unverified"
+	ActionSchoolAttested   = "school_attested"
+	ActionSchoolDisputed   = "school_disputed"
+)
+
+var (
+	ErrAlreadyVerified = errors.New("player is already verified")
+	ErrUnderReview     = errors.New("a reviewer is working on the current submission")
+	ErrClaimed         = errors.New("submission is claimed by another reviewer")
+	ErrNotClaimed      = errors.New("claim the submission before deciding on it")
+	ErrAwaitingInfo    = errors.New("waiting for the player to send more information")
+	ErrClosed          = errors.New("submission has already been decided")
+)
+
+// Open returns the player's open submission, or nil
+func Open(db *gorm.DB, playerID uuid.UUID) (*domain.PlayerVerification, error) {
+	var v domain.PlayerVerification
+	err := db.Where("player_id = ? AND status IN ?", playerID, OpenStatuses).First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Submit queues a document for the player. While a submission is waiting for more
+// information (or hasn't been claimed yet) the new document replaces it and it goes
+// back to its reviewer.
+func Submit(db *gorm.DB, player *domain.Player, docType, docURL string, submittedBy uuid.UUID) (*domain.PlayerVerification, error) {
+	if player.VerificationStatus == "verified" {
+		return nil, ErrAlreadyVerified
+	}
+
+	var v *domain.PlayerVerification
+	err := db.Transaction(func(tx *gorm.DB) error {
+		open, err := Open(tx, player.ID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+
+		if open == nil {
+			v = &domain.PlayerVerification{
+				PlayerID:    player.ID,
+				DocType:     docType,
+				DocURL:      docURL,
+				Status:      StatusSubmitted,
+				SubmittedBy: submittedBy,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+			if err := tx.Create(v).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&domain.Player{}).Where("id = ?", player.ID).
+				Updates(map[string]interface{}{"verification_status": "pending", "updated_at": now}).Error; err != nil {
+				return err
+			}
+			return record(tx, v, ActionSubmitted, submittedBy, nil)
+		}
+
+		if open.Status == StatusInReview {
+			return ErrUnderReview
+		}
+		status := StatusSubmitted
+		if open.ClaimedBy != nil {
+			status = StatusInReview
+		}
+		if err := tx.Model(open).Updates(map[string]interface{}{
+			"doc_type":   docType,
+			"doc_url":    docURL,
+			"status":     status,
+			"updated_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		v = open
+		return record(tx, v, ActionResubmitted, submittedBy, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if v.ClaimedBy != nil {
+		notify.Send(db, notify.Message{
+			Type:  "verification_resubmitted",
+			Title: "A verification you're reviewing has new documents",
+			Link:  "/admin/verifications/" + v.ID.String(),
+		}, *v.ClaimedBy)
+	}
+	return v, nil
+}
+
+// Claim assigns an open submission to the reviewer. Reviewers can re-claim their own.
+func Claim(db *gorm.DB, v *domain.PlayerVerification, reviewerID uuid.UUID) error {
+	if v.Status == StatusMoreInfo {
+		return ErrAwaitingInfo
+	}
+	now := time.Now()
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.PlayerVerification{}).
+			Where("id = ? AND status IN ? AND (claimed_by IS NULL OR claimed_by = ?)", v.ID, []string{StatusSubmitted, StatusInReview}, reviewerID).
+			Updates(map[string]interface{}{"status": StatusInReview, "claimed_by": reviewerID, "claimed_at": now, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return transitionError(tx, v.ID)
+		}
+		v.Status, v.ClaimedBy, v.ClaimedAt = StatusInReview, &reviewerID, &now
+		return record(tx, v, ActionClaimed, reviewerID, nil)
+	})
+}
+
+// Release puts a claimed submission back in the queue
+func Release(db *gorm.DB, v *domain.PlayerVerification, reviewerID uuid.UUID) error {
+	now := time.Now()
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.PlayerVerification{}).
+			Where("id = ? AND status = ? AND claimed_by = ?", v.ID, StatusInReview, reviewerID).
+			Updates(map[string]interface{}{"status": StatusSubmitted, "claimed_by": nil, "claimed_at": nil, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return transitionError(tx, v.ID)
+		}
+		v.Status, v.ClaimedBy, v.ClaimedAt = StatusSubmitted, nil, nil
+		return record(tx, v, ActionReleased, reviewerID, nil)
+	})
+}
+
+// Approve verifies the player with the submitted document
+func Approve(db *gorm.DB, v *domain.PlayerVerification, reviewerID uuid.UUID, note *string) error {
+	err := decide(db, v, reviewerID, StatusApproved, ActionApproved, note, func(tx *gorm.DB, now time.Time) error {
+		return tx.Model(&domain.Player{}).Where("id = ?", v.PlayerID).Updates(map[string]interface{}{
+			"verification_status":   "verified",
+			"verification_source":   SourceDocument,
+			"verification_doc_type": v.DocType,
+			"verification_doc_url":  v.DocURL,
+			"verified_at":           now,
+			"verified_by":           reviewerID,
+			"updated_at":            now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+	notifyPlayer(db, v, reviewerID, notify.Message{
+		Type:  "verification_approved",
+		Title: "Profile verified",
+		Body:  "Your identity document was approved and your profile now shows as verified.",
+	})
+	return nil
+}
+
+// Reject closes the submission. The reason is shown to the player.
+func Reject(db *gorm.DB, v *domain.PlayerVerification, reviewerID uuid.UUID, reason string) error {
+	err := decide(db, v, reviewerID, StatusRejected, ActionRejected, &reason, func(tx *gorm.DB, now time.Time) error {
+		return tx.Model(&domain.Player{}).Where("id = ?", v.PlayerID).
+			Updates(map[string]interface{}{"verification_status": "rejected", "updated_at": now}).Error
+	})
+	if err != nil {
+		return err
+	}
+	notifyPlayer(db, v, reviewerID, notify.Message{
+		Type:  "verification_rejected",
+		Title: "Verification document rejected",
+		Body:  reason,
+	})
+	return nil
+}
+
+// RequestInfo sends the submission back to the player with what's missing. It
+// stays claimed, so the resubmission returns to the same reviewer.
+func RequestInfo(db *gorm.DB, v *domain.PlayerVerification, reviewerID uuid.UUID, message string) error {
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.PlayerVerification{}).
+			Where("id = ? AND status = ? AND claimed_by = ?", v.ID, StatusInReview, reviewerID).
+			Updates(map[string]interface{}{"status": StatusMoreInfo, "reason": message, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return transitionError(tx, v.ID)
+		}
+		v.Status, v.Reason = StatusMoreInfo, &message
+		return record(tx, v, ActionMoreInfo, reviewerID, &message)
+	})
+	if err != nil {
+		return err
+	}
+	notifyPlayer(db, v, reviewerID, notify.Message{
+		Type:  "verification_more_info",
+		Title: "More information needed to verify your profile",
+		Body:  message,
+	})
+	return nil
+}
+
+// RecordManual adds history for verification status set directly on players
+// (create, edit or bulk actions) rather than through the queue
+func RecordManual(db *gorm.DB, playerIDs []uuid.UUID, actorID uuid.UUID, verified bool) error {
+	action := ActionManualUnverified
+	if verified {
+		action = ActionManualVerified
+	}
+	events := make([]domain.VerificationEvent, len(playerIDs))
+	for i, id := range playerIDs {
+		events[i] = domain.VerificationEvent{PlayerID: id, Action: action, ActorID: actorID, CreatedAt: time.Now()}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	return db.CreateInBatches(&events, 500).Error
+}
+
+// decide closes a claimed submission and applies the player update in one transaction
+func decide(db *gorm.DB, v *domain.PlayerVerification, reviewerID uuid.UUID, status, action string, reason *string, apply func(*gorm.DB, time.Time) error) error {
+	now := time.Now()
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.PlayerVerification{}).
+			Where("id = ? AND status = ? AND claimed_by = ?", v.ID, StatusInReview, reviewerID).
+			Updates(map[string]interface{}{"status": status, "reason": reason, "decided_by": reviewerID, "decided_at": now, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return transitionError(tx, v.ID)
+		}
+		if err := apply(tx, now); err != nil {
+			return err
+		}
+		v.Status, v.Reason, v.DecidedBy, v.DecidedAt = status, reason, &reviewerID, &now
+		return record(tx, v, action, reviewerID, reason)
+	})
+}
+
+// transitionError explains why a conditional update matched nothing
+func transitionError(db *gorm.DB, id uuid.UUID) error {
+	var current domain.PlayerVerification
+	if err := db.First(&current, "id = ?", id).Error; err != nil {
+		return err
+	}
+	switch current.Status {
+	case StatusApproved, StatusRejected:
+		return ErrClosed
+	case StatusMoreInfo:
+		return ErrAwaitingInfo
+	case StatusSubmitted:
+		return ErrNotClaimed
+	}
+	return ErrClaimed
+}
+
+func record(db *gorm.DB, v *domain.PlayerVerification, action string, actorID uuid.UUID, note *string) error {
+	return recordEvent(db, &v.ID, v.PlayerID, action, actorID, note)
+}
+
+func recordEvent(db *gorm.DB, verificationID *uuid.UUID, playerID uuid.UUID, action string, actorID uuid.UUID, note *string) error {
+	return db.Create(&domain.VerificationEvent{
+		VerificationID: verificationID,
+		PlayerID:       playerID,
+		Action:         action,
+		ActorID:        actorID,
+		Note:           note,
+		CreatedAt:      time.Now(),
+	}).Error
+}
+
+// notifyPlayer tells the player's account and whoever submitted the document,
+// other than the reviewer
+func notifyPlayer(db *gorm.DB, v *domain.PlayerVerification, reviewerID uuid.UUID, msg notify.Message) {
+	var player domain.Player
+	if err := db.Select("id, user_id").First(&player, "id = ?", v.PlayerID).Error; err != nil {
+		return
+	}
+	var recipients []uuid.UUID
+	if player.UserID != nil {
+		recipients = append(recipients, *player.UserID)
+	}
+	if v.SubmittedBy != reviewerID {
+		recipients = append(recipients, v.SubmittedBy)
+	}
+	msg.Link = "/player/verification"
+	notify.Send(db, msg, recipients...)
+}
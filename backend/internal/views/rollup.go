@@ -0,0 +1,205 @@
+package views
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Rollups are recomputed from the raw view tables rather than incremented, so they
+// stay correct with several API instances and can be rebuilt at any time.
+
+const rollupHighlightsSQL = `
+INSERT INTO view_rollups (id, day, asset_type, asset_id, player_id, views, unique_viewers, updated_at)
+SELECT gen_random_uuid(), DATE(v.created_at), 'highlight', v.highlight_id, h.player_id,
+       COUNT(*), COUNT(DISTINCT COALESCE(v.viewer_id::text, v.ip_hash)), NOW()
+FROM highlight_views v
+JOIN player_highlights h ON h.id = v.highlight_id
+WHERE v.created_at >= ? AND v.created_at < ?
+GROUP BY DATE(v.created_at), v.highlight_id, h.player_id
+ON CONFLICT (day, asset_type, asset_id) DO UPDATE SET
+    player_id = EXCLUDED.player_id,
+    views = EXCLUDED.views,
+    unique_viewers = EXCLUDED.unique_viewers,
+    updated_at = EXCLUDED.updated_at`
+
+const rollupVideosSQL = `
+INSERT INTO view_rollups (id, day, asset_type, asset_id, player_id, views, unique_viewers, updated_at)
+SELECT gen_random_uuid(), DATE(v.created_at), 'video', v.video_id,
+       (ARRAY_AGG(v.player_id) FILTER (WHERE v.player_id IS NOT NULL))[1],
+       COUNT(*), COUNT(DISTINCT COALESCE(v.viewer_id::text, v.ip_hash)), NOW()
+FROM video_views v
+WHERE v.created_at >= ? AND v.created_at < ?
+GROUP BY DATE(v.created_at), v.video_id
+ON CONFLICT (day, asset_type, asset_id) DO UPDATE SET
+    player_id = EXCLUDED.player_id,
+    views = EXCLUDED.views,
+    unique_viewers = EXCLUDED.unique_viewers,
+    updated_at = EXCLUDED.updated_at`
+
+const rollupProfilesSQL = `
+INSERT INTO view_rollups (id, day, asset_type, asset_id, player_id, views, unique_viewers, updated_at)
+SELECT gen_random_uuid(), DATE(v.created_at), 'profile', v.player_id, v.player_id,
+       COUNT(*), COUNT(DISTINCT COALESCE(v.viewer_id::text, v.ip_hash)), NOW()
+FROM profile_views v
+WHERE v.created_at >= ? AND v.created_at < ?
+GROUP BY DATE(v.created_at), v.player_id
+ON CONFLICT (day, asset_type, asset_id) DO UPDATE SET
+    views = EXCLUDED.views,
+    unique_viewers = EXCLUDED.unique_viewers,
+    updated_at = EXCLUDED.updated_at`
+
+// PlayerHighlight.ViewCount is the all-time sum of its daily rollups
+const syncHighlightCountsSQL = `
+UPDATE player_highlights h SET view_count = r.total
+FROM (
+    SELECT asset_id, SUM(views) AS total
+    FROM view_rollups
+    WHERE asset_type = 'highlight'
+    GROUP BY asset_id
+) r
+WHERE h.id = r.asset_id AND h.view_count <> r.total`
+
+// Rollup recomputes the daily rollups for every day touching [from, to) and
+// refreshes the highlight view counts
+func (t *Tracker) Rollup(ctx context.Context, from, to time.Time) error {
+	from = startOfDay(from)
+	db := t.db.WithContext(ctx)
+
+	if err := db.Exec(rollupHighlightsSQL, from, to).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(rollupVideosSQL, from, to).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(rollupProfilesSQL, from, to).Error; err != nil {
+		return err
+	}
+	return db.Exec(syncHighlightCountsSQL).Error
+}
+
+// RunRollups keeps today's and yesterday's rollups current until ctx is cancelled.
+// On first start with an empty rollup table it backfills from the oldest view.
+func (t *Tracker) RunRollups(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	from := time.Now().AddDate(0, 0, -1)
+	if oldest, ok := t.needsBackfill(ctx); ok {
+		from = oldest
+	}
+
+	for {
+		now := time.Now()
+		if err := t.Rollup(ctx, from, now.Add(time.Minute)); err != nil {
+			log.Printf("view rollups: %v", err)
+		}
+		// Yesterday is redone once more to pick up views flushed after midnight
+		from = now.AddDate(0, 0, -1)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// needsBackfill returns the oldest recorded view when no rollups exist yet
+func (t *Tracker) needsBackfill(ctx context.Context) (time.Time, bool) {
+	db := t.db.WithContext(ctx)
+
+	var rollups int64
+	db.Model(&domain.ViewRollup{}).Limit(1).Count(&rollups)
+	if rollups > 0 {
+		return time.Time{}, false
+	}
+
+	var oldest struct{ At *time.Time }
+	db.Raw(`SELECT LEAST(
+        (SELECT MIN(created_at) FROM highlight_views),
+        (SELECT MIN(created_at) FROM video_views),
+        (SELECT MIN(created_at) FROM profile_views)) AS at`).Scan(&oldest)
+	if oldest.At == nil {
+		return time.Time{}, false
+	}
+	return *oldest.At, true
+}
+
+// DailyCount is one day of a view series
+type DailyCount struct {
+	Date          string `json:"date"`
+	Views         int    `json:"views"`
+	UniqueViewers int    `json:"unique_viewers"`
+}
+
+// Daily returns views per day since from, optionally for one asset type and/or player
+func (t *Tracker) Daily(ctx context.Context, from time.Time, assetType string, playerID *uuid.UUID) ([]DailyCount, error) {
+	query := t.db.WithContext(ctx).Model(&domain.ViewRollup{}).
+		Select("TO_CHAR(day, 'YYYY-MM-DD') AS date, SUM(views) AS views, SUM(unique_viewers) AS unique_viewers").
+		Where("day >= ?", startOfDay(from))
+	if assetType != "" {
+		query = query.Where("asset_type = ?", assetType)
+	}
+	if playerID != nil {
+		query = query.Where("player_id = ?", *playerID)
+	}
+
+	var counts []DailyCount
+	err := query.Group("day").Order("day").Scan(&counts).Error
+	return counts, err
+}
+
+// Weekly returns one asset type's views per week (weeks start Monday) since from,
+// optionally for one player
+func (t *Tracker) Weekly(ctx context.Context, from time.Time, assetType string, playerID *uuid.UUID) ([]DailyCount, error) {
+	query := t.db.WithContext(ctx).Model(&domain.ViewRollup{}).
+		Select("TO_CHAR(DATE_TRUNC('week', day), 'YYYY-MM-DD') AS date, SUM(views) AS views, SUM(unique_viewers) AS unique_viewers").
+		Where("day >= ? AND asset_type = ?", startOfDay(from), assetType)
+	if playerID != nil {
+		query = query.Where("player_id = ?", *playerID)
+	}
+
+	var counts []DailyCount
+	err := query.Group("DATE_TRUNC('week', day)").Order("DATE_TRUNC('week', day)").Scan(&counts).Error
+	return counts, err
+}
+
+// TopCount is an asset or player ranked by views. UniqueViewers sums the daily
+// unique viewer counts, so a viewer returning on another day counts again.
+type TopCount struct {
+	ID            uuid.UUID `json:"id"`
+	Views         int       `json:"views"`
+	UniqueViewers int       `json:"unique_viewers"`
+}
+
+// TopAssets ranks assets of one type by views since from
+func (t *Tracker) TopAssets(ctx context.Context, from time.Time, assetType string, limit int) ([]TopCount, error) {
+	var top []TopCount
+	err := t.db.WithContext(ctx).Model(&domain.ViewRollup{}).
+		Select("asset_id AS id, SUM(views) AS views, SUM(unique_viewers) AS unique_viewers").
+		Where("day >= ? AND asset_type = ?", startOfDay(from), assetType).
+		Group("asset_id").Order("views DESC").Limit(limit).
+		Scan(&top).Error
+	return top, err
+}
+
+// TopPlayers ranks players by views of all their assets since from
+func (t *Tracker) TopPlayers(ctx context.Context, from time.Time, limit int) ([]TopCount, error) {
+	var top []TopCount
+	err := t.db.WithContext(ctx).Model(&domain.ViewRollup{}).
+		Select("player_id AS id, SUM(views) AS views, SUM(unique_viewers) AS unique_viewers").
+		Where("day >= ? AND player_id IS NOT NULL", startOfDay(from)).
+		Group("player_id").Order("views DESC").Limit(limit).
+		Scan(&top).Error
+	return top, err
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,258 @@
+package views
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// Asset types tracked by the view pipeline
+const (
+	AssetHighlight = "highlight"
+	AssetVideo     = "video"
+	AssetProfile   = "profile" // AssetID is the player ID
+)
+
+// botPattern matches user agents of crawlers, link previewers, monitors and scripts
+var botPattern = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|curl|wget|python-requests|go-http-client|httpclient|headless|lighthouse|facebookexternalhit|embedly|preview|monitor|pingdom|uptime`)
+
+// Event is one view, built from the request before the handler returns so nothing
+// touches the gin.Context once it's been recycled
+type Event struct {
+	AssetType string
+	AssetID   uuid.UUID
+	PlayerID  *uuid.UUID
+	ViewerID  *uuid.UUID // nil for anonymous viewers
+	IP        string     // hashed on record, never stored
+	UserAgent string
+	Source    string
+
+	// Profile views only: what the player may see about the viewer
+	Viewer *ViewerInfo
+}
+
+// ViewerInfo describes a logged-in profile viewer
+type ViewerInfo struct {
+	Role             string
+	OrganizationType *string
+	Country          *string
+	IdentityShared   bool
+}
+
+// Config tunes deduplication and batching
+type Config struct {
+	Salt          string        // secret mixed into IP hashes
+	DedupWindow   time.Duration // repeat views by the same viewer inside the window are dropped
+	FlushInterval time.Duration // max delay before buffered views are written
+	BatchSize     int
+}
+
+// Tracker records views asynchronously: bots are dropped, repeats within the dedup
+// window are ignored, and the rest are buffered and written in batches by Run.
+// Deduplication is per process, so with several API instances the daily rollups'
+// unique viewer counts are the number to trust.
+type Tracker struct {
+	db     *gorm.DB
+	cfg    Config
+	events chan pendingView
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+type pendingView struct {
+	Event
+	ipHash string
+	at     time.Time
+}
+
+// NewTracker creates a view tracker. Call Run to start writing views.
+func NewTracker(db *gorm.DB, cfg Config) *Tracker {
+	if cfg.DedupWindow <= 0 {
+		cfg.DedupWindow = 30 * time.Minute
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	return &Tracker{
+		db:     db,
+		cfg:    cfg,
+		events: make(chan pendingView, cfg.BatchSize*20),
+		seen:   map[string]time.Time{},
+	}
+}
+
+// HashIP returns the salted hash stored instead of a client IP
+func (t *Tracker) HashIP(ip string) string {
+	mac := hmac.New(sha256.New, []byte(t.cfg.Salt))
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsBot reports whether a user agent looks automated. Empty user agents count as bots.
+func IsBot(userAgent string) bool {
+	return strings.TrimSpace(userAgent) == "" || botPattern.MatchString(userAgent)
+}
+
+// Record queues a view. It returns false when the view was filtered out as a bot
+// or a repeat, or dropped because the write buffer is full. Never blocks.
+func (t *Tracker) Record(e Event) bool {
+	if IsBot(e.UserAgent) {
+		return false
+	}
+
+	view := pendingView{Event: e, ipHash: t.HashIP(e.IP), at: time.Now()}
+
+	viewer := view.ipHash
+	if e.ViewerID != nil {
+		viewer = e.ViewerID.String()
+	}
+	key := e.AssetType + ":" + e.AssetID.String() + ":" + viewer
+
+	t.mu.Lock()
+	if last, ok := t.seen[key]; ok && view.at.Sub(last) < t.cfg.DedupWindow {
+		t.mu.Unlock()
+		return false
+	}
+	t.seen[key] = view.at
+	t.mu.Unlock()
+
+	select {
+	case t.events <- view:
+		return true
+	default:
+		log.Printf("views: buffer full, dropping %s view of %s", e.AssetType, e.AssetID)
+		return false
+	}
+}
+
+// Run writes queued views in batches until ctx is cancelled, then flushes what's left
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]pendingView, 0, t.cfg.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			t.write(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case v := <-t.events:
+					batch = append(batch, v)
+				default:
+					flush()
+					return
+				}
+			}
+		case v := <-t.events:
+			batch = append(batch, v)
+			if len(batch) >= t.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			t.pruneSeen()
+		}
+	}
+}
+
+// write inserts a batch into the per-asset view tables
+func (t *Tracker) write(batch []pendingView) {
+	var highlightViews []domain.HighlightView
+	var videoViews []domain.VideoView
+	var profileViews []domain.ProfileView
+
+	for _, v := range batch {
+		ipHash := v.ipHash
+		var source *string
+		if v.Source != "" {
+			s := v.Source
+			source = &s
+		}
+
+		switch v.AssetType {
+		case AssetHighlight:
+			highlightViews = append(highlightViews, domain.HighlightView{
+				HighlightID: v.AssetID,
+				ViewerID:    v.ViewerID,
+				Source:      source,
+				IPHash:      &ipHash,
+				CreatedAt:   v.at,
+			})
+		case AssetVideo:
+			videoViews = append(videoViews, domain.VideoView{
+				VideoID:   v.AssetID,
+				ViewerID:  v.ViewerID,
+				PlayerID:  v.PlayerID,
+				Source:    source,
+				IPHash:    &ipHash,
+				CreatedAt: v.at,
+			})
+		case AssetProfile:
+			view := domain.ProfileView{
+				PlayerID:  v.AssetID,
+				ViewerID:  v.ViewerID,
+				Source:    source,
+				IPHash:    &ipHash,
+				CreatedAt: v.at,
+			}
+			if v.Viewer != nil {
+				role := v.Viewer.Role
+				view.ViewerRole = &role
+				view.OrganizationType = v.Viewer.OrganizationType
+				view.Country = v.Viewer.Country
+				view.IdentityShared = v.Viewer.IdentityShared
+			}
+			profileViews = append(profileViews, view)
+		}
+	}
+
+	if len(highlightViews) > 0 {
+		if err := t.db.CreateInBatches(&highlightViews, t.cfg.BatchSize).Error; err != nil {
+			log.Printf("views: failed to write %d highlight views: %v", len(highlightViews), err)
+		}
+	}
+	if len(videoViews) > 0 {
+		if err := t.db.CreateInBatches(&videoViews, t.cfg.BatchSize).Error; err != nil {
+			log.Printf("views: failed to write %d video views: %v", len(videoViews), err)
+		}
+	}
+	if len(profileViews) > 0 {
+		if err := t.db.CreateInBatches(&profileViews, t.cfg.BatchSize).Error; err != nil {
+			log.Printf("views: failed to write %d profile views: %v", len(profileViews), err)
+		}
+	}
+}
+
+// pruneSeen forgets viewers whose dedup window has passed
+func (t *Tracker) pruneSeen() {
+	cutoff := time.Now().Add(-t.cfg.DedupWindow)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, at := range t.seen {
+		if at.Before(cutoff) {
+			delete(t.seen, key)
+		}
+	}
+}
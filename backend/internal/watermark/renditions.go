@@ -0,0 +1,72 @@
+package watermark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/unicorn-sport/backend/internal/cdn"
+	"github.com/unicorn-sport/backend/internal/domain"
+	"github.com/unicorn-sport/backend/internal/storage"
+)
+
+// A watermarked video keeps its renditions under one storage prefix, as
+// <prefix>/a/index.m3u8 and <prefix>/b/index.m3u8 with their segments alongside.
+
+// maxPlaylistBytes caps how much of a playlist is read
+const maxPlaylistBytes = 8 << 20
+
+// CheckRenditions verifies that both renditions exist under prefix with the same,
+// non-zero number of segments
+func CheckRenditions(ctx context.Context, store storage.Store, prefix string) error {
+	counts := map[string]int{}
+	for _, variant := range []string{VariantA, VariantB} {
+		src, err := readPlaylist(ctx, store, path.Join(prefix, variant, PlaylistName))
+		if err != nil {
+			return fmt.Errorf("rendition %q playlist not found under %s", variant, prefix)
+		}
+		counts[variant] = CountSegments(src)
+	}
+	if counts[VariantA] == 0 || counts[VariantA] != counts[VariantB] {
+		return errors.New("A and B renditions must have the same, non-zero number of segments")
+	}
+	return nil
+}
+
+// SessionPlaylist builds the media playlist for a forensic playback session of the
+// video whose renditions are under prefix. Segment URLs are signed individually
+// (bound to clientIP where the CDN is configured to) so the viewer can't fetch the
+// other rendition and strip the watermark.
+func SessionPlaylist(ctx context.Context, store storage.Store, cf *cdn.CloudFront, prefix string, session *domain.PlaybackSession, clientIP string) ([]byte, error) {
+	prefix = strings.Trim(prefix, "/")
+	src, err := readPlaylist(ctx, store, path.Join(prefix, VariantA, PlaylistName))
+	if err != nil {
+		return nil, fmt.Errorf("load playlist: %w", err)
+	}
+
+	expiresIn := time.Until(session.ExpiresAt)
+	return RewritePlaylist(src, uint32(session.WatermarkID), func(variant, uri string) (string, error) {
+		if strings.Contains(uri, "://") {
+			return "", fmt.Errorf("absolute segment URI %q in watermarked rendition", uri)
+		}
+		key := path.Join(prefix, variant, uri)
+		if cf.CanSign() {
+			signed, _, err := cf.SignedURL(key, clientIP)
+			return signed, err
+		}
+		return store.PresignGet(ctx, key, expiresIn)
+	})
+}
+
+func readPlaylist(ctx context.Context, store storage.Store, key string) ([]byte, error) {
+	r, err := store.Open(ctx, key, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(io.LimitReader(r, maxPlaylistBytes))
+}
@@ -0,0 +1,180 @@
+package watermark
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/unicorn-sport/backend/internal/domain"
+)
+
+// ErrSessionExpired is returned for playback sessions past their expiry
+var ErrSessionExpired = errors.New("playback session expired")
+
+// maxSequenceCandidates caps how many sessions are compared against a short clip
+const maxSequenceCandidates = 50000
+
+// Service issues watermarked playback sessions and traces leaked watermarks back to them
+type Service struct {
+	db *gorm.DB
+}
+
+// NewService creates a new watermark service
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db}
+}
+
+// StartSession records a new playback of a paid asset with a fresh watermark ID
+func (s *Service) StartSession(ctx context.Context, userID uuid.UUID, assetType string, assetID uuid.UUID, forensic bool, ipAddress, userAgent string, ttl time.Duration) (*domain.PlaybackSession, error) {
+	session := domain.PlaybackSession{
+		UserID:    userID,
+		AssetType: assetType,
+		AssetID:   assetID,
+		Forensic:  forensic,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if ipAddress != "" {
+		session.IPAddress = &ipAddress
+	}
+	if userAgent != "" {
+		session.UserAgent = &userAgent
+	}
+
+	// IDs are random; retry on the rare unique-index collision
+	var err error
+	for attempt := 0; attempt < 5; attempt++ {
+		var id uint32
+		if id, err = NewID(); err != nil {
+			return nil, err
+		}
+		session.ID = uuid.New()
+		session.WatermarkID = int64(id)
+		session.WatermarkCode = Code(id)
+
+		var taken int64
+		s.db.WithContext(ctx).Model(&domain.PlaybackSession{}).Where("watermark_id = ?", session.WatermarkID).Count(&taken)
+		if taken > 0 {
+			continue
+		}
+		if err = s.db.WithContext(ctx).Create(&session).Error; err == nil {
+			return &session, nil
+		}
+	}
+	return nil, err
+}
+
+// ActiveSession loads a playback session that hasn't expired
+func (s *Service) ActiveSession(ctx context.Context, id uuid.UUID) (*domain.PlaybackSession, error) {
+	var session domain.PlaybackSession
+	if err := s.db.WithContext(ctx).First(&session, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return &session, ErrSessionExpired
+	}
+	return &session, nil
+}
+
+// LookupQuery describes what was recovered from a leaked copy. Either the visible
+// Code or the observed A/B Sequence must be set.
+type LookupQuery struct {
+	Code         string
+	Sequence     string // "ABBA?B..." one letter per segment, ? for unreadable
+	StartSegment *int   // index of the first observed segment, when known
+	AssetType    string
+	AssetID      *uuid.UUID
+}
+
+// LookupMatch is a playback session consistent with the leaked watermark
+type LookupMatch struct {
+	Session domain.PlaybackSession `json:"session"`
+	Method  string                 `json:"method"` // code, decoded, sequence_match
+}
+
+// Lookup identifies which playback sessions (and so which accounts) a leaked
+// watermark belongs to
+func (s *Service) Lookup(ctx context.Context, q LookupQuery) ([]LookupMatch, error) {
+	db := s.db.WithContext(ctx).Preload("User")
+	scoped := func(tx *gorm.DB) *gorm.DB {
+		if q.AssetType != "" {
+			tx = tx.Where("asset_type = ?", q.AssetType)
+		}
+		if q.AssetID != nil {
+			tx = tx.Where("asset_id = ?", *q.AssetID)
+		}
+		return tx
+	}
+
+	if q.Code != "" {
+		id, err := ParseCode(q.Code)
+		if err != nil {
+			return nil, err
+		}
+		var sessions []domain.PlaybackSession
+		if err := scoped(db).Where("watermark_id = ?", int64(id)).Find(&sessions).Error; err != nil {
+			return nil, err
+		}
+		return toMatches(sessions, "code"), nil
+	}
+
+	if q.Sequence == "" {
+		return nil, errors.New("a watermark code or segment sequence is required")
+	}
+
+	start := -1
+	if q.StartSegment != nil {
+		start = *q.StartSegment
+	}
+
+	// Long enough clips decode to the ID directly
+	seq, err := normalizeSequence(q.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	if len(seq) >= FrameBits {
+		ids, err := Decode(seq, start)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			watermarkIDs := make([]int64, len(ids))
+			for i, id := range ids {
+				watermarkIDs[i] = int64(id)
+			}
+			var sessions []domain.PlaybackSession
+			if err := scoped(db).Where("watermark_id IN ? AND forensic = ?", watermarkIDs, true).Find(&sessions).Error; err != nil {
+				return nil, err
+			}
+			if len(sessions) > 0 {
+				return toMatches(sessions, "decoded"), nil
+			}
+		}
+	}
+
+	// Short or damaged clips: compare against every forensic session of the asset
+	if start < 0 || q.AssetID == nil {
+		return nil, errors.New("clips shorter than 40 readable segments need start_segment and the asset")
+	}
+	var sessions []domain.PlaybackSession
+	if err := scoped(db).Where("forensic = ?", true).Order("created_at DESC").Limit(maxSequenceCandidates).Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	var matched []domain.PlaybackSession
+	for _, session := range sessions {
+		if Matches(uint32(session.WatermarkID), seq, start) {
+			matched = append(matched, session)
+		}
+	}
+	return toMatches(matched, "sequence_match"), nil
+}
+
+func toMatches(sessions []domain.PlaybackSession, method string) []LookupMatch {
+	matches := make([]LookupMatch, 0, len(sessions))
+	for _, session := range sessions {
+		matches = append(matches, LookupMatch{Session: session, Method: method})
+	}
+	return matches
+}
@@ -0,0 +1,299 @@
+package watermark
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Forensic watermarking uses A/B segment selection: every HLS segment is encoded
+// twice with imperceptibly different marks (renditions "a" and "b" stored side by
+// side), and each playback session gets its own sequence of a/b picks derived from
+// a 32-bit watermark ID. A leaked recording reveals the sequence, and so the ID.
+//
+// The ID is sent as a repeating 40-bit frame (32-bit ID + CRC-8), one bit per
+// segment, so any 40 consecutive segments identify the session even without knowing
+// where in the match the clip starts.
+
+// Variant directories under a watermarked video's prefix
+const (
+	VariantA = "a"
+	VariantB = "b"
+)
+
+// PlaylistName is the media playlist inside each variant directory
+const PlaylistName = "index.m3u8"
+
+// FrameBits is the length of one repetition of the watermark payload
+const FrameBits = 40
+
+// crockford is the Crockford base32 alphabet used for the visible watermark code
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewID returns a random non-zero watermark ID
+func NewID() (uint32, error) {
+	var b [4]byte
+	for {
+		if _, err := rand.Read(b[:]); err != nil {
+			return 0, err
+		}
+		if id := binary.BigEndian.Uint32(b[:]); id != 0 {
+			return id, nil
+		}
+	}
+}
+
+// Code renders an ID as the short code overlaid on the player (7 Crockford base32 chars)
+func Code(id uint32) string {
+	v := uint64(id)
+	out := make([]byte, 7)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[v&31]
+		v >>= 5
+	}
+	return string(out)
+}
+
+// ParseCode decodes a visible watermark code, tolerating lowercase, dashes and
+// the usual misreads (O for 0, I/L for 1)
+func ParseCode(code string) (uint32, error) {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	code = strings.NewReplacer("O", "0", "I", "1", "L", "1").Replace(code)
+	if len(code) != 7 {
+		return 0, errors.New("watermark code must be 7 characters")
+	}
+
+	var v uint64
+	for _, r := range code {
+		idx := strings.IndexRune(crockford, r)
+		if idx < 0 {
+			return 0, fmt.Errorf("invalid character %q in watermark code", r)
+		}
+		v = v<<5 | uint64(idx)
+	}
+	if v > 0xFFFFFFFF {
+		return 0, errors.New("watermark code out of range")
+	}
+	return uint32(v), nil
+}
+
+// frame returns the 40-bit payload (ID followed by its CRC-8), most significant bit first
+func frame(id uint32) uint64 {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], id)
+	return uint64(id)<<8 | uint64(crc8(b[:]))
+}
+
+// crc8 is CRC-8/SMBUS (polynomial 0x07)
+func crc8(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// VariantFor returns which rendition ("a" or "b") segment i is served from for an ID
+func VariantFor(id uint32, segment int) string {
+	bit := frame(id) >> (FrameBits - 1 - segment%FrameBits) & 1
+	if bit == 1 {
+		return VariantB
+	}
+	return VariantA
+}
+
+// Sequence returns the a/b picks for segments [start, start+n) as an "ABBA..." string
+func Sequence(id uint32, start, n int) string {
+	var sb strings.Builder
+	for i := start; i < start+n; i++ {
+		sb.WriteString(strings.ToUpper(VariantFor(id, i)))
+	}
+	return sb.String()
+}
+
+// normalizeSequence upper-cases an observed sequence and drops separators. Unknown
+// segments may be given as "?", "-" or "x".
+func normalizeSequence(observed string) (string, error) {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(observed) {
+		switch r {
+		case 'A', 'B':
+			sb.WriteRune(r)
+		case '?', '-', 'X':
+			sb.WriteRune('?')
+		case ' ', ',', '\n', '\t':
+		default:
+			return "", fmt.Errorf("invalid character %q in segment sequence (use A, B or ?)", r)
+		}
+	}
+	return sb.String(), nil
+}
+
+// Decode recovers watermark IDs from an observed a/b sequence. When start is the
+// index of the first observed segment the frame position is known; pass start < 0
+// to try every alignment. Every frame bit must be observed at least once, so at
+// least 40 consecutive segments are needed. Several candidates can come back when
+// the alignment is unknown; callers should confirm them against recorded sessions.
+func Decode(observed string, start int) ([]uint32, error) {
+	seq, err := normalizeSequence(observed)
+	if err != nil {
+		return nil, err
+	}
+	if len(seq) < FrameBits {
+		return nil, fmt.Errorf("need at least %d segments to decode a watermark, got %d", FrameBits, len(seq))
+	}
+
+	offsets := []int{}
+	if start >= 0 {
+		offsets = append(offsets, start%FrameBits)
+	} else {
+		for i := 0; i < FrameBits; i++ {
+			offsets = append(offsets, i)
+		}
+	}
+
+	var ids []uint32
+	for _, offset := range offsets {
+		if id, ok := decodeAt(seq, offset); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// decodeAt assembles the frame assuming seq[0] is frame bit `offset`
+func decodeAt(seq string, offset int) (uint32, bool) {
+	var bits [FrameBits]byte // 0 = unknown, 'A' or 'B'
+	for i := 0; i < len(seq); i++ {
+		if seq[i] == '?' {
+			continue
+		}
+		pos := (offset + i) % FrameBits
+		if bits[pos] != 0 && bits[pos] != seq[i] {
+			return 0, false // conflicting repeats: wrong alignment or not our watermark
+		}
+		bits[pos] = seq[i]
+	}
+
+	var f uint64
+	for _, b := range bits {
+		if b == 0 {
+			return 0, false
+		}
+		f <<= 1
+		if b == 'B' {
+			f |= 1
+		}
+	}
+
+	id := uint32(f >> 8)
+	if id == 0 || frame(id) != f {
+		return 0, false
+	}
+	return id, true
+}
+
+// Matches reports whether an observed sequence starting at segment start is
+// consistent with an ID. Used for clips too short to decode on their own.
+func Matches(id uint32, observed string, start int) bool {
+	seq, err := normalizeSequence(observed)
+	if err != nil || start < 0 {
+		return false
+	}
+	known := 0
+	for i := 0; i < len(seq); i++ {
+		if seq[i] == '?' {
+			continue
+		}
+		known++
+		if strings.ToUpper(VariantFor(id, start+i)) != string(seq[i]) {
+			return false
+		}
+	}
+	return known > 0
+}
+
+// ==================== PLAYLISTS ====================
+
+var mapURIPattern = regexp.MustCompile(`URI="([^"]*)"`)
+
+// SegmentURLFunc turns a segment URI from the playlist into the URL the player
+// should fetch, for the given variant
+type SegmentURLFunc func(variant, uri string) (string, error)
+
+// RewritePlaylist builds a session-specific media playlist from the variant "a"
+// playlist: segment i is pointed at rendition VariantFor(id, i). Both renditions
+// must share segment names and durations. Master playlists aren't supported, each
+// rendition is a single media playlist.
+func RewritePlaylist(src []byte, id uint32, segmentURL SegmentURLFunc) ([]byte, error) {
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	segment := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF"):
+			return nil, errors.New("watermarked renditions must be media playlists, not master playlists")
+
+		case strings.HasPrefix(line, "#EXT-X-MAP:"):
+			// Init section (fMP4) is identical in both renditions
+			var rewriteErr error
+			line = mapURIPattern.ReplaceAllStringFunc(line, func(m string) string {
+				uri := mapURIPattern.FindStringSubmatch(m)[1]
+				u, err := segmentURL(VariantA, uri)
+				if err != nil {
+					rewriteErr = err
+				}
+				return `URI="` + u + `"`
+			})
+			if rewriteErr != nil {
+				return nil, rewriteErr
+			}
+
+		case line != "" && !strings.HasPrefix(line, "#"):
+			u, err := segmentURL(VariantFor(id, segment), line)
+			if err != nil {
+				return nil, err
+			}
+			line = u
+			segment++
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if segment == 0 {
+		return nil, errors.New("playlist has no segments")
+	}
+	return out.Bytes(), nil
+}
+
+// CountSegments returns how many media segments a playlist lists
+func CountSegments(src []byte) int {
+	n := 0
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,14 @@
+-- Migration 011: Upload integrity validation
+-- Adds: declared checksum, sniffed content type and failure reason to upload sessions
+
+ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS checksum_sha256 VARCHAR(128);
+ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS detected_content_type VARCHAR(100);
+ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS failure_reason TEXT;
+ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP;
+
+COMMENT ON COLUMN upload_sessions.checksum_sha256 IS 'Optional client-declared SHA-256 of the whole file (hex or base64)';
+COMMENT ON COLUMN upload_sessions.detected_content_type IS 'MIME type sniffed from the stored object';
+COMMENT ON COLUMN upload_sessions.failure_reason IS 'Why verification failed (size, type or checksum mismatch)';
+
+-- Speeds up session lookup when a video/highlight is saved by storage key
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_s3_key ON upload_sessions(s3_key);
@@ -0,0 +1,13 @@
+-- Migration 012: Malware scanning for uploads
+-- Documents and images are uploaded under quarantine/ and only copied to their
+-- final key once the scanner reports them clean.
+
+ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS scan_status VARCHAR(20);
+ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS scan_signature VARCHAR(255);
+ALTER TABLE upload_sessions ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMP;
+
+COMMENT ON COLUMN upload_sessions.scan_status IS 'clean or infected; NULL when the upload type is not scanned';
+COMMENT ON COLUMN upload_sessions.scan_signature IS 'Malware signature reported by the scanner';
+
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_scan_status ON upload_sessions(scan_status);
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_entity ON upload_sessions(entity_type, entity_id);
@@ -0,0 +1,26 @@
+-- Migration 013: Forensic watermarking of paid playback
+-- Every stream of a full match gets a watermark ID. It is shown as a visible code and,
+-- when A/B renditions exist, encoded in which rendition each HLS segment is served from.
+
+ALTER TABLE match_videos ADD COLUMN IF NOT EXISTS watermark_prefix TEXT;
+
+COMMENT ON COLUMN match_videos.watermark_prefix IS 'Storage prefix holding a/index.m3u8 and b/index.m3u8 renditions for A/B watermarking';
+
+CREATE TABLE IF NOT EXISTS playback_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    asset_type VARCHAR(30) NOT NULL, -- match_video, video
+    asset_id UUID NOT NULL,
+    watermark_id BIGINT NOT NULL,
+    watermark_code VARCHAR(16) NOT NULL,
+    forensic BOOLEAN DEFAULT FALSE,
+    ip_address VARCHAR(64),
+    user_agent TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_playback_sessions_watermark_id ON playback_sessions(watermark_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_playback_sessions_watermark_code ON playback_sessions(watermark_code);
+CREATE INDEX IF NOT EXISTS idx_playback_sessions_user_id ON playback_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_playback_asset ON playback_sessions(asset_type, asset_id);
@@ -0,0 +1,23 @@
+-- Migration 014: Free previews of locked full matches
+-- Users without access can watch a preview (admin-picked clip or a window of the
+-- HLS stream). Preview plays are tracked for preview-to-purchase conversion.
+
+ALTER TABLE match_videos ADD COLUMN IF NOT EXISTS preview_key TEXT;
+ALTER TABLE match_videos ADD COLUMN IF NOT EXISTS preview_start_seconds INTEGER DEFAULT 0;
+ALTER TABLE match_videos ADD COLUMN IF NOT EXISTS preview_duration_seconds INTEGER DEFAULT 0;
+
+COMMENT ON COLUMN match_videos.preview_key IS 'Storage key of a pre-cut preview clip; NULL = window of the HLS stream';
+COMMENT ON COLUMN match_videos.preview_duration_seconds IS 'Preview length; 0 = default (3 minutes)';
+
+CREATE TABLE IF NOT EXISTS match_preview_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    match_video_id UUID NOT NULL REFERENCES match_videos(id) ON DELETE CASCADE,
+    viewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    ip_hash VARCHAR(64),
+    source VARCHAR(50),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_preview_views_match_video_id ON match_preview_views(match_video_id);
+CREATE INDEX IF NOT EXISTS idx_match_preview_views_viewer_id ON match_preview_views(viewer_id);
+CREATE INDEX IF NOT EXISTS idx_match_preview_views_created_at ON match_preview_views(created_at);
@@ -0,0 +1,19 @@
+-- Migration 015: Scout notes on match videos
+-- Private timestamped notes ("34:10 great switch of play by #8"), optionally tied
+-- to a player in the match lineup. Shown on the scout's saved player entry.
+
+CREATE TABLE IF NOT EXISTS match_video_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    match_id UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    match_video_id UUID NOT NULL REFERENCES match_videos(id) ON DELETE CASCADE,
+    player_id UUID REFERENCES players(id) ON DELETE SET NULL,
+    timestamp_seconds INTEGER NOT NULL CHECK (timestamp_seconds >= 0),
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_video_notes_match_id ON match_video_notes(match_id);
+CREATE INDEX IF NOT EXISTS idx_match_video_notes_match_video_id ON match_video_notes(match_video_id);
+CREATE INDEX IF NOT EXISTS idx_match_note_user_player ON match_video_notes(user_id, player_id);
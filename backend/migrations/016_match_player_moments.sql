@@ -0,0 +1,21 @@
+-- Migration 016: Player appearance index within match videos
+-- Time ranges of a full match video in which a player is involved, tagged during
+-- clipping sessions or imported from CSV. Powers "watch only this player".
+
+CREATE TABLE IF NOT EXISTS match_player_moments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    match_id UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    match_video_id UUID NOT NULL REFERENCES match_videos(id) ON DELETE CASCADE,
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    start_seconds INTEGER NOT NULL CHECK (start_seconds >= 0),
+    end_seconds INTEGER NOT NULL,
+    label VARCHAR(100),
+    source VARCHAR(20) DEFAULT 'clipping',
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CHECK (end_seconds > start_seconds)
+);
+
+CREATE INDEX IF NOT EXISTS idx_moment_match_player ON match_player_moments(match_id, player_id);
+CREATE INDEX IF NOT EXISTS idx_match_player_moments_match_video_id ON match_player_moments(match_video_id);
@@ -0,0 +1,38 @@
+-- Migration 017: Unique view counting and daily view rollups
+-- Raw views keep a salted IP hash for unique counting. Rollups are rebuilt from the
+-- raw tables by the API and feed player_highlights.view_count.
+
+ALTER TABLE video_views ADD COLUMN IF NOT EXISTS source VARCHAR(50);
+ALTER TABLE video_views ADD COLUMN IF NOT EXISTS ip_hash VARCHAR(64);
+
+-- view_count now comes from the rollups; the per-insert trigger would double count
+DROP TRIGGER IF EXISTS trigger_increment_highlight_views ON highlight_views;
+DROP FUNCTION IF EXISTS increment_highlight_view_count();
+
+-- View count refreshes aren't edits, so they leave updated_at alone
+CREATE OR REPLACE FUNCTION update_player_highlights_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (to_jsonb(NEW) - 'view_count' - 'updated_at') = (to_jsonb(OLD) - 'view_count' - 'updated_at') THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE IF NOT EXISTS view_rollups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    day DATE NOT NULL,
+    asset_type VARCHAR(20) NOT NULL,
+    asset_id UUID NOT NULL,
+    player_id UUID REFERENCES players(id) ON DELETE SET NULL,
+    views INTEGER DEFAULT 0,
+    unique_viewers INTEGER DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_view_rollup_unique ON view_rollups(day, asset_type, asset_id);
+CREATE INDEX IF NOT EXISTS idx_view_rollup_player ON view_rollups(player_id, day);
+
+COMMENT ON TABLE view_rollups IS 'Daily views per asset (highlight, video), rebuilt from highlight_views/video_views';
@@ -0,0 +1,24 @@
+-- Migration 018: Player profile views and the player dashboard
+-- Views of player profiles keep the viewer's organization type and country. The
+-- viewer's identity is only shown to the player when the scout has opted in.
+
+ALTER TABLE scouts ADD COLUMN IF NOT EXISTS share_identity_with_players BOOLEAN DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS profile_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    viewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    viewer_role VARCHAR(20),
+    organization_type VARCHAR(100),
+    country VARCHAR(100),
+    identity_shared BOOLEAN DEFAULT FALSE,
+    source VARCHAR(50),
+    ip_hash VARCHAR(64),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_profile_views_player_id ON profile_views(player_id);
+CREATE INDEX IF NOT EXISTS idx_profile_views_viewer_id ON profile_views(viewer_id);
+CREATE INDEX IF NOT EXISTS idx_profile_views_created_at ON profile_views(created_at);
+
+COMMENT ON COLUMN profile_views.identity_shared IS 'Scout had opted in to show their identity to players at the time of the view';
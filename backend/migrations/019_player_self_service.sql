@@ -0,0 +1,33 @@
+-- Migration 019: Player self-service
+-- Admin-created player accounts must replace their temporary password on first
+-- login, and profile edits by players wait in an admin approval queue.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN DEFAULT FALSE;
+
+-- Player accounts that never logged in still have the temporary password
+UPDATE users SET must_change_password = TRUE WHERE role = 'player' AND last_login_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS player_change_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    submitted_by UUID NOT NULL REFERENCES users(id),
+    school_name VARCHAR(255),
+    city VARCHAR(100),
+    height_cm INTEGER,
+    secondary_positions TEXT,
+    photo_url TEXT,
+    note TEXT,
+    status VARCHAR(20) DEFAULT 'pending',
+    review_note TEXT,
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_change_requests_player_id ON player_change_requests(player_id);
+CREATE INDEX IF NOT EXISTS idx_player_change_requests_status ON player_change_requests(status);
+
+-- One open request per player
+CREATE UNIQUE INDEX IF NOT EXISTS idx_player_change_requests_pending
+    ON player_change_requests(player_id) WHERE status = 'pending';
@@ -0,0 +1,51 @@
+-- Migration 020: Guardians and consent records
+-- Minor players are only published once a guardian has consented, against the
+-- current version of each consent form.
+
+CREATE TABLE IF NOT EXISTS guardians (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    email VARCHAR(255),
+    phone VARCHAR(50),
+    address TEXT,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS player_guardians (
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    guardian_id UUID NOT NULL REFERENCES guardians(id) ON DELETE CASCADE,
+    relationship VARCHAR(30) NOT NULL DEFAULT 'parent',
+    is_primary BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (player_id, guardian_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_guardians_guardian_id ON player_guardians(guardian_id);
+
+CREATE TABLE IF NOT EXISTS consent_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    guardian_id UUID REFERENCES guardians(id),
+    consent_type VARCHAR(30) NOT NULL,
+    version VARCHAR(20) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    signed_at TIMESTAMP NOT NULL,
+    evidence_url TEXT,
+    notes TEXT,
+    recorded_by UUID NOT NULL REFERENCES users(id),
+    withdrawn_at TIMESTAMP,
+    withdrawn_by UUID REFERENCES users(id),
+    withdrawal_reason TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_consent_records_player_id ON consent_records(player_id);
+CREATE INDEX IF NOT EXISTS idx_consent_records_guardian_id ON consent_records(guardian_id);
+CREATE INDEX IF NOT EXISTS idx_consent_records_consent_type ON consent_records(consent_type);
+
+-- Publication checks look for a current, unwithdrawn consent per player and type
+CREATE INDEX IF NOT EXISTS idx_consent_records_active
+    ON consent_records(player_id, consent_type, version) WHERE withdrawn_at IS NULL;
@@ -0,0 +1,23 @@
+-- Migration 021: Access audit trail for minors' personal data
+-- Non-admin reads of a minor's profile, full match footage and contact requests,
+-- and every export, get one row per minor for safeguarding review.
+
+CREATE TABLE IF NOT EXISTS minor_access_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_role VARCHAR(20) NOT NULL,
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    action VARCHAR(50) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id UUID,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_minor_access_user ON minor_access_logs(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_minor_access_player ON minor_access_logs(player_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_minor_access_logs_action ON minor_access_logs(action);
+CREATE INDEX IF NOT EXISTS idx_minor_access_logs_created_at ON minor_access_logs(created_at);
+
+COMMENT ON TABLE minor_access_logs IS 'Who read or exported which minor''s data; players are minors at the time of access';
@@ -0,0 +1,55 @@
+-- Migration 022: Player verification workflow
+-- Identity documents go through a review queue (claim, approve, reject, request
+-- more information) with every step kept in the verification history. Decisions
+-- notify the player and whoever submitted the document.
+
+CREATE TABLE IF NOT EXISTS player_verifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    doc_type VARCHAR(50) NOT NULL,
+    doc_url TEXT NOT NULL,
+    status VARCHAR(30) DEFAULT 'submitted',
+    submitted_by UUID NOT NULL REFERENCES users(id),
+    claimed_by UUID REFERENCES users(id),
+    claimed_at TIMESTAMP,
+    reason TEXT,
+    decided_by UUID REFERENCES users(id),
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_verifications_player_id ON player_verifications(player_id);
+CREATE INDEX IF NOT EXISTS idx_player_verifications_status ON player_verifications(status);
+CREATE INDEX IF NOT EXISTS idx_player_verifications_claimed_by ON player_verifications(claimed_by);
+
+-- One open submission per player
+CREATE UNIQUE INDEX IF NOT EXISTS idx_player_verifications_open
+    ON player_verifications(player_id) WHERE status IN ('submitted', 'in_review', 'more_info_requested');
+
+CREATE TABLE IF NOT EXISTS verification_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    verification_id UUID REFERENCES player_verifications(id) ON DELETE CASCADE,
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    action VARCHAR(30) NOT NULL,
+    actor_id UUID NOT NULL REFERENCES users(id),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_events_verification_id ON verification_events(verification_id);
+CREATE INDEX IF NOT EXISTS idx_verification_events_player_id ON verification_events(player_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT,
+    link TEXT,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_created_at ON notifications(created_at);
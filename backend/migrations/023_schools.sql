@@ -0,0 +1,69 @@
+-- Migration 023: Partner schools
+-- Schools vouch for their students' enrollment and date of birth. School staff
+-- sign in with the new 'school' role, confirm or dispute students in their
+-- attestation queue, and can register a group from a CSV roster.
+
+-- The role check was created inline by 001 and named by GORM on AutoMigrate
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users ADD CONSTRAINT chk_users_role CHECK (role IN ('admin', 'scout', 'player', 'school'));
+
+CREATE TABLE IF NOT EXISTS schools (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    state VARCHAR(100),
+    city VARCHAR(100),
+    address TEXT,
+    phone VARCHAR(50),
+    email VARCHAR(255),
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_schools_name ON schools(name);
+CREATE INDEX IF NOT EXISTS idx_schools_country ON schools(country);
+
+CREATE TABLE IF NOT EXISTS school_admins (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    school_id UUID NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_school_admins_school_id ON school_admins(school_id);
+
+ALTER TABLE players ADD COLUMN IF NOT EXISTS school_id UUID REFERENCES schools(id) ON DELETE SET NULL;
+ALTER TABLE players ADD COLUMN IF NOT EXISTS verification_source VARCHAR(20);
+CREATE INDEX IF NOT EXISTS idx_players_school_id ON players(school_id);
+
+-- Everything verified so far was set by an admin or approved from a document
+UPDATE players p SET verification_source = CASE
+        WHEN EXISTS (SELECT 1 FROM player_verifications v WHERE v.player_id = p.id AND v.status = 'approved') THEN 'document'
+        ELSE 'admin'
+    END
+WHERE verification_status = 'verified' AND verification_source IS NULL;
+
+CREATE TABLE IF NOT EXISTS school_attestations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    school_id UUID NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    status VARCHAR(20) DEFAULT 'pending',
+    dispute_reason VARCHAR(30),
+    reported_date_of_birth DATE,
+    note TEXT,
+    requested_by UUID NOT NULL REFERENCES users(id),
+    attested_by UUID REFERENCES users(id),
+    attested_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_school_attestations_school_id ON school_attestations(school_id);
+CREATE INDEX IF NOT EXISTS idx_school_attestations_player_id ON school_attestations(player_id);
+CREATE INDEX IF NOT EXISTS idx_school_attestations_status ON school_attestations(status);
+
+-- One pending attestation per player
+CREATE UNIQUE INDEX IF NOT EXISTS idx_school_attestations_pending
+    ON school_attestations(player_id) WHERE status = 'pending';
@@ -0,0 +1,29 @@
+-- Migration 024: Age groups and date-of-birth checks
+-- Tournaments can be limited to an age group, with ages taken on a cut-off date.
+-- Automated checks flag players whose age data looks wrong for admins to review.
+
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS age_group VARCHAR(10);
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS age_cutoff_date DATE;
+
+CREATE TABLE IF NOT EXISTS player_flags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    related_player_id UUID REFERENCES players(id) ON DELETE CASCADE,
+    details TEXT,
+    status VARCHAR(20) DEFAULT 'open',
+    resolved_by UUID REFERENCES users(id),
+    resolved_at TIMESTAMP,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_flags_player_id ON player_flags(player_id);
+CREATE INDEX IF NOT EXISTS idx_player_flags_type ON player_flags(type);
+CREATE INDEX IF NOT EXISTS idx_player_flags_status ON player_flags(status);
+
+-- Earlier confirmations didn't keep the date of birth the school saw; assume the current one
+UPDATE school_attestations a SET reported_date_of_birth = p.date_of_birth
+FROM players p
+WHERE p.id = a.player_id AND a.status = 'confirmed' AND a.reported_date_of_birth IS NULL;
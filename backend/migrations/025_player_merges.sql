@@ -0,0 +1,39 @@
+-- Migration 025: Duplicate players and merges
+-- Players who attend several tournaments often get created once per tournament.
+-- Admins review candidate duplicates and merge them into one record; each merge
+-- keeps the rows it moved so it can be undone.
+
+CREATE TABLE IF NOT EXISTS player_merges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    survivor_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    merged_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    moved_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    deactivated_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) DEFAULT 'merged',
+    merged_by UUID NOT NULL REFERENCES users(id),
+    undone_by UUID REFERENCES users(id),
+    undone_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_merges_survivor_id ON player_merges(survivor_id);
+CREATE INDEX IF NOT EXISTS idx_player_merges_merged_id ON player_merges(merged_id);
+CREATE INDEX IF NOT EXISTS idx_player_merges_status ON player_merges(status);
+
+CREATE TABLE IF NOT EXISTS player_merge_rows (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    merge_id UUID NOT NULL REFERENCES player_merges(id) ON DELETE CASCADE,
+    source VARCHAR(50) NOT NULL,
+    row_id UUID NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_merge_rows_merge_id ON player_merge_rows(merge_id);
+
+CREATE TABLE IF NOT EXISTS player_duplicate_dismissals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    other_player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    dismissed_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (player_id, other_player_id)
+);
@@ -0,0 +1,40 @@
+-- Migration 026: Tournament registrations
+-- players.tournament_id holds one tournament, so players who attended several were
+-- re-created per event. Registrations record every tournament a player entered.
+
+CREATE TABLE IF NOT EXISTS tournament_registrations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tournament_id UUID NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    squad VARCHAR(255),
+    shirt_number INTEGER,
+    status VARCHAR(20) DEFAULT 'registered',
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (tournament_id, player_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_registrations_player_id ON tournament_registrations(player_id);
+CREATE INDEX IF NOT EXISTS idx_tournament_registrations_status ON tournament_registrations(status);
+
+-- The tournament each player was created for
+INSERT INTO tournament_registrations (tournament_id, player_id, status, created_by, created_at, updated_at)
+SELECT p.tournament_id, p.id, 'registered', p.created_by, p.created_at, NOW()
+FROM players p
+JOIN tournaments t ON t.id = p.tournament_id
+ON CONFLICT (tournament_id, player_id) DO NOTHING;
+
+-- Every tournament a player appeared in a match of, with their most used shirt number.
+-- jersey_number has only been added by AutoMigrate so far.
+ALTER TABLE match_players ADD COLUMN IF NOT EXISTS jersey_number INTEGER;
+
+INSERT INTO tournament_registrations (tournament_id, player_id, shirt_number, status, created_at, updated_at)
+SELECT m.tournament_id, mp.player_id, MODE() WITHIN GROUP (ORDER BY mp.jersey_number), 'participated', MIN(mp.created_at), NOW()
+FROM match_players mp
+JOIN matches m ON m.id = mp.match_id
+WHERE m.tournament_id IS NOT NULL
+GROUP BY m.tournament_id, mp.player_id
+ON CONFLICT (tournament_id, player_id) DO UPDATE
+SET status = 'participated',
+    shirt_number = COALESCE(tournament_registrations.shirt_number, EXCLUDED.shirt_number);
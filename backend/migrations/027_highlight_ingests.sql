@@ -0,0 +1,41 @@
+-- Migration 027: Bulk highlight ingestion
+-- Media teams upload a tournament's clips in one go from a manifest. Each clip gets
+-- a presigned upload; the highlights are created once the uploads check out.
+
+CREATE TABLE IF NOT EXISTS highlight_ingests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    manifest_name VARCHAR(255),
+    status VARCHAR(20) DEFAULT 'uploading',
+    item_count INTEGER DEFAULT 0,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_highlight_ingests_status ON highlight_ingests(status);
+CREATE INDEX IF NOT EXISTS idx_highlight_ingests_created_by ON highlight_ingests(created_by);
+
+CREATE TABLE IF NOT EXISTS highlight_ingest_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ingest_id UUID NOT NULL REFERENCES highlight_ingests(id) ON DELETE CASCADE,
+    manifest_row INTEGER NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    match_id UUID REFERENCES matches(id) ON DELETE SET NULL,
+    highlight_type VARCHAR(50) NOT NULL,
+    timestamp_in_match INTEGER,
+    title VARCHAR(255),
+    description TEXT,
+    upload_session_id UUID NOT NULL REFERENCES upload_sessions(id) ON DELETE CASCADE,
+    s3_key TEXT NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending',
+    error TEXT,
+    highlight_id UUID REFERENCES player_highlights(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_highlight_ingest_items_ingest_id ON highlight_ingest_items(ingest_id);
+CREATE INDEX IF NOT EXISTS idx_highlight_ingest_items_player_id ON highlight_ingest_items(player_id);
+CREATE INDEX IF NOT EXISTS idx_highlight_ingest_items_status ON highlight_ingest_items(status);
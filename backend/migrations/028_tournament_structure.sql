@@ -0,0 +1,44 @@
+-- Migration 028: Tournament structure
+-- Groups and teams, generated round-robin and knockout fixtures, and knockout
+-- matches linked to the match their winner plays next.
+
+CREATE TABLE IF NOT EXISTS tournament_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tournament_id UUID NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    position INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_groups_tournament_id ON tournament_groups(tournament_id);
+
+CREATE TABLE IF NOT EXISTS teams (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tournament_id UUID NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    academy_id UUID REFERENCES academies(id) ON DELETE SET NULL,
+    group_id UUID REFERENCES tournament_groups(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    short_name VARCHAR(50),
+    seed INTEGER,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_teams_tournament_id ON teams(tournament_id);
+CREATE INDEX IF NOT EXISTS idx_teams_academy_id ON teams(academy_id);
+CREATE INDEX IF NOT EXISTS idx_teams_group_id ON teams(group_id);
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS home_team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS away_team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS group_id UUID REFERENCES tournament_groups(id) ON DELETE SET NULL;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS round INTEGER;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS bracket_slot INTEGER;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS next_match_id UUID REFERENCES matches(id) ON DELETE SET NULL;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS next_match_side VARCHAR(10);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS home_penalties INTEGER;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS away_penalties INTEGER;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS winner_team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_matches_home_team_id ON matches(home_team_id);
+CREATE INDEX IF NOT EXISTS idx_matches_away_team_id ON matches(away_team_id);
+CREATE INDEX IF NOT EXISTS idx_matches_group_id ON matches(group_id);
@@ -0,0 +1,11 @@
+-- Migration 029: Tournament standings and leaderboards
+-- Group tables and player leaderboards are cached per tournament as JSON and
+-- recomputed whenever a result changes.
+
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS tie_breakers VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS tournament_stats (
+    tournament_id UUID PRIMARY KEY REFERENCES tournaments(id) ON DELETE CASCADE,
+    data JSONB NOT NULL,
+    computed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,44 @@
+-- Migration 030: Matches reference teams
+-- home_team/away_team were free text, so a match player's side and team records
+-- couldn't be worked out. Every side named in a tournament's matches becomes a
+-- team, and match players record the team they played for.
+
+ALTER TABLE match_players ADD COLUMN IF NOT EXISTS team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_match_players_team_id ON match_players(team_id);
+
+-- A team for each side name not already entered, linked to the academy of the same name
+INSERT INTO teams (tournament_id, academy_id, name, created_at, updated_at)
+SELECT s.tournament_id,
+       (SELECT a.id FROM academies a WHERE LOWER(a.name) = LOWER(s.name) LIMIT 1),
+       s.name, NOW(), NOW()
+FROM (
+    SELECT DISTINCT ON (tournament_id, LOWER(name)) tournament_id, name
+    FROM (
+        SELECT tournament_id, TRIM(home_team) AS name FROM matches
+        UNION ALL
+        SELECT tournament_id, TRIM(away_team) FROM matches
+    ) sides
+    WHERE tournament_id IS NOT NULL AND COALESCE(name, '') <> ''
+    ORDER BY tournament_id, LOWER(name), name
+) s
+WHERE NOT EXISTS (
+    SELECT 1 FROM teams t WHERE t.tournament_id = s.tournament_id AND LOWER(t.name) = LOWER(s.name)
+);
+
+UPDATE matches m SET home_team_id = t.id, home_team = t.name
+FROM teams t
+WHERE m.home_team_id IS NULL AND t.tournament_id = m.tournament_id AND LOWER(t.name) = LOWER(TRIM(m.home_team));
+
+UPDATE matches m SET away_team_id = t.id, away_team = t.name
+FROM teams t
+WHERE m.away_team_id IS NULL AND t.tournament_id = m.tournament_id AND LOWER(t.name) = LOWER(TRIM(m.away_team));
+
+-- A player's side is the team from their academy, where exactly one side is
+UPDATE match_players mp SET team_id = t.id
+FROM matches m, players p, teams t
+WHERE m.id = mp.match_id AND p.id = mp.player_id AND mp.team_id IS NULL
+  AND t.id IN (m.home_team_id, m.away_team_id) AND t.academy_id = p.academy_id
+  AND (SELECT COUNT(*) FROM teams s WHERE s.id IN (m.home_team_id, m.away_team_id) AND s.academy_id = p.academy_id) = 1;
+
+-- Cached stats predate team records, so they are recomputed on next use
+DELETE FROM tournament_stats;
@@ -0,0 +1,7 @@
+-- Migration 031: Forensic watermarking of full matches in the video library
+-- Full-match videos streamed through /videos/:id/stream get the same per-session
+-- A/B segment selection as match videos once their renditions are set.
+
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS watermark_prefix TEXT;
+
+COMMENT ON COLUMN videos.watermark_prefix IS 'Storage prefix holding a/index.m3u8 and b/index.m3u8 renditions for A/B watermarking';
@@ -6,6 +6,7 @@ export interface Player {
   last_name_init?: string
   date_of_birth?: string
   age?: number
+  age_group?: string
   position: string
   preferred_foot?: 'left' | 'right' | 'both'
   height_cm?: number